@@ -0,0 +1,1350 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: okra/v1/okra.proto
+
+// The okra gRPC API: the uom catalog, parsing uoms out of text and converting amounts.
+//
+// Calls act on the tenant named in the x-tenant-id metadata, or on the global catalog
+// without it. With auth enabled, credentials go in the x-api-key or authorization
+// ("Bearer <jwt>") metadata, exactly as over REST.
+//
+// Amounts are exact decimal or fraction strings: "2", "0.25", "1/3" or "1 1/3".
+
+package okrav1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PluralNames holds a name per CLDR plural category: zero, one, two, few, many or other
+type PluralNames struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         map[string]string      `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluralNames) Reset() {
+	*x = PluralNames{}
+	mi := &file_okra_v1_okra_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluralNames) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluralNames) ProtoMessage() {}
+
+func (x *PluralNames) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluralNames.ProtoReflect.Descriptor instead.
+func (*PluralNames) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PluralNames) GetNames() map[string]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type UomLocale struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ShortNames         *PluralNames           `protobuf:"bytes,1,opt,name=short_names,json=shortNames,proto3" json:"short_names,omitempty"`
+	FullNames          *PluralNames           `protobuf:"bytes,2,opt,name=full_names,json=fullNames,proto3" json:"full_names,omitempty"`
+	DisambiguatedNames *PluralNames           `protobuf:"bytes,3,opt,name=disambiguated_names,json=disambiguatedNames,proto3" json:"disambiguated_names,omitempty"`
+	MatchNamesRecipe   []string               `protobuf:"bytes,4,rep,name=match_names_recipe,json=matchNamesRecipe,proto3" json:"match_names_recipe,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UomLocale) Reset() {
+	*x = UomLocale{}
+	mi := &file_okra_v1_okra_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UomLocale) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UomLocale) ProtoMessage() {}
+
+func (x *UomLocale) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UomLocale.ProtoReflect.Descriptor instead.
+func (*UomLocale) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UomLocale) GetShortNames() *PluralNames {
+	if x != nil {
+		return x.ShortNames
+	}
+	return nil
+}
+
+func (x *UomLocale) GetFullNames() *PluralNames {
+	if x != nil {
+		return x.FullNames
+	}
+	return nil
+}
+
+func (x *UomLocale) GetDisambiguatedNames() *PluralNames {
+	if x != nil {
+		return x.DisambiguatedNames
+	}
+	return nil
+}
+
+func (x *UomLocale) GetMatchNamesRecipe() []string {
+	if x != nil {
+		return x.MatchNamesRecipe
+	}
+	return nil
+}
+
+// UomFields are the editable fields of a uom. Optional fields are left unset when
+// they don't apply.
+type UomFields struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	Label                     string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Enabled                   bool                   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	MeasureType               string                 `protobuf:"bytes,3,opt,name=measure_type,json=measureType,proto3" json:"measure_type,omitempty"` // volume, weight, count, ...
+	Group                     *string                `protobuf:"bytes,4,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	GroupMin                  *string                `protobuf:"bytes,5,opt,name=group_min,json=groupMin,proto3,oneof" json:"group_min,omitempty"`
+	GroupMax                  *string                `protobuf:"bytes,6,opt,name=group_max,json=groupMax,proto3,oneof" json:"group_max,omitempty"`
+	SnapAmount                []string               `protobuf:"bytes,7,rep,name=snap_amount,json=snapAmount,proto3" json:"snap_amount,omitempty"`
+	SnapSelect                *string                `protobuf:"bytes,8,opt,name=snap_select,json=snapSelect,proto3,oneof" json:"snap_select,omitempty"`
+	PivotRatio                *string                `protobuf:"bytes,9,opt,name=pivot_ratio,json=pivotRatio,proto3,oneof" json:"pivot_ratio,omitempty"`
+	PackageAmount             *string                `protobuf:"bytes,10,opt,name=package_amount,json=packageAmount,proto3,oneof" json:"package_amount,omitempty"`
+	PackageUom                *string                `protobuf:"bytes,11,opt,name=package_uom,json=packageUom,proto3,oneof" json:"package_uom,omitempty"`
+	MatchNamesRecipe          []string               `protobuf:"bytes,12,rep,name=match_names_recipe,json=matchNamesRecipe,proto3" json:"match_names_recipe,omitempty"`
+	MatchNamesFoodLabel       []string               `protobuf:"bytes,13,rep,name=match_names_food_label,json=matchNamesFoodLabel,proto3" json:"match_names_food_label,omitempty"`
+	DefaultNameType           string                 `protobuf:"bytes,14,opt,name=default_name_type,json=defaultNameType,proto3" json:"default_name_type,omitempty"`
+	ShortNameSingular         *string                `protobuf:"bytes,15,opt,name=short_name_singular,json=shortNameSingular,proto3,oneof" json:"short_name_singular,omitempty"`
+	ShortNamePlural           *string                `protobuf:"bytes,16,opt,name=short_name_plural,json=shortNamePlural,proto3,oneof" json:"short_name_plural,omitempty"`
+	FullNameSingular          *string                `protobuf:"bytes,17,opt,name=full_name_singular,json=fullNameSingular,proto3,oneof" json:"full_name_singular,omitempty"`
+	FullNamePlural            *string                `protobuf:"bytes,18,opt,name=full_name_plural,json=fullNamePlural,proto3,oneof" json:"full_name_plural,omitempty"`
+	DisambiguatedNameSingular *string                `protobuf:"bytes,19,opt,name=disambiguated_name_singular,json=disambiguatedNameSingular,proto3,oneof" json:"disambiguated_name_singular,omitempty"`
+	DisambiguatedNamePlural   *string                `protobuf:"bytes,20,opt,name=disambiguated_name_plural,json=disambiguatedNamePlural,proto3,oneof" json:"disambiguated_name_plural,omitempty"`
+	Systems                   []string               `protobuf:"bytes,21,rep,name=systems,proto3" json:"systems,omitempty"`
+	NameGroup                 *string                `protobuf:"bytes,22,opt,name=name_group,json=nameGroup,proto3,oneof" json:"name_group,omitempty"`
+	Locales                   map[string]*UomLocale  `protobuf:"bytes,23,rep,name=locales,proto3" json:"locales,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // keyed by BCP 47 tag, e.g. "es" or "fr-CA"
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *UomFields) Reset() {
+	*x = UomFields{}
+	mi := &file_okra_v1_okra_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UomFields) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UomFields) ProtoMessage() {}
+
+func (x *UomFields) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UomFields.ProtoReflect.Descriptor instead.
+func (*UomFields) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UomFields) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *UomFields) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *UomFields) GetMeasureType() string {
+	if x != nil {
+		return x.MeasureType
+	}
+	return ""
+}
+
+func (x *UomFields) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *UomFields) GetGroupMin() string {
+	if x != nil && x.GroupMin != nil {
+		return *x.GroupMin
+	}
+	return ""
+}
+
+func (x *UomFields) GetGroupMax() string {
+	if x != nil && x.GroupMax != nil {
+		return *x.GroupMax
+	}
+	return ""
+}
+
+func (x *UomFields) GetSnapAmount() []string {
+	if x != nil {
+		return x.SnapAmount
+	}
+	return nil
+}
+
+func (x *UomFields) GetSnapSelect() string {
+	if x != nil && x.SnapSelect != nil {
+		return *x.SnapSelect
+	}
+	return ""
+}
+
+func (x *UomFields) GetPivotRatio() string {
+	if x != nil && x.PivotRatio != nil {
+		return *x.PivotRatio
+	}
+	return ""
+}
+
+func (x *UomFields) GetPackageAmount() string {
+	if x != nil && x.PackageAmount != nil {
+		return *x.PackageAmount
+	}
+	return ""
+}
+
+func (x *UomFields) GetPackageUom() string {
+	if x != nil && x.PackageUom != nil {
+		return *x.PackageUom
+	}
+	return ""
+}
+
+func (x *UomFields) GetMatchNamesRecipe() []string {
+	if x != nil {
+		return x.MatchNamesRecipe
+	}
+	return nil
+}
+
+func (x *UomFields) GetMatchNamesFoodLabel() []string {
+	if x != nil {
+		return x.MatchNamesFoodLabel
+	}
+	return nil
+}
+
+func (x *UomFields) GetDefaultNameType() string {
+	if x != nil {
+		return x.DefaultNameType
+	}
+	return ""
+}
+
+func (x *UomFields) GetShortNameSingular() string {
+	if x != nil && x.ShortNameSingular != nil {
+		return *x.ShortNameSingular
+	}
+	return ""
+}
+
+func (x *UomFields) GetShortNamePlural() string {
+	if x != nil && x.ShortNamePlural != nil {
+		return *x.ShortNamePlural
+	}
+	return ""
+}
+
+func (x *UomFields) GetFullNameSingular() string {
+	if x != nil && x.FullNameSingular != nil {
+		return *x.FullNameSingular
+	}
+	return ""
+}
+
+func (x *UomFields) GetFullNamePlural() string {
+	if x != nil && x.FullNamePlural != nil {
+		return *x.FullNamePlural
+	}
+	return ""
+}
+
+func (x *UomFields) GetDisambiguatedNameSingular() string {
+	if x != nil && x.DisambiguatedNameSingular != nil {
+		return *x.DisambiguatedNameSingular
+	}
+	return ""
+}
+
+func (x *UomFields) GetDisambiguatedNamePlural() string {
+	if x != nil && x.DisambiguatedNamePlural != nil {
+		return *x.DisambiguatedNamePlural
+	}
+	return ""
+}
+
+func (x *UomFields) GetSystems() []string {
+	if x != nil {
+		return x.Systems
+	}
+	return nil
+}
+
+func (x *UomFields) GetNameGroup() string {
+	if x != nil && x.NameGroup != nil {
+		return *x.NameGroup
+	}
+	return ""
+}
+
+func (x *UomFields) GetLocales() map[string]*UomLocale {
+	if x != nil {
+		return x.Locales
+	}
+	return nil
+}
+
+type Uom struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Fields        *UomFields             `protobuf:"bytes,2,opt,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Uom) Reset() {
+	*x = Uom{}
+	mi := &file_okra_v1_okra_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Uom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Uom) ProtoMessage() {}
+
+func (x *Uom) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Uom.ProtoReflect.Descriptor instead.
+func (*Uom) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Uom) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Uom) GetFields() *UomFields {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type CreateUomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uom           *UomFields             `protobuf:"bytes,1,opt,name=uom,proto3" json:"uom,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUomRequest) Reset() {
+	*x = CreateUomRequest{}
+	mi := &file_okra_v1_okra_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUomRequest) ProtoMessage() {}
+
+func (x *CreateUomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUomRequest.ProtoReflect.Descriptor instead.
+func (*CreateUomRequest) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateUomRequest) GetUom() *UomFields {
+	if x != nil {
+		return x.Uom
+	}
+	return nil
+}
+
+type GetUomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUomRequest) Reset() {
+	*x = GetUomRequest{}
+	mi := &file_okra_v1_okra_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUomRequest) ProtoMessage() {}
+
+func (x *GetUomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUomRequest.ProtoReflect.Descriptor instead.
+func (*GetUomRequest) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetUomRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetUomByLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUomByLabelRequest) Reset() {
+	*x = GetUomByLabelRequest{}
+	mi := &file_okra_v1_okra_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUomByLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUomByLabelRequest) ProtoMessage() {}
+
+func (x *GetUomByLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUomByLabelRequest.ProtoReflect.Descriptor instead.
+func (*GetUomByLabelRequest) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetUomByLabelRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type ListUomsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	System        string                 `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"` // Optional: only the uoms of this system, e.g. "metric"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUomsRequest) Reset() {
+	*x = ListUomsRequest{}
+	mi := &file_okra_v1_okra_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUomsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUomsRequest) ProtoMessage() {}
+
+func (x *ListUomsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUomsRequest.ProtoReflect.Descriptor instead.
+func (*ListUomsRequest) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListUomsRequest) GetSystem() string {
+	if x != nil {
+		return x.System
+	}
+	return ""
+}
+
+type ListUomsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uoms          []*Uom                 `protobuf:"bytes,1,rep,name=uoms,proto3" json:"uoms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUomsResponse) Reset() {
+	*x = ListUomsResponse{}
+	mi := &file_okra_v1_okra_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUomsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUomsResponse) ProtoMessage() {}
+
+func (x *ListUomsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUomsResponse.ProtoReflect.Descriptor instead.
+func (*ListUomsResponse) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListUomsResponse) GetUoms() []*Uom {
+	if x != nil {
+		return x.Uoms
+	}
+	return nil
+}
+
+type UpdateUomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uom           *UomFields             `protobuf:"bytes,2,opt,name=uom,proto3" json:"uom,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUomRequest) Reset() {
+	*x = UpdateUomRequest{}
+	mi := &file_okra_v1_okra_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUomRequest) ProtoMessage() {}
+
+func (x *UpdateUomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUomRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUomRequest) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateUomRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateUomRequest) GetUom() *UomFields {
+	if x != nil {
+		return x.Uom
+	}
+	return nil
+}
+
+type DeleteUomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUomRequest) Reset() {
+	*x = DeleteUomRequest{}
+	mi := &file_okra_v1_okra_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUomRequest) ProtoMessage() {}
+
+func (x *DeleteUomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUomRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUomRequest) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteUomRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteUomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUomResponse) Reset() {
+	*x = DeleteUomResponse{}
+	mi := &file_okra_v1_okra_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUomResponse) ProtoMessage() {}
+
+func (x *DeleteUomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUomResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUomResponse) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{11}
+}
+
+type ParseUomsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Locales       []string               `protobuf:"bytes,2,rep,name=locales,proto3" json:"locales,omitempty"` // Optional: preferred locales, most preferred first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseUomsRequest) Reset() {
+	*x = ParseUomsRequest{}
+	mi := &file_okra_v1_okra_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseUomsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseUomsRequest) ProtoMessage() {}
+
+func (x *ParseUomsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseUomsRequest.ProtoReflect.Descriptor instead.
+func (*ParseUomsRequest) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ParseUomsRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ParseUomsRequest) GetLocales() []string {
+	if x != nil {
+		return x.Locales
+	}
+	return nil
+}
+
+type UomMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uom           *Uom                   `protobuf:"bytes,1,opt,name=uom,proto3" json:"uom,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`    // the match name that was found
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`    // the text it matched
+	Start         int32                  `protobuf:"varint,4,opt,name=start,proto3" json:"start,omitempty"` // byte offsets in the request text
+	End           int32                  `protobuf:"varint,5,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UomMatch) Reset() {
+	*x = UomMatch{}
+	mi := &file_okra_v1_okra_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UomMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UomMatch) ProtoMessage() {}
+
+func (x *UomMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UomMatch.ProtoReflect.Descriptor instead.
+func (*UomMatch) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UomMatch) GetUom() *Uom {
+	if x != nil {
+		return x.Uom
+	}
+	return nil
+}
+
+func (x *UomMatch) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UomMatch) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *UomMatch) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *UomMatch) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type ParseUomsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*UomMatch            `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseUomsResponse) Reset() {
+	*x = ParseUomsResponse{}
+	mi := &file_okra_v1_okra_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseUomsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseUomsResponse) ProtoMessage() {}
+
+func (x *ParseUomsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseUomsResponse.ProtoReflect.Descriptor instead.
+func (*ParseUomsResponse) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ParseUomsResponse) GetMatches() []*UomMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type ConvertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        string                 `protobuf:"bytes,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Max           *string                `protobuf:"bytes,2,opt,name=max,proto3,oneof" json:"max,omitempty"` // upper bound of a range
+	Approximate   bool                   `protobuf:"varint,3,opt,name=approximate,proto3" json:"approximate,omitempty"`
+	From          string                 `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`       // uom id, label or match name
+	To            string                 `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`           // uom id, label or match name
+	System        string                 `protobuf:"bytes,6,opt,name=system,proto3" json:"system,omitempty"`   // used when to is empty: pick the best uom in this system
+	Locales       []string               `protobuf:"bytes,7,rep,name=locales,proto3" json:"locales,omitempty"` // Optional: locales the result text is formatted for
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_okra_v1_okra_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ConvertRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetMax() string {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+func (x *ConvertRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetSystem() string {
+	if x != nil {
+		return x.System
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetLocales() []string {
+	if x != nil {
+		return x.Locales
+	}
+	return nil
+}
+
+type Quantity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        string                 `protobuf:"bytes,1,opt,name=amount,proto3" json:"amount,omitempty"` // lower bound of a range
+	Max           *string                `protobuf:"bytes,2,opt,name=max,proto3,oneof" json:"max,omitempty"` // upper bound of a range
+	Approximate   bool                   `protobuf:"varint,3,opt,name=approximate,proto3" json:"approximate,omitempty"`
+	Qualitative   string                 `protobuf:"bytes,4,opt,name=qualitative,proto3" json:"qualitative,omitempty"` // e.g. "to taste"; amount is then empty
+	UomId         string                 `protobuf:"bytes,5,opt,name=uom_id,json=uomId,proto3" json:"uom_id,omitempty"`
+	Uom           string                 `protobuf:"bytes,6,opt,name=uom,proto3" json:"uom,omitempty"`   // uom label
+	Text          string                 `protobuf:"bytes,7,opt,name=text,proto3" json:"text,omitempty"` // formatted for the requested locales
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quantity) Reset() {
+	*x = Quantity{}
+	mi := &file_okra_v1_okra_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quantity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quantity) ProtoMessage() {}
+
+func (x *Quantity) ProtoReflect() protoreflect.Message {
+	mi := &file_okra_v1_okra_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quantity.ProtoReflect.Descriptor instead.
+func (*Quantity) Descriptor() ([]byte, []int) {
+	return file_okra_v1_okra_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Quantity) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Quantity) GetMax() string {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return ""
+}
+
+func (x *Quantity) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+func (x *Quantity) GetQualitative() string {
+	if x != nil {
+		return x.Qualitative
+	}
+	return ""
+}
+
+func (x *Quantity) GetUomId() string {
+	if x != nil {
+		return x.UomId
+	}
+	return ""
+}
+
+func (x *Quantity) GetUom() string {
+	if x != nil {
+		return x.Uom
+	}
+	return ""
+}
+
+func (x *Quantity) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+var File_okra_v1_okra_proto protoreflect.FileDescriptor
+
+const file_okra_v1_okra_proto_rawDesc = "" +
+	"\n" +
+	"\x12okra/v1/okra.proto\x12\aokra.v1\"~\n" +
+	"\vPluralNames\x125\n" +
+	"\x05names\x18\x01 \x03(\v2\x1f.okra.v1.PluralNames.NamesEntryR\x05names\x1a8\n" +
+	"\n" +
+	"NamesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xec\x01\n" +
+	"\tUomLocale\x125\n" +
+	"\vshort_names\x18\x01 \x01(\v2\x14.okra.v1.PluralNamesR\n" +
+	"shortNames\x123\n" +
+	"\n" +
+	"full_names\x18\x02 \x01(\v2\x14.okra.v1.PluralNamesR\tfullNames\x12E\n" +
+	"\x13disambiguated_names\x18\x03 \x01(\v2\x14.okra.v1.PluralNamesR\x12disambiguatedNames\x12,\n" +
+	"\x12match_names_recipe\x18\x04 \x03(\tR\x10matchNamesRecipe\"\xb2\n" +
+	"\n" +
+	"\tUomFields\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\x12!\n" +
+	"\fmeasure_type\x18\x03 \x01(\tR\vmeasureType\x12\x19\n" +
+	"\x05group\x18\x04 \x01(\tH\x00R\x05group\x88\x01\x01\x12 \n" +
+	"\tgroup_min\x18\x05 \x01(\tH\x01R\bgroupMin\x88\x01\x01\x12 \n" +
+	"\tgroup_max\x18\x06 \x01(\tH\x02R\bgroupMax\x88\x01\x01\x12\x1f\n" +
+	"\vsnap_amount\x18\a \x03(\tR\n" +
+	"snapAmount\x12$\n" +
+	"\vsnap_select\x18\b \x01(\tH\x03R\n" +
+	"snapSelect\x88\x01\x01\x12$\n" +
+	"\vpivot_ratio\x18\t \x01(\tH\x04R\n" +
+	"pivotRatio\x88\x01\x01\x12*\n" +
+	"\x0epackage_amount\x18\n" +
+	" \x01(\tH\x05R\rpackageAmount\x88\x01\x01\x12$\n" +
+	"\vpackage_uom\x18\v \x01(\tH\x06R\n" +
+	"packageUom\x88\x01\x01\x12,\n" +
+	"\x12match_names_recipe\x18\f \x03(\tR\x10matchNamesRecipe\x123\n" +
+	"\x16match_names_food_label\x18\r \x03(\tR\x13matchNamesFoodLabel\x12*\n" +
+	"\x11default_name_type\x18\x0e \x01(\tR\x0fdefaultNameType\x123\n" +
+	"\x13short_name_singular\x18\x0f \x01(\tH\aR\x11shortNameSingular\x88\x01\x01\x12/\n" +
+	"\x11short_name_plural\x18\x10 \x01(\tH\bR\x0fshortNamePlural\x88\x01\x01\x121\n" +
+	"\x12full_name_singular\x18\x11 \x01(\tH\tR\x10fullNameSingular\x88\x01\x01\x12-\n" +
+	"\x10full_name_plural\x18\x12 \x01(\tH\n" +
+	"R\x0efullNamePlural\x88\x01\x01\x12C\n" +
+	"\x1bdisambiguated_name_singular\x18\x13 \x01(\tH\vR\x19disambiguatedNameSingular\x88\x01\x01\x12?\n" +
+	"\x19disambiguated_name_plural\x18\x14 \x01(\tH\fR\x17disambiguatedNamePlural\x88\x01\x01\x12\x18\n" +
+	"\asystems\x18\x15 \x03(\tR\asystems\x12\"\n" +
+	"\n" +
+	"name_group\x18\x16 \x01(\tH\rR\tnameGroup\x88\x01\x01\x129\n" +
+	"\alocales\x18\x17 \x03(\v2\x1f.okra.v1.UomFields.LocalesEntryR\alocales\x1aN\n" +
+	"\fLocalesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
+	"\x05value\x18\x02 \x01(\v2\x12.okra.v1.UomLocaleR\x05value:\x028\x01B\b\n" +
+	"\x06_groupB\f\n" +
+	"\n" +
+	"_group_minB\f\n" +
+	"\n" +
+	"_group_maxB\x0e\n" +
+	"\f_snap_selectB\x0e\n" +
+	"\f_pivot_ratioB\x11\n" +
+	"\x0f_package_amountB\x0e\n" +
+	"\f_package_uomB\x16\n" +
+	"\x14_short_name_singularB\x14\n" +
+	"\x12_short_name_pluralB\x15\n" +
+	"\x13_full_name_singularB\x13\n" +
+	"\x11_full_name_pluralB\x1e\n" +
+	"\x1c_disambiguated_name_singularB\x1c\n" +
+	"\x1a_disambiguated_name_pluralB\r\n" +
+	"\v_name_group\"A\n" +
+	"\x03Uom\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
+	"\x06fields\x18\x02 \x01(\v2\x12.okra.v1.UomFieldsR\x06fields\"8\n" +
+	"\x10CreateUomRequest\x12$\n" +
+	"\x03uom\x18\x01 \x01(\v2\x12.okra.v1.UomFieldsR\x03uom\"\x1f\n" +
+	"\rGetUomRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\",\n" +
+	"\x14GetUomByLabelRequest\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\")\n" +
+	"\x0fListUomsRequest\x12\x16\n" +
+	"\x06system\x18\x01 \x01(\tR\x06system\"4\n" +
+	"\x10ListUomsResponse\x12 \n" +
+	"\x04uoms\x18\x01 \x03(\v2\f.okra.v1.UomR\x04uoms\"H\n" +
+	"\x10UpdateUomRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12$\n" +
+	"\x03uom\x18\x02 \x01(\v2\x12.okra.v1.UomFieldsR\x03uom\"\"\n" +
+	"\x10DeleteUomRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x13\n" +
+	"\x11DeleteUomResponse\"@\n" +
+	"\x10ParseUomsRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x18\n" +
+	"\alocales\x18\x02 \x03(\tR\alocales\"z\n" +
+	"\bUomMatch\x12\x1e\n" +
+	"\x03uom\x18\x01 \x01(\v2\f.okra.v1.UomR\x03uom\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12\x14\n" +
+	"\x05start\x18\x04 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x05 \x01(\x05R\x03end\"@\n" +
+	"\x11ParseUomsResponse\x12+\n" +
+	"\amatches\x18\x01 \x03(\v2\x11.okra.v1.UomMatchR\amatches\"\xbf\x01\n" +
+	"\x0eConvertRequest\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\tR\x06amount\x12\x15\n" +
+	"\x03max\x18\x02 \x01(\tH\x00R\x03max\x88\x01\x01\x12 \n" +
+	"\vapproximate\x18\x03 \x01(\bR\vapproximate\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x05 \x01(\tR\x02to\x12\x16\n" +
+	"\x06system\x18\x06 \x01(\tR\x06system\x12\x18\n" +
+	"\alocales\x18\a \x03(\tR\alocalesB\x06\n" +
+	"\x04_max\"\xc2\x01\n" +
+	"\bQuantity\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\tR\x06amount\x12\x15\n" +
+	"\x03max\x18\x02 \x01(\tH\x00R\x03max\x88\x01\x01\x12 \n" +
+	"\vapproximate\x18\x03 \x01(\bR\vapproximate\x12 \n" +
+	"\vqualitative\x18\x04 \x01(\tR\vqualitative\x12\x15\n" +
+	"\x06uom_id\x18\x05 \x01(\tR\x05uomId\x12\x10\n" +
+	"\x03uom\x18\x06 \x01(\tR\x03uom\x12\x12\n" +
+	"\x04text\x18\a \x01(\tR\x04textB\x06\n" +
+	"\x04_max2\xe6\x03\n" +
+	"\n" +
+	"UomService\x124\n" +
+	"\tCreateUom\x12\x19.okra.v1.CreateUomRequest\x1a\f.okra.v1.Uom\x12.\n" +
+	"\x06GetUom\x12\x16.okra.v1.GetUomRequest\x1a\f.okra.v1.Uom\x12<\n" +
+	"\rGetUomByLabel\x12\x1d.okra.v1.GetUomByLabelRequest\x1a\f.okra.v1.Uom\x12?\n" +
+	"\bListUoms\x12\x18.okra.v1.ListUomsRequest\x1a\x19.okra.v1.ListUomsResponse\x124\n" +
+	"\tUpdateUom\x12\x19.okra.v1.UpdateUomRequest\x1a\f.okra.v1.Uom\x12B\n" +
+	"\tDeleteUom\x12\x19.okra.v1.DeleteUomRequest\x1a\x1a.okra.v1.DeleteUomResponse\x12B\n" +
+	"\tParseUoms\x12\x19.okra.v1.ParseUomsRequest\x1a\x1a.okra.v1.ParseUomsResponse\x125\n" +
+	"\aConvert\x12\x17.okra.v1.ConvertRequest\x1a\x11.okra.v1.QuantityB4Z2github.com/jeffjlins/okra/api/proto/okra/v1;okrav1b\x06proto3"
+
+var (
+	file_okra_v1_okra_proto_rawDescOnce sync.Once
+	file_okra_v1_okra_proto_rawDescData []byte
+)
+
+func file_okra_v1_okra_proto_rawDescGZIP() []byte {
+	file_okra_v1_okra_proto_rawDescOnce.Do(func() {
+		file_okra_v1_okra_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_okra_v1_okra_proto_rawDesc), len(file_okra_v1_okra_proto_rawDesc)))
+	})
+	return file_okra_v1_okra_proto_rawDescData
+}
+
+var file_okra_v1_okra_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_okra_v1_okra_proto_goTypes = []any{
+	(*PluralNames)(nil),          // 0: okra.v1.PluralNames
+	(*UomLocale)(nil),            // 1: okra.v1.UomLocale
+	(*UomFields)(nil),            // 2: okra.v1.UomFields
+	(*Uom)(nil),                  // 3: okra.v1.Uom
+	(*CreateUomRequest)(nil),     // 4: okra.v1.CreateUomRequest
+	(*GetUomRequest)(nil),        // 5: okra.v1.GetUomRequest
+	(*GetUomByLabelRequest)(nil), // 6: okra.v1.GetUomByLabelRequest
+	(*ListUomsRequest)(nil),      // 7: okra.v1.ListUomsRequest
+	(*ListUomsResponse)(nil),     // 8: okra.v1.ListUomsResponse
+	(*UpdateUomRequest)(nil),     // 9: okra.v1.UpdateUomRequest
+	(*DeleteUomRequest)(nil),     // 10: okra.v1.DeleteUomRequest
+	(*DeleteUomResponse)(nil),    // 11: okra.v1.DeleteUomResponse
+	(*ParseUomsRequest)(nil),     // 12: okra.v1.ParseUomsRequest
+	(*UomMatch)(nil),             // 13: okra.v1.UomMatch
+	(*ParseUomsResponse)(nil),    // 14: okra.v1.ParseUomsResponse
+	(*ConvertRequest)(nil),       // 15: okra.v1.ConvertRequest
+	(*Quantity)(nil),             // 16: okra.v1.Quantity
+	nil,                          // 17: okra.v1.PluralNames.NamesEntry
+	nil,                          // 18: okra.v1.UomFields.LocalesEntry
+}
+var file_okra_v1_okra_proto_depIdxs = []int32{
+	17, // 0: okra.v1.PluralNames.names:type_name -> okra.v1.PluralNames.NamesEntry
+	0,  // 1: okra.v1.UomLocale.short_names:type_name -> okra.v1.PluralNames
+	0,  // 2: okra.v1.UomLocale.full_names:type_name -> okra.v1.PluralNames
+	0,  // 3: okra.v1.UomLocale.disambiguated_names:type_name -> okra.v1.PluralNames
+	18, // 4: okra.v1.UomFields.locales:type_name -> okra.v1.UomFields.LocalesEntry
+	2,  // 5: okra.v1.Uom.fields:type_name -> okra.v1.UomFields
+	2,  // 6: okra.v1.CreateUomRequest.uom:type_name -> okra.v1.UomFields
+	3,  // 7: okra.v1.ListUomsResponse.uoms:type_name -> okra.v1.Uom
+	2,  // 8: okra.v1.UpdateUomRequest.uom:type_name -> okra.v1.UomFields
+	3,  // 9: okra.v1.UomMatch.uom:type_name -> okra.v1.Uom
+	13, // 10: okra.v1.ParseUomsResponse.matches:type_name -> okra.v1.UomMatch
+	1,  // 11: okra.v1.UomFields.LocalesEntry.value:type_name -> okra.v1.UomLocale
+	4,  // 12: okra.v1.UomService.CreateUom:input_type -> okra.v1.CreateUomRequest
+	5,  // 13: okra.v1.UomService.GetUom:input_type -> okra.v1.GetUomRequest
+	6,  // 14: okra.v1.UomService.GetUomByLabel:input_type -> okra.v1.GetUomByLabelRequest
+	7,  // 15: okra.v1.UomService.ListUoms:input_type -> okra.v1.ListUomsRequest
+	9,  // 16: okra.v1.UomService.UpdateUom:input_type -> okra.v1.UpdateUomRequest
+	10, // 17: okra.v1.UomService.DeleteUom:input_type -> okra.v1.DeleteUomRequest
+	12, // 18: okra.v1.UomService.ParseUoms:input_type -> okra.v1.ParseUomsRequest
+	15, // 19: okra.v1.UomService.Convert:input_type -> okra.v1.ConvertRequest
+	3,  // 20: okra.v1.UomService.CreateUom:output_type -> okra.v1.Uom
+	3,  // 21: okra.v1.UomService.GetUom:output_type -> okra.v1.Uom
+	3,  // 22: okra.v1.UomService.GetUomByLabel:output_type -> okra.v1.Uom
+	8,  // 23: okra.v1.UomService.ListUoms:output_type -> okra.v1.ListUomsResponse
+	3,  // 24: okra.v1.UomService.UpdateUom:output_type -> okra.v1.Uom
+	11, // 25: okra.v1.UomService.DeleteUom:output_type -> okra.v1.DeleteUomResponse
+	14, // 26: okra.v1.UomService.ParseUoms:output_type -> okra.v1.ParseUomsResponse
+	16, // 27: okra.v1.UomService.Convert:output_type -> okra.v1.Quantity
+	20, // [20:28] is the sub-list for method output_type
+	12, // [12:20] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_okra_v1_okra_proto_init() }
+func file_okra_v1_okra_proto_init() {
+	if File_okra_v1_okra_proto != nil {
+		return
+	}
+	file_okra_v1_okra_proto_msgTypes[2].OneofWrappers = []any{}
+	file_okra_v1_okra_proto_msgTypes[15].OneofWrappers = []any{}
+	file_okra_v1_okra_proto_msgTypes[16].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_okra_v1_okra_proto_rawDesc), len(file_okra_v1_okra_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_okra_v1_okra_proto_goTypes,
+		DependencyIndexes: file_okra_v1_okra_proto_depIdxs,
+		MessageInfos:      file_okra_v1_okra_proto_msgTypes,
+	}.Build()
+	File_okra_v1_okra_proto = out.File
+	file_okra_v1_okra_proto_goTypes = nil
+	file_okra_v1_okra_proto_depIdxs = nil
+}
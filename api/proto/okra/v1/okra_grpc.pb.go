@@ -0,0 +1,399 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: okra/v1/okra.proto
+
+// The okra gRPC API: the uom catalog, parsing uoms out of text and converting amounts.
+//
+// Calls act on the tenant named in the x-tenant-id metadata, or on the global catalog
+// without it. With auth enabled, credentials go in the x-api-key or authorization
+// ("Bearer <jwt>") metadata, exactly as over REST.
+//
+// Amounts are exact decimal or fraction strings: "2", "0.25", "1/3" or "1 1/3".
+
+package okrav1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UomService_CreateUom_FullMethodName     = "/okra.v1.UomService/CreateUom"
+	UomService_GetUom_FullMethodName        = "/okra.v1.UomService/GetUom"
+	UomService_GetUomByLabel_FullMethodName = "/okra.v1.UomService/GetUomByLabel"
+	UomService_ListUoms_FullMethodName      = "/okra.v1.UomService/ListUoms"
+	UomService_UpdateUom_FullMethodName     = "/okra.v1.UomService/UpdateUom"
+	UomService_DeleteUom_FullMethodName     = "/okra.v1.UomService/DeleteUom"
+	UomService_ParseUoms_FullMethodName     = "/okra.v1.UomService/ParseUoms"
+	UomService_Convert_FullMethodName       = "/okra.v1.UomService/Convert"
+)
+
+// UomServiceClient is the client API for UomService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UomServiceClient interface {
+	CreateUom(ctx context.Context, in *CreateUomRequest, opts ...grpc.CallOption) (*Uom, error)
+	GetUom(ctx context.Context, in *GetUomRequest, opts ...grpc.CallOption) (*Uom, error)
+	GetUomByLabel(ctx context.Context, in *GetUomByLabelRequest, opts ...grpc.CallOption) (*Uom, error)
+	ListUoms(ctx context.Context, in *ListUomsRequest, opts ...grpc.CallOption) (*ListUomsResponse, error)
+	UpdateUom(ctx context.Context, in *UpdateUomRequest, opts ...grpc.CallOption) (*Uom, error)
+	DeleteUom(ctx context.Context, in *DeleteUomRequest, opts ...grpc.CallOption) (*DeleteUomResponse, error)
+	// ParseUoms finds the uoms named in free text, e.g. "2 Tbsp. butter"
+	ParseUoms(ctx context.Context, in *ParseUomsRequest, opts ...grpc.CallOption) (*ParseUomsResponse, error)
+	// Convert expresses an amount in another uom, or in the most natural uom of a system
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*Quantity, error)
+}
+
+type uomServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUomServiceClient(cc grpc.ClientConnInterface) UomServiceClient {
+	return &uomServiceClient{cc}
+}
+
+func (c *uomServiceClient) CreateUom(ctx context.Context, in *CreateUomRequest, opts ...grpc.CallOption) (*Uom, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Uom)
+	err := c.cc.Invoke(ctx, UomService_CreateUom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uomServiceClient) GetUom(ctx context.Context, in *GetUomRequest, opts ...grpc.CallOption) (*Uom, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Uom)
+	err := c.cc.Invoke(ctx, UomService_GetUom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uomServiceClient) GetUomByLabel(ctx context.Context, in *GetUomByLabelRequest, opts ...grpc.CallOption) (*Uom, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Uom)
+	err := c.cc.Invoke(ctx, UomService_GetUomByLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uomServiceClient) ListUoms(ctx context.Context, in *ListUomsRequest, opts ...grpc.CallOption) (*ListUomsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUomsResponse)
+	err := c.cc.Invoke(ctx, UomService_ListUoms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uomServiceClient) UpdateUom(ctx context.Context, in *UpdateUomRequest, opts ...grpc.CallOption) (*Uom, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Uom)
+	err := c.cc.Invoke(ctx, UomService_UpdateUom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uomServiceClient) DeleteUom(ctx context.Context, in *DeleteUomRequest, opts ...grpc.CallOption) (*DeleteUomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUomResponse)
+	err := c.cc.Invoke(ctx, UomService_DeleteUom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uomServiceClient) ParseUoms(ctx context.Context, in *ParseUomsRequest, opts ...grpc.CallOption) (*ParseUomsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParseUomsResponse)
+	err := c.cc.Invoke(ctx, UomService_ParseUoms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uomServiceClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*Quantity, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Quantity)
+	err := c.cc.Invoke(ctx, UomService_Convert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UomServiceServer is the server API for UomService service.
+// All implementations must embed UnimplementedUomServiceServer
+// for forward compatibility.
+type UomServiceServer interface {
+	CreateUom(context.Context, *CreateUomRequest) (*Uom, error)
+	GetUom(context.Context, *GetUomRequest) (*Uom, error)
+	GetUomByLabel(context.Context, *GetUomByLabelRequest) (*Uom, error)
+	ListUoms(context.Context, *ListUomsRequest) (*ListUomsResponse, error)
+	UpdateUom(context.Context, *UpdateUomRequest) (*Uom, error)
+	DeleteUom(context.Context, *DeleteUomRequest) (*DeleteUomResponse, error)
+	// ParseUoms finds the uoms named in free text, e.g. "2 Tbsp. butter"
+	ParseUoms(context.Context, *ParseUomsRequest) (*ParseUomsResponse, error)
+	// Convert expresses an amount in another uom, or in the most natural uom of a system
+	Convert(context.Context, *ConvertRequest) (*Quantity, error)
+	mustEmbedUnimplementedUomServiceServer()
+}
+
+// UnimplementedUomServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUomServiceServer struct{}
+
+func (UnimplementedUomServiceServer) CreateUom(context.Context, *CreateUomRequest) (*Uom, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUom not implemented")
+}
+func (UnimplementedUomServiceServer) GetUom(context.Context, *GetUomRequest) (*Uom, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUom not implemented")
+}
+func (UnimplementedUomServiceServer) GetUomByLabel(context.Context, *GetUomByLabelRequest) (*Uom, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUomByLabel not implemented")
+}
+func (UnimplementedUomServiceServer) ListUoms(context.Context, *ListUomsRequest) (*ListUomsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUoms not implemented")
+}
+func (UnimplementedUomServiceServer) UpdateUom(context.Context, *UpdateUomRequest) (*Uom, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUom not implemented")
+}
+func (UnimplementedUomServiceServer) DeleteUom(context.Context, *DeleteUomRequest) (*DeleteUomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUom not implemented")
+}
+func (UnimplementedUomServiceServer) ParseUoms(context.Context, *ParseUomsRequest) (*ParseUomsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ParseUoms not implemented")
+}
+func (UnimplementedUomServiceServer) Convert(context.Context, *ConvertRequest) (*Quantity, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (UnimplementedUomServiceServer) mustEmbedUnimplementedUomServiceServer() {}
+func (UnimplementedUomServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeUomServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UomServiceServer will
+// result in compilation errors.
+type UnsafeUomServiceServer interface {
+	mustEmbedUnimplementedUomServiceServer()
+}
+
+func RegisterUomServiceServer(s grpc.ServiceRegistrar, srv UomServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUomServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UomService_ServiceDesc, srv)
+}
+
+func _UomService_CreateUom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UomServiceServer).CreateUom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UomService_CreateUom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UomServiceServer).CreateUom(ctx, req.(*CreateUomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UomService_GetUom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UomServiceServer).GetUom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UomService_GetUom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UomServiceServer).GetUom(ctx, req.(*GetUomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UomService_GetUomByLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUomByLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UomServiceServer).GetUomByLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UomService_GetUomByLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UomServiceServer).GetUomByLabel(ctx, req.(*GetUomByLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UomService_ListUoms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUomsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UomServiceServer).ListUoms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UomService_ListUoms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UomServiceServer).ListUoms(ctx, req.(*ListUomsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UomService_UpdateUom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UomServiceServer).UpdateUom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UomService_UpdateUom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UomServiceServer).UpdateUom(ctx, req.(*UpdateUomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UomService_DeleteUom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UomServiceServer).DeleteUom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UomService_DeleteUom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UomServiceServer).DeleteUom(ctx, req.(*DeleteUomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UomService_ParseUoms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseUomsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UomServiceServer).ParseUoms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UomService_ParseUoms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UomServiceServer).ParseUoms(ctx, req.(*ParseUomsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UomService_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UomServiceServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UomService_Convert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UomServiceServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UomService_ServiceDesc is the grpc.ServiceDesc for UomService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UomService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "okra.v1.UomService",
+	HandlerType: (*UomServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUom",
+			Handler:    _UomService_CreateUom_Handler,
+		},
+		{
+			MethodName: "GetUom",
+			Handler:    _UomService_GetUom_Handler,
+		},
+		{
+			MethodName: "GetUomByLabel",
+			Handler:    _UomService_GetUomByLabel_Handler,
+		},
+		{
+			MethodName: "ListUoms",
+			Handler:    _UomService_ListUoms_Handler,
+		},
+		{
+			MethodName: "UpdateUom",
+			Handler:    _UomService_UpdateUom_Handler,
+		},
+		{
+			MethodName: "DeleteUom",
+			Handler:    _UomService_DeleteUom_Handler,
+		},
+		{
+			MethodName: "ParseUoms",
+			Handler:    _UomService_ParseUoms_Handler,
+		},
+		{
+			MethodName: "Convert",
+			Handler:    _UomService_Convert_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "okra/v1/okra.proto",
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -25,23 +26,49 @@ func main() {
 		log.Fatalf("failed to initialize app: %v", err)
 	}
 
+	logger := app.Logger
+
 	// graceful shutdown
 	go func() {
-		log.Printf("HTTP server listening on :%s", cfg.Server.Port)
+		logger.Info("HTTP server listening", "addr", app.Server.Addr)
 		if err := app.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("server error: %v", err)
+			logger.Error("server error", "error", err)
+			os.Exit(1)
 		}
 	}()
+	if app.GRPCServer != nil {
+		go func() {
+			lis, err := net.Listen("tcp", app.GRPCAddr)
+			if err != nil {
+				logger.Error("grpc server error", "error", err)
+				os.Exit(1)
+			}
+			logger.Info("gRPC server listening", "addr", app.GRPCAddr)
+			if err := app.GRPCServer.Serve(lis); err != nil {
+				logger.Error("grpc server error", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+	if app.AdminServer != nil {
+		go func() {
+			logger.Info("admin server listening", "addr", app.AdminServer.Addr)
+			if err := app.AdminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("admin server error", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 	<-stop
 
-	log.Println("shutting down...")
+	logger.Info("shutting down")
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := app.Shutdown(shutdownCtx); err != nil {
-		log.Printf("shutdown error: %v", err)
+		logger.Error("shutdown error", "error", err)
 	}
 }
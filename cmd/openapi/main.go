@@ -0,0 +1,80 @@
+// Command openapi writes the OpenAPI spec of the REST API, as served at /openapi.json,
+// in YAML. `make openapi` uses it to regenerate api/openapi.yaml.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+
+	httpadapter "github.com/jeffjlins/okra/internal/adapters/inbound/http"
+	"go.yaml.in/yaml/v3"
+)
+
+func main() {
+	out := flag.String("o", "", "file to write the spec to, stdout when empty")
+	flag.Parse()
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeSpec(w); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeSpec writes the spec in YAML, sections in their conventional order
+func writeSpec(w io.Writer) error {
+	doc, err := httpadapter.OpenAPI()
+	if err != nil {
+		return fmt.Errorf("failed to build the OpenAPI spec: %w", err)
+	}
+	var node yaml.Node
+	if err := node.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode the OpenAPI spec: %w", err)
+	}
+	sortTopLevel(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("failed to write the OpenAPI spec: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to write the OpenAPI spec: %w", err)
+	}
+	return nil
+}
+
+// topLevelOrder is the conventional order of a spec's sections; the encoder would
+// otherwise sort them, putting components first
+var topLevelOrder = []string{"openapi", "info", "servers", "tags", "security", "paths", "components"}
+
+func sortTopLevel(node *yaml.Node) {
+	type pair struct{ key, value *yaml.Node }
+	pairs := make([]pair, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, pair{node.Content[i], node.Content[i+1]})
+	}
+	rank := func(p pair) int {
+		if i := slices.Index(topLevelOrder, p.key.Value); i >= 0 {
+			return i
+		}
+		return len(topLevelOrder)
+	}
+	slices.SortStableFunc(pairs, func(a, b pair) int { return rank(a) - rank(b) })
+
+	node.Content = node.Content[:0]
+	for _, p := range pairs {
+		node.Content = append(node.Content, p.key, p.value)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// specFile is the spec checked in for readers and client generators
+const specFile = "../../api/openapi.yaml"
+
+func TestSpecIsUpToDate(t *testing.T) {
+	var generated bytes.Buffer
+	if err := writeSpec(&generated); err != nil {
+		t.Fatal(err)
+	}
+	checkedIn, err := os.ReadFile(specFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(generated.Bytes(), checkedIn) {
+		return
+	}
+
+	want := strings.Split(generated.String(), "\n")
+	got := strings.Split(string(checkedIn), "\n")
+	for i := range max(len(want), len(got)) {
+		var w, g string
+		if i < len(want) {
+			w = want[i]
+		}
+		if i < len(got) {
+			g = got[i]
+		}
+		if w != g {
+			t.Fatalf("%s is out of date, run make openapi. First difference at line %d:\n  checked in: %q\n  generated:  %q", specFile, i+1, g, w)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+// APIKey is a static key and the principal it authenticates as
+type APIKey struct {
+	Name   string
+	Key    string
+	Role   Role
+	Tenant string // Optional: restricts the key to one tenant
+}
+
+// APIKeyAuthenticator authenticates requests carrying one of a fixed set of keys
+type APIKeyAuthenticator struct {
+	keys map[[sha256.Size]byte]*Principal // keyed by hash so lookups don't leak key prefixes through timing
+}
+
+func NewAPIKeyAuthenticator(keys []APIKey) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{keys: make(map[[sha256.Size]byte]*Principal, len(keys))}
+	for _, k := range keys {
+		if k.Name == "" {
+			return nil, errors.New("api key name is required")
+		}
+		if k.Key == "" {
+			return nil, fmt.Errorf("api key %s: key is required", k.Name)
+		}
+		if _, err := ParseRole(string(k.Role)); err != nil {
+			return nil, fmt.Errorf("api key %s: %w", k.Name, err)
+		}
+		if k.Tenant != "" {
+			if err := domain.ValidateTenant(k.Tenant); err != nil {
+				return nil, fmt.Errorf("api key %s: %w", k.Name, err)
+			}
+		}
+		hash := sha256.Sum256([]byte(k.Key))
+		if _, ok := a.keys[hash]; ok {
+			return nil, fmt.Errorf("api key %s: key is used by another api key", k.Name)
+		}
+		a.keys[hash] = &Principal{Subject: k.Name, Role: k.Role, Tenant: k.Tenant}
+	}
+	return a, nil
+}
+
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	if creds.APIKey == "" {
+		return nil, ErrNoCredentials
+	}
+	p, ok := a.keys[sha256.Sum256([]byte(creds.APIKey))]
+	if !ok {
+		return nil, errors.New("invalid api key")
+	}
+	return p, nil
+}
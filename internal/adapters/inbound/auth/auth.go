@@ -0,0 +1,92 @@
+// Package auth authenticates callers of the inbound adapters and carries who they are
+// in the request context. It is transport-agnostic: each adapter extracts Credentials
+// from its own request format and checks roles with Principal.Allows.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Role is what a caller may do. Each role includes the ones below it.
+type Role string
+
+const (
+	ROLE_READER Role = "reader" // read uoms, parse, convert, scale
+	ROLE_EDITOR Role = "editor" // also create, update and delete a tenant's uoms
+	ROLE_ADMIN  Role = "admin"  // also change the global catalog
+)
+
+var roleLevels = map[Role]int{
+	ROLE_READER: 1,
+	ROLE_EDITOR: 2,
+	ROLE_ADMIN:  3,
+}
+
+// ParseRole validates a role name
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if _, ok := roleLevels[role]; !ok {
+		return "", fmt.Errorf("unknown role %q (expected reader, editor or admin)", s)
+	}
+	return role, nil
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	Subject string // key name or token subject, for logs
+	Role    Role
+	Tenant  string // Optional: the only tenant the caller may act for. Empty means any tenant and the global catalog, so only give unbound credentials to trusted services
+}
+
+// Allows reports whether the principal's role includes required
+func (p *Principal) Allows(required Role) bool {
+	return p != nil && roleLevels[p.Role] >= roleLevels[required]
+}
+
+// Credentials are what a request presented. Both are empty for anonymous requests.
+type Credentials struct {
+	APIKey      string
+	BearerToken string
+}
+
+// ErrNoCredentials is returned by an Authenticator that found nothing it understands in the credentials
+var ErrNoCredentials = errors.New("no credentials")
+
+// Authenticator verifies credentials. It returns ErrNoCredentials when the credentials
+// aren't its kind, and any other error when they are but don't check out.
+type Authenticator interface {
+	Authenticate(ctx context.Context, creds Credentials) (*Principal, error)
+}
+
+// Chain tries each authenticator in turn and returns the first answer that isn't ErrNoCredentials
+func Chain(authenticators ...Authenticator) Authenticator {
+	return chain(authenticators)
+}
+
+type chain []Authenticator
+
+func (c chain) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(ctx, creds)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return p, err
+	}
+	return nil, ErrNoCredentials
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated caller, or nil for anonymous requests
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
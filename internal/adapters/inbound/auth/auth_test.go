@@ -0,0 +1,402 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	httpadapter "github.com/jeffjlins/okra/internal/adapters/inbound/http"
+	"github.com/jeffjlins/okra/internal/adapters/outbound/memory"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/usecase"
+	"github.com/jeffjlins/okra/pkg/okra"
+)
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// writeJWKS writes the public halves of the keys, by kid, as a JWKS file
+func writeJWKS(t *testing.T, path string, keys map[string]*ecdsa.PrivateKey) {
+	t.Helper()
+	var doc struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		point, err := key.PublicKey.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, y := point[1:33], point[33:]
+		doc.Keys = append(doc.Keys, map[string]string{
+			"kty": "EC", "crv": "P-256", "kid": kid, "use": "sig",
+			"x": base64.RawURLEncoding.EncodeToString(x),
+			"y": base64.RawURLEncoding.EncodeToString(y),
+		})
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// touch moves the file's modification time forward, so a rewrite within the clock's
+// resolution still shows as a change
+func touch(t *testing.T, path string, by time.Duration) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, time.Time{}, info.ModTime().Add(by)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	a, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Name: "ops", Key: "ops-key", Role: auth.ROLE_ADMIN},
+		{Name: "household-app", Key: "household-key", Role: auth.ROLE_EDITOR, Tenant: "household-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key     string
+		want    *auth.Principal
+		wantErr error // nil for any error when want is nil
+	}{
+		{"ops-key", &auth.Principal{Subject: "ops", Role: auth.ROLE_ADMIN}, nil},
+		{"household-key", &auth.Principal{Subject: "household-app", Role: auth.ROLE_EDITOR, Tenant: "household-1"}, nil},
+		{"ops-key ", nil, nil},
+		{"nope", nil, nil},
+		{"", nil, auth.ErrNoCredentials},
+	}
+	for _, tt := range tests {
+		got, err := a.Authenticate(context.Background(), auth.Credentials{APIKey: tt.key})
+		switch {
+		case tt.want != nil:
+			if err != nil || *got != *tt.want {
+				t.Errorf("Authenticate(%q) = %+v, %v; want %+v", tt.key, got, err, tt.want)
+			}
+		case tt.wantErr != nil:
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authenticate(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+		default:
+			if err == nil || errors.Is(err, auth.ErrNoCredentials) {
+				t.Errorf("Authenticate(%q) error = %v, want it rejected", tt.key, err)
+			}
+		}
+	}
+}
+
+func TestNewAPIKeyAuthenticatorRejects(t *testing.T) {
+	tests := map[string][]auth.APIKey{
+		"no name":        {{Key: "k", Role: auth.ROLE_READER}},
+		"no key":         {{Name: "a", Role: auth.ROLE_READER}},
+		"unknown role":   {{Name: "a", Key: "k", Role: "owner"}},
+		"invalid tenant": {{Name: "a", Key: "k", Role: auth.ROLE_READER, Tenant: "no/slashes"}},
+		"shared key":     {{Name: "a", Key: "k", Role: auth.ROLE_READER}, {Name: "b", Key: "k", Role: auth.ROLE_ADMIN}},
+	}
+	for name, keys := range tests {
+		if _, err := auth.NewAPIKeyAuthenticator(keys); err == nil {
+			t.Errorf("%s: NewAPIKeyAuthenticator succeeded, want an error", name)
+		}
+	}
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	key, other := newKey(t), newKey(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, map[string]*ecdsa.PrivateKey{"k1": key})
+	jwks, err := auth.LoadJWKS(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := auth.NewJWTAuthenticator(jwks, auth.JWTOptions{Issuer: "https://issuer.example", Audience: "okra"})
+
+	now := time.Now()
+	claims := func(extra jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub":  "user-1",
+			"iss":  "https://issuer.example",
+			"aud":  "okra",
+			"exp":  now.Add(time.Hour).Unix(),
+			"role": "reader",
+		}
+		for k, v := range extra {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+	hmac, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims(nil)).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  *auth.Principal // nil when the token must be rejected
+	}{
+		{"valid", sign(t, key, "k1", claims(nil)), &auth.Principal{Subject: "user-1", Role: auth.ROLE_READER}},
+		{"no kid with a single key", sign(t, key, "", claims(nil)), &auth.Principal{Subject: "user-1", Role: auth.ROLE_READER}},
+		{"highest of several roles", sign(t, key, "k1", claims(jwt.MapClaims{"role": []any{"reader", "billing", "editor"}})), &auth.Principal{Subject: "user-1", Role: auth.ROLE_EDITOR}},
+		{"bound to a tenant", sign(t, key, "k1", claims(jwt.MapClaims{"tenant": "household-1"})), &auth.Principal{Subject: "user-1", Role: auth.ROLE_READER, Tenant: "household-1"}},
+		{"expired", sign(t, key, "k1", claims(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()})), nil},
+		{"no expiry", sign(t, key, "k1", claims(jwt.MapClaims{"exp": nil})), nil},
+		{"not yet valid", sign(t, key, "k1", claims(jwt.MapClaims{"nbf": now.Add(time.Hour).Unix()})), nil},
+		{"wrong issuer", sign(t, key, "k1", claims(jwt.MapClaims{"iss": "https://evil.example"})), nil},
+		{"wrong audience", sign(t, key, "k1", claims(jwt.MapClaims{"aud": "other-service"})), nil},
+		{"wrong key", sign(t, other, "k1", claims(nil)), nil},
+		{"unknown kid", sign(t, key, "k2", claims(nil)), nil},
+		{"hmac", hmac, nil},
+		{"no role", sign(t, key, "k1", claims(jwt.MapClaims{"role": nil})), nil},
+		{"unknown role", sign(t, key, "k1", claims(jwt.MapClaims{"role": "owner"})), nil},
+		{"invalid tenant", sign(t, key, "k1", claims(jwt.MapClaims{"tenant": "no/slashes"})), nil},
+		{"malformed", "not.a.token", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.Authenticate(context.Background(), auth.Credentials{BearerToken: tt.token})
+			if tt.want == nil {
+				if err == nil || errors.Is(err, auth.ErrNoCredentials) {
+					t.Errorf("Authenticate = %+v, %v; want it rejected", got, err)
+				}
+				return
+			}
+			if err != nil || *got != *tt.want {
+				t.Errorf("Authenticate = %+v, %v; want %+v", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := a.Authenticate(context.Background(), auth.Credentials{}); !errors.Is(err, auth.ErrNoCredentials) {
+		t.Errorf("Authenticate without a token: error = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestJWKSReloadsOnUnknownKid(t *testing.T) {
+	old, rotated := newKey(t), newKey(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, map[string]*ecdsa.PrivateKey{"old": old})
+	jwks, err := auth.LoadJWKS(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := auth.NewJWTAuthenticator(jwks, auth.JWTOptions{})
+	claims := jwt.MapClaims{"sub": "user-1", "role": "reader", "exp": time.Now().Add(time.Hour).Unix()}
+	authenticate := func(key *ecdsa.PrivateKey, kid string) error {
+		_, err := a.Authenticate(context.Background(), auth.Credentials{BearerToken: sign(t, key, kid, claims)})
+		return err
+	}
+
+	if err := authenticate(rotated, "new"); err == nil {
+		t.Fatal("a token signed with a key the file doesn't have was accepted")
+	}
+
+	// The issuer rotates a key in: the unknown kid rereads the file
+	writeJWKS(t, path, map[string]*ecdsa.PrivateKey{"old": old, "new": rotated})
+	touch(t, path, time.Second)
+	if err := authenticate(rotated, "new"); err != nil {
+		t.Errorf("token signed with the rotated key: %v", err)
+	}
+	if err := authenticate(old, "old"); err != nil {
+		t.Errorf("token signed with the old key: %v", err)
+	}
+	if err := authenticate(rotated, "old"); err == nil {
+		t.Error("a token whose kid names the wrong key was accepted")
+	}
+
+	// A broken rewrite keeps the keys already loaded
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, path, 2*time.Second)
+	if err := authenticate(old, "other"); err == nil {
+		t.Error("a token with an unknown kid was accepted")
+	}
+	if err := authenticate(rotated, "new"); err != nil {
+		t.Errorf("token signed with the rotated key after a broken rewrite: %v", err)
+	}
+
+	// The key is retired
+	writeJWKS(t, path, map[string]*ecdsa.PrivateKey{"new": rotated})
+	touch(t, path, 3*time.Second)
+	if err := authenticate(rotated, "unknown"); err == nil {
+		t.Error("a token with an unknown kid was accepted")
+	}
+	if err := authenticate(old, "old"); err == nil {
+		t.Error("a token signed with a retired key was accepted")
+	}
+}
+
+func TestParseJWKSRejects(t *testing.T) {
+	tests := map[string]string{
+		"not json":       `{`,
+		"no keys":        `{"keys": []}`,
+		"only enc keys":  `{"keys": [{"kty": "OKP", "crv": "Ed25519", "use": "enc", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`,
+		"unknown kty":    `{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`,
+		"unknown curve":  `{"keys": [{"kty": "EC", "crv": "P-192", "x": "AA", "y": "AA"}]}`,
+		"duplicate kids": `{"keys": [{"kty": "OKP", "crv": "Ed25519", "kid": "a", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}, {"kty": "OKP", "crv": "Ed25519", "kid": "a", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`,
+	}
+	for name, data := range tests {
+		if _, err := auth.ParseJWKS([]byte(data)); err == nil {
+			t.Errorf("%s: ParseJWKS succeeded, want an error", name)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	keys, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{{Name: "ops", Key: "ops-key", Role: auth.ROLE_ADMIN}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, err := auth.ParseJWKS([]byte(`{"keys": [{"kty": "OKP", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := auth.Chain(auth.NewJWTAuthenticator(jwks, auth.JWTOptions{}), keys)
+
+	if p, err := a.Authenticate(context.Background(), auth.Credentials{APIKey: "ops-key"}); err != nil || p.Subject != "ops" {
+		t.Errorf("api key through the chain = %+v, %v", p, err)
+	}
+	if _, err := a.Authenticate(context.Background(), auth.Credentials{BearerToken: "not.a.token"}); err == nil || errors.Is(err, auth.ErrNoCredentials) {
+		t.Errorf("invalid token through the chain: error = %v, want it rejected", err)
+	}
+	if _, err := a.Authenticate(context.Background(), auth.Credentials{}); !errors.Is(err, auth.ErrNoCredentials) {
+		t.Errorf("no credentials through the chain: error = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestRoles(t *testing.T) {
+	for _, s := range []string{"reader", "editor", "admin"} {
+		if _, err := auth.ParseRole(s); err != nil {
+			t.Errorf("ParseRole(%q): %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "Admin", "owner"} {
+		if _, err := auth.ParseRole(s); err == nil {
+			t.Errorf("ParseRole(%q) succeeded, want an error", s)
+		}
+	}
+
+	editor := &auth.Principal{Role: auth.ROLE_EDITOR}
+	if !editor.Allows(auth.ROLE_READER) || !editor.Allows(auth.ROLE_EDITOR) || editor.Allows(auth.ROLE_ADMIN) {
+		t.Error("an editor should be allowed reader and editor routes but not admin ones")
+	}
+	var anonymous *auth.Principal
+	if anonymous.Allows(auth.ROLE_READER) {
+		t.Error("a nil principal was allowed a role")
+	}
+}
+
+// TestTenantScoping runs api keys through the router: callers bound to a tenant act
+// for it alone, unbound ones for any tenant they name, and anonymous public reads see
+// the global catalog whatever X-Tenant-ID says
+func TestTenantScoping(t *testing.T) {
+	keys, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Name: "ops", Key: "ops-key", Role: auth.ROLE_READER},
+		{Name: "household-app", Key: "household-key", Role: auth.ROLE_EDITOR, Tenant: "household-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uoms, err := okra.DefaultUoms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := memory.NewUomRepository(uoms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := httpadapter.NewRouter(
+		usecase.NewUomService(repo, domain.LabelIDGenerator),
+		usecase.NewConversionService(repo),
+		usecase.NewScaleService(repo),
+		usecase.NewShoppingListService(repo),
+		usecase.NewIngredientService(repo),
+		usecase.NewHealthService(repo, repo, 0),
+		httpadapter.RouterOptions{Auth: httpadapter.AuthOptions{Authenticator: keys, PublicReads: true}},
+	)
+
+	const mug = `{"label": "mug", "enabled": true, "measure_type": "volume", "snap_amount": ["1/4"],
+		"pivot_ratio": "350", "match_names_recipe": ["mug"], "default_name_type": "full",
+		"full_name_singular": "mug", "full_name_plural": "mugs"}`
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		key        string
+		tenant     string
+		body       string
+		wantStatus int
+	}{
+		{"bound key writes to its tenant", http.MethodPost, "/uom", "household-key", "", mug, http.StatusCreated},
+		{"bound key reads its tenant", http.MethodGet, "/uom/by-label/mug", "household-key", "", "", http.StatusOK},
+		{"bound key names its tenant", http.MethodGet, "/uom/by-label/mug", "household-key", "household-1", "", http.StatusOK},
+		{"bound key names another tenant", http.MethodGet, "/uom/by-label/mug", "household-key", "household-2", "", http.StatusForbidden},
+		{"unbound key names the tenant", http.MethodGet, "/uom/by-label/mug", "ops-key", "household-1", "", http.StatusOK},
+		{"unbound key without a tenant", http.MethodGet, "/uom/by-label/mug", "ops-key", "", "", http.StatusNotFound},
+		{"anonymous read of the global catalog", http.MethodGet, "/uom/by-label/cup", "", "", "", http.StatusOK},
+		{"anonymous read ignores the tenant", http.MethodGet, "/uom/by-label/mug", "", "household-1", "", http.StatusNotFound},
+		{"anonymous write", http.MethodPost, "/uom", "", "household-1", mug, http.StatusUnauthorized},
+		{"unknown key", http.MethodGet, "/uom/by-label/cup", "nope", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		if tt.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if tt.key != "" {
+			req.Header.Set("X-API-Key", tt.key)
+		}
+		if tt.tenant != "" {
+			req.Header.Set("X-Tenant-ID", tt.tenant)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: %s %s = %d, want %d: %s", tt.name, tt.method, tt.path, rec.Code, tt.wantStatus, rec.Body)
+		}
+	}
+}
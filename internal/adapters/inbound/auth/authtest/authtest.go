@@ -0,0 +1,17 @@
+// Package authtest provides fakes of the auth package for the inbound adapters' tests
+package authtest
+
+import (
+	"context"
+
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+)
+
+// Authenticator accepts any credentials as Principal
+type Authenticator struct {
+	Principal *auth.Principal
+}
+
+func (a Authenticator) Authenticate(context.Context, auth.Credentials) (*auth.Principal, error) {
+	return a.Principal, nil
+}
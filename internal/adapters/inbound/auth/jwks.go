@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// JWKS is a set of public keys (RFC 7517) that tokens are verified against. It
+// supports RSA, EC (P-256, P-384, P-521) and Ed25519 signing keys. A set loaded from a
+// file rereads it when a token names a kid it doesn't have and the file has changed,
+// so keys the issuer rotates in are picked up without a restart.
+type JWKS struct {
+	path string // Optional: the file the set was loaded from
+
+	mu      sync.RWMutex
+	set     keySet
+	modTime time.Time // of the file when it was last read
+	size    int64
+}
+
+type keySet struct {
+	keys map[string]crypto.PublicKey // keyed by kid
+	only crypto.PublicKey            // the key used for tokens without a kid when the set has exactly one
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// LoadJWKS reads a key set from a JSON file
+func LoadJWKS(path string) (*JWKS, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks file: %w", err)
+	}
+	set, err := readKeySet(path)
+	if err != nil {
+		return nil, err
+	}
+	return &JWKS{path: path, set: set, modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// ParseJWKS parses a key set. Encryption keys are skipped.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	set, err := parseKeySet(data)
+	if err != nil {
+		return nil, err
+	}
+	return &JWKS{set: set}, nil
+}
+
+func readKeySet(path string) (keySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return keySet{}, fmt.Errorf("failed to read jwks file: %w", err)
+	}
+	set, err := parseKeySet(data)
+	if err != nil {
+		return keySet{}, fmt.Errorf("invalid jwks file %s: %w", path, err)
+	}
+	return set, nil
+}
+
+func parseKeySet(data []byte) (keySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return keySet{}, err
+	}
+
+	set := keySet{keys: make(map[string]crypto.PublicKey)}
+	for i, k := range doc.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return keySet{}, fmt.Errorf("key %d (%s): %w", i, k.Kid, err)
+		}
+		if _, ok := set.keys[k.Kid]; ok {
+			return keySet{}, fmt.Errorf("key %d: duplicate kid %q", i, k.Kid)
+		}
+		set.keys[k.Kid] = key
+		set.only = key
+	}
+	if len(set.keys) == 0 {
+		return keySet{}, errors.New("no signing keys")
+	}
+	if len(set.keys) > 1 {
+		set.only = nil
+	}
+	return set, nil
+}
+
+// Key returns the key with the kid. An empty kid is only accepted when the set has a single key.
+func (s *JWKS) Key(kid string) (crypto.PublicKey, error) {
+	key, err := s.lookup(kid)
+	if err != nil && kid != "" && s.reload() {
+		return s.lookup(kid)
+	}
+	return key, err
+}
+
+func (s *JWKS) lookup(kid string) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if kid == "" {
+		if s.set.only == nil {
+			return nil, errors.New("token has no kid")
+		}
+		return s.set.only, nil
+	}
+	key, ok := s.set.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	return key, nil
+}
+
+// reload rereads the file if it changed since it was last read, reporting whether the
+// keys changed. Only a stat is spent on tokens with made-up kids, and a file that fails
+// to parse is read once per change while the keys already loaded stay in use.
+func (s *JWKS) reload() bool {
+	if s.path == "" {
+		return false
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return false
+	}
+	s.modTime, s.size = info.ModTime(), info.Size()
+	set, err := readKeySet(s.path)
+	if err != nil {
+		return false
+	}
+	s.set = set
+	return true
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid n: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid e: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid e")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y: %w", err)
+		}
+		point := append(append([]byte{4}, x...), y...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid x")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
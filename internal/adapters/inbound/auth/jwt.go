@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+// signingMethods are the asymmetric algorithms accepted. HMAC is left out on purpose:
+// a JWKS holds public keys, and accepting HS* would let a public key act as a secret.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type JWTOptions struct {
+	Issuer      string        // Optional: required "iss" value
+	Audience    string        // Optional: required "aud" value
+	RoleClaim   string        // Optional: claim holding the role or a list of roles, defaults to "role"
+	TenantClaim string        // Optional: claim binding the caller to a tenant, defaults to "tenant"
+	Leeway      time.Duration // Optional: clock skew allowed on exp and nbf
+}
+
+// JWTAuthenticator authenticates bearer tokens signed by one of the keys in a JWKS.
+// Tokens must carry an expiry and a role; when the role claim lists several roles
+// the highest one applies.
+type JWTAuthenticator struct {
+	keys   *JWKS
+	opts   JWTOptions
+	parser *jwt.Parser
+}
+
+func NewJWTAuthenticator(keys *JWKS, opts JWTOptions) *JWTAuthenticator {
+	if opts.RoleClaim == "" {
+		opts.RoleClaim = "role"
+	}
+	if opts.TenantClaim == "" {
+		opts.TenantClaim = "tenant"
+	}
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(opts.Leeway),
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+	return &JWTAuthenticator{
+		keys:   keys,
+		opts:   opts,
+		parser: jwt.NewParser(parserOpts...),
+	}
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	if creds.BearerToken == "" {
+		return nil, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(creds.BearerToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.Key(kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	subject, _ := claims.GetSubject()
+	role, err := highestRole(claims[a.opts.RoleClaim])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	p := &Principal{Subject: subject, Role: role}
+	if tenant, ok := claims[a.opts.TenantClaim].(string); ok && tenant != "" {
+		if err := domain.ValidateTenant(tenant); err != nil {
+			return nil, fmt.Errorf("invalid token: %w", err)
+		}
+		p.Tenant = tenant
+	}
+	return p, nil
+}
+
+// highestRole reads a role claim that is either a single role or a list of roles.
+// Unknown role names in a list are ignored so tokens can carry roles of other services.
+func highestRole(claim any) (Role, error) {
+	var names []string
+	switch v := claim.(type) {
+	case string:
+		names = []string{v}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				names = append(names, s)
+			}
+		}
+	}
+
+	var best Role
+	for _, name := range names {
+		if role, err := ParseRole(name); err == nil && roleLevels[role] > roleLevels[best] {
+			best = role
+		}
+	}
+	if best == "" {
+		return "", errors.New("token has no reader, editor or admin role")
+	}
+	return best, nil
+}
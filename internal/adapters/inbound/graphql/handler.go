@@ -0,0 +1,69 @@
+// Package graphql serves a GraphQL API over the uom catalog, for clients such as the
+// admin UI that want uoms with their groups, measure types and conversions in one
+// round trip. Nested lookups are batched per request; see requestLoaders.
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/jeffjlins/okra/internal/usecase"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxDepth bounds how deeply queries may nest, e.g. uom.group.uoms.group...
+const maxDepth = 8
+
+// Options configures the GraphQL handler
+type Options struct {
+	// RequireAuth makes mutations check the caller's role, as the REST routes do. The
+	// handler must then run behind authentication that admits readers.
+	RequireAuth bool
+}
+
+// NewHandler answers GraphQL requests, POSTed as JSON. Other methods get a 405, even
+// when the handler is mounted without a method in its pattern.
+func NewHandler(uomService *usecase.UomService, opts Options) http.Handler {
+	s := gql.MustParseSchema(schema, &rootResolver{uomService: uomService, requireAuth: opts.RequireAuth},
+		gql.UseStringDescriptions(),
+		gql.MaxDepth(maxDepth),
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		var req struct {
+			Query         string         `json:"query"`
+			OperationName string         `json:"operationName"`
+			Variables     map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %v", err))
+			return
+		}
+
+		ctx := withLoaders(r.Context(), newRequestLoaders(uomService))
+		resp := s.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			// The status is already sent, so the client only sees a truncated body
+			logging.FromContext(ctx).Warn("failed to write graphql response", "error", err)
+		}
+	})
+}
+
+// writeError answers with the body of the REST routes' errors, {"error": msg}
+func writeError(w http.ResponseWriter, statusCode int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,155 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/adapters/outbound/memory"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/usecase"
+	"github.com/jeffjlins/okra/pkg/okra"
+)
+
+// newTestHandler serves the default catalog pkg/okra embeds from the memory repository
+func newTestHandler(t *testing.T, opts Options) http.Handler {
+	t.Helper()
+	uoms, err := okra.DefaultUoms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := memory.NewUomRepository(uoms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(usecase.NewUomService(repo, domain.LabelIDGenerator), opts)
+}
+
+type gqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// exec POSTs the query as ctx's caller and decodes the response
+func exec(t *testing.T, h http.Handler, ctx context.Context, query string) gqlResponse {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var resp gqlResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response %s: %v", rec.Body, err)
+	}
+	return resp
+}
+
+func TestHandlerRejects(t *testing.T) {
+	h := newTestHandler(t, Options{})
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{"GET", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"PUT", http.MethodPut, `{"query": "{ measureTypes { name } }"}`, http.StatusMethodNotAllowed},
+		{"invalid JSON", http.MethodPost, `{"query": `, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, "/graphql", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+			if tt.wantStatus == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != http.MethodPost {
+				t.Errorf("Allow = %q, want POST", rec.Header().Get("Allow"))
+			}
+		})
+	}
+}
+
+func TestUomNotFoundIsNull(t *testing.T) {
+	resp := exec(t, newTestHandler(t, Options{}), context.Background(), `{ byLabel: uom(label: "no such uom") { label } byID: uom(id: "nope") { label } }`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %v", resp.Errors)
+	}
+	for _, field := range []string{"byLabel", "byID"} {
+		if got := string(resp.Data[field]); got != "null" {
+			t.Errorf("%s = %s, want null", field, got)
+		}
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	// nested wraps the selection in pivotUom depth times; uom and label make the
+	// query depth+2 deep
+	nested := func(depth int) string {
+		return `{ uom(label: "cup") {` + strings.Repeat(" pivotUom {", depth) + " label" + strings.Repeat(" }", depth) + " } }"
+	}
+	h := newTestHandler(t, Options{})
+
+	if resp := exec(t, h, context.Background(), nested(maxDepth-2)); len(resp.Errors) > 0 {
+		t.Errorf("query within the depth limit failed: %v", resp.Errors)
+	}
+	resp := exec(t, h, context.Background(), nested(maxDepth-1))
+	if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, "exceeds max depth") {
+		t.Errorf("query past the depth limit gave errors %v, want a depth error", resp.Errors)
+	}
+}
+
+func TestMutationRoles(t *testing.T) {
+	reader := &auth.Principal{Subject: "reader", Role: auth.ROLE_READER}
+	editor := &auth.Principal{Subject: "editor", Role: auth.ROLE_EDITOR}
+	admin := &auth.Principal{Subject: "admin", Role: auth.ROLE_ADMIN}
+
+	tests := []struct {
+		name        string
+		requireAuth bool
+		principal   *auth.Principal
+		tenant      string
+		wantErr     string
+	}{
+		{"auth off", false, nil, "", ""},
+		{"reader", true, reader, "household-1", "role editor required"},
+		{"editor, tenant catalog", true, editor, "household-1", ""},
+		{"editor, global catalog", true, editor, "", "role admin required"},
+		{"admin, global catalog", true, admin, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, Options{RequireAuth: tt.requireAuth})
+			ctx := domain.WithTenant(context.Background(), tt.tenant)
+			if tt.principal != nil {
+				ctx = auth.WithPrincipal(ctx, tt.principal)
+			}
+
+			resp := exec(t, h, ctx, `mutation {
+				createUom(input: {label: "mug", enabled: true, measureType: "volume", pivotRatio: "350",
+					snapAmount: ["1/4"], defaultNameType: "full", fullNameSingular: "mug", fullNamePlural: "mugs"}) { label }
+			}`)
+			switch {
+			case tt.wantErr == "" && len(resp.Errors) > 0:
+				t.Errorf("errors: %v", resp.Errors)
+			case tt.wantErr != "" && (len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.wantErr)):
+				t.Errorf("errors %v, want %q", resp.Errors, tt.wantErr)
+			}
+		})
+	}
+}
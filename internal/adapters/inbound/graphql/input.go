@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+// uomInput is the UomInput type. Validation is left to the use cases.
+type uomInput struct {
+	Label                     string
+	Enabled                   bool
+	MeasureType               string
+	Group                     *string
+	GroupMin                  *string
+	GroupMax                  *string
+	SnapAmount                []string
+	SnapSelect                *string
+	PivotRatio                *string
+	PackageAmount             *string
+	PackageUom                *string
+	MatchNamesRecipe          *[]string
+	MatchNamesFoodLabel       *[]string
+	DefaultNameType           string
+	ShortNameSingular         *string
+	ShortNamePlural           *string
+	FullNameSingular          *string
+	FullNamePlural            *string
+	DisambiguatedNameSingular *string
+	DisambiguatedNamePlural   *string
+	Systems                   *[]string
+	NameGroup                 *string
+	Locales                   *[]uomLocaleInput
+}
+
+type uomLocaleInput struct {
+	Tag                string
+	ShortNames         *[]pluralNameInput
+	FullNames          *[]pluralNameInput
+	DisambiguatedNames *[]pluralNameInput
+	MatchNamesRecipe   *[]string
+}
+
+type pluralNameInput struct {
+	Category string
+	Name     string
+}
+
+func (in uomInput) toBaseUom() (*domain.BaseUom, error) {
+	base := &domain.BaseUom{
+		Label:                            in.Label,
+		Enabled:                          in.Enabled,
+		MeasureType:                      in.MeasureType,
+		Group:                            in.Group,
+		SnapAmount:                       make([]domain.Rational, len(in.SnapAmount)),
+		PackageUom:                       in.PackageUom,
+		MatchNamesRecipe:                 deref(in.MatchNamesRecipe),
+		MatchNamesFoodLabel:              deref(in.MatchNamesFoodLabel),
+		PrintedNameDefaultType:           in.DefaultNameType,
+		PrintedNameShortSingular:         in.ShortNameSingular,
+		PrintedNameShortPlural:           in.ShortNamePlural,
+		PrintedNameFullSingular:          in.FullNameSingular,
+		PrintedNameFullPlural:            in.FullNamePlural,
+		PrintedNameDisambiguatedSingular: in.DisambiguatedNameSingular,
+		PrintedNameDisambiguatedPlural:   in.DisambiguatedNamePlural,
+	}
+
+	for _, r := range []struct {
+		field string
+		in    *string
+		out   **domain.Rational
+	}{
+		{"groupMin", in.GroupMin, &base.GroupMin},
+		{"groupMax", in.GroupMax, &base.GroupMax},
+		{"snapSelect", in.SnapSelect, &base.SnapSelect},
+		{"pivotRatio", in.PivotRatio, &base.PivotRatio},
+		{"packageAmount", in.PackageAmount, &base.PackageAmount},
+	} {
+		if r.in == nil {
+			continue
+		}
+		v, err := parseRational(r.field, *r.in)
+		if err != nil {
+			return nil, err
+		}
+		*r.out = &v
+	}
+	for i, s := range in.SnapAmount {
+		v, err := parseRational("snapAmount", s)
+		if err != nil {
+			return nil, err
+		}
+		base.SnapAmount[i] = v
+	}
+
+	if in.Systems != nil || in.NameGroup != nil {
+		base.AdditionalInfo = &domain.UomAdditionalInfo{Systems: deref(in.Systems), NameGroup: in.NameGroup}
+	}
+	if in.Locales != nil {
+		base.Locales = make(map[string]domain.UomLocale, len(*in.Locales))
+		for _, l := range *in.Locales {
+			base.Locales[l.Tag] = domain.UomLocale{
+				ShortNames:         pluralNames(l.ShortNames),
+				FullNames:          pluralNames(l.FullNames),
+				DisambiguatedNames: pluralNames(l.DisambiguatedNames),
+				MatchNamesRecipe:   deref(l.MatchNamesRecipe),
+			}
+		}
+	}
+	return base, nil
+}
+
+func pluralNames(in *[]pluralNameInput) map[domain.UomPluralCategory]string {
+	if in == nil {
+		return nil
+	}
+	names := make(map[domain.UomPluralCategory]string, len(*in))
+	for _, n := range *in {
+		names[n.Category] = n.Name
+	}
+	return names
+}
+
+func deref[T any](s *[]T) []T {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
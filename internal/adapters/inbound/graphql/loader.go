@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// batchWait is how long a loader collects keys before fetching them. GraphQL resolves
+// the items of a list concurrently, so the lookups of one nesting level land in the
+// same batch.
+const batchWait = 2 * time.Millisecond
+
+// loader batches and caches lookups for the lifetime of one request, in the manner of
+// dataloader: Load calls made close together are answered by a single fetch, and a key
+// is never fetched twice
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	results map[K]*result[V]
+	pending []K
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, results: make(map[K]*result[V])}
+}
+
+// Load returns the value of key, or the zero value when the fetch didn't find it
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.results[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.results[key] = res
+		l.pending = append(l.pending, key)
+		// The first key of a batch schedules its fetch
+		if len(l.pending) == 1 {
+			time.AfterFunc(batchWait, func() { l.dispatch(context.WithoutCancel(ctx)) })
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	values, err := l.fetch(ctx, keys)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		res := l.results[key]
+		res.value, res.err = values[key], err
+		close(res.done)
+	}
+}
+
+// clear forgets every cached value, so lookups after a mutation see its effect
+func (l *loader[K, V]) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, res := range l.results {
+		select {
+		case <-res.done:
+			delete(l.results, key)
+		default: // still being fetched; keep it so its waiters get an answer
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// countingFetch answers every key with its length and records the batches it was asked for
+type countingFetch struct {
+	mu      sync.Mutex
+	batches [][]string
+	started chan struct{} // Optional: signalled when a fetch starts
+	release chan struct{} // Optional: a fetch waits for it before answering
+}
+
+func (f *countingFetch) fetch(ctx context.Context, keys []string) (map[string]int, error) {
+	f.mu.Lock()
+	f.batches = append(f.batches, slices.Sorted(slices.Values(keys)))
+	f.mu.Unlock()
+	if f.started != nil {
+		f.started <- struct{}{}
+	}
+	if f.release != nil {
+		<-f.release
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	values := make(map[string]int, len(keys))
+	for _, key := range keys {
+		values[key] = len(key)
+	}
+	return values, nil
+}
+
+func (f *countingFetch) fetches() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.batches)
+}
+
+func TestLoaderBatchesAndCaches(t *testing.T) {
+	f := &countingFetch{}
+	l := newLoader(f.fetch)
+	ctx := context.Background()
+
+	keys := []string{"cup", "tbsp", "cup", "ml"}
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Go(func() {
+			if got, err := l.Load(ctx, key); err != nil || got != len(key) {
+				t.Errorf("Load(%q) = %d, %v; want %d", key, got, err, len(key))
+			}
+		})
+	}
+	wg.Wait()
+
+	want := [][]string{{"cup", "ml", "tbsp"}}
+	if got := f.fetches(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("fetched %v, want one batch %v", got, want)
+	}
+
+	if _, err := l.Load(ctx, "cup"); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.fetches(); len(got) != 1 {
+		t.Errorf("a cached key was fetched again: %v", got)
+	}
+
+	l.clear()
+	if _, err := l.Load(ctx, "cup"); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.fetches(); len(got) != 2 || !slices.Equal(got[1], []string{"cup"}) {
+		t.Errorf("after clear fetched %v, want a second batch [cup]", got)
+	}
+}
+
+func TestLoaderFetchError(t *testing.T) {
+	errFetch := errors.New("catalog unavailable")
+	l := newLoader(func(context.Context, []string) (map[string]int, error) { return nil, errFetch })
+	if _, err := l.Load(context.Background(), "cup"); !errors.Is(err, errFetch) {
+		t.Errorf("Load error = %v, want %v", err, errFetch)
+	}
+}
+
+func TestLoaderClearDuringFetch(t *testing.T) {
+	f := &countingFetch{started: make(chan struct{}), release: make(chan struct{})}
+	l := newLoader(f.fetch)
+	ctx := context.Background()
+
+	type answer struct {
+		value int
+		err   error
+	}
+	done := make(chan answer)
+	go func() {
+		v, err := l.Load(ctx, "cup")
+		done <- answer{v, err}
+	}()
+	<-f.started
+
+	// The key being fetched is kept, so its waiter still gets an answer
+	l.clear()
+	close(f.release)
+	if got := <-done; got.err != nil || got.value != 3 {
+		t.Fatalf("Load = %d, %v; want 3", got.value, got.err)
+	}
+	if got := f.fetches(); len(got) != 1 {
+		t.Errorf("clear during the fetch started another one: %v", got)
+	}
+}
+
+func TestLoaderContextCancellation(t *testing.T) {
+	f := &countingFetch{started: make(chan struct{}), release: make(chan struct{})}
+	l := newLoader(f.fetch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := l.Load(ctx, "cup")
+		canceled <- err
+	}()
+	<-f.started
+
+	other := make(chan int)
+	go func() {
+		v, _ := l.Load(context.Background(), "cup")
+		other <- v
+	}()
+
+	cancel()
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled Load error = %v, want %v", err, context.Canceled)
+	}
+
+	// The fetch doesn't inherit the first caller's cancellation, so the other waiter
+	// of the batch still gets the value
+	close(f.release)
+	if v := <-other; v != 3 {
+		t.Errorf("other waiter got %d, want 3", v)
+	}
+}
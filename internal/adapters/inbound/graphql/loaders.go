@@ -0,0 +1,82 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/usecase"
+)
+
+// requestLoaders resolve the nested lookups of one request. They all read from a
+// catalog loaded once per request, so a query over every uom with their groups,
+// package uoms and conversions costs a single catalog load.
+type requestLoaders struct {
+	catalogs     *loader[struct{}, *domain.UomCatalog]
+	refs         *loader[string, *domain.Uom]   // by id, label or match name
+	groups       *loader[string, []*domain.Uom] // by group name
+	measureTypes *loader[string, []*domain.Uom] // by measure type
+}
+
+func newRequestLoaders(uomService *usecase.UomService) *requestLoaders {
+	l := &requestLoaders{}
+	l.catalogs = newLoader(func(ctx context.Context, _ []struct{}) (map[struct{}]*domain.UomCatalog, error) {
+		catalog, err := uomService.Catalog(ctx)
+		return map[struct{}]*domain.UomCatalog{{}: catalog}, err
+	})
+	l.refs = newLoader(func(ctx context.Context, refs []string) (map[string]*domain.Uom, error) {
+		catalog, err := l.catalog(ctx)
+		if err != nil {
+			return nil, err
+		}
+		uoms := make(map[string]*domain.Uom, len(refs))
+		for _, ref := range refs {
+			uoms[ref] = catalog.Resolve(ref)
+		}
+		return uoms, nil
+	})
+	l.groups = newLoader(func(ctx context.Context, groups []string) (map[string][]*domain.Uom, error) {
+		catalog, err := l.catalog(ctx)
+		if err != nil {
+			return nil, err
+		}
+		uoms := make(map[string][]*domain.Uom, len(groups))
+		for _, group := range groups {
+			uoms[group] = catalog.ByGroup(group)
+		}
+		return uoms, nil
+	})
+	l.measureTypes = newLoader(func(ctx context.Context, measureTypes []string) (map[string][]*domain.Uom, error) {
+		catalog, err := l.catalog(ctx)
+		if err != nil {
+			return nil, err
+		}
+		uoms := make(map[string][]*domain.Uom, len(measureTypes))
+		for _, uom := range catalog.All() {
+			uoms[uom.MeasureType] = append(uoms[uom.MeasureType], uom)
+		}
+		return uoms, nil
+	})
+	return l
+}
+
+func (l *requestLoaders) catalog(ctx context.Context) (*domain.UomCatalog, error) {
+	return l.catalogs.Load(ctx, struct{}{})
+}
+
+// clear drops everything loaded so far, for lookups after a mutation
+func (l *requestLoaders) clear() {
+	l.catalogs.clear()
+	l.refs.clear()
+	l.groups.clear()
+	l.measureTypes.clear()
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *requestLoaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFromContext(ctx context.Context) *requestLoaders {
+	return ctx.Value(loadersKey{}).(*requestLoaders)
+}
@@ -0,0 +1,215 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/jeffjlins/okra/internal/usecase"
+)
+
+// rootResolver answers the Query and Mutation fields
+type rootResolver struct {
+	uomService  *usecase.UomService
+	requireAuth bool
+}
+
+func (r *rootResolver) Uom(ctx context.Context, args struct {
+	ID    *gql.ID
+	Label *string
+}) (*uomResolver, error) {
+	var uom *domain.Uom
+	var err error
+	switch {
+	case args.ID != nil:
+		uom, err = r.uomService.GetUomByID(ctx, string(*args.ID))
+	case args.Label != nil:
+		uom, err = r.uomService.GetUomByLabel(ctx, *args.Label)
+	default:
+		return nil, errors.New("validation failed: id or label is required")
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, toError(ctx, "failed to get uom", err)
+	}
+	return newUomResolver(uom), nil
+}
+
+type uomFilter struct {
+	System      *string
+	MeasureType *string
+	Group       *string
+	Enabled     *bool
+	Search      *string
+}
+
+func (f *uomFilter) matches(uom *domain.Uom) bool {
+	if f == nil {
+		return true
+	}
+	if f.System != nil && !uom.InSystem(*f.System) {
+		return false
+	}
+	if f.MeasureType != nil && uom.MeasureType != *f.MeasureType {
+		return false
+	}
+	if f.Group != nil && (uom.Group == nil || *uom.Group != *f.Group) {
+		return false
+	}
+	if f.Enabled != nil && uom.Enabled != *f.Enabled {
+		return false
+	}
+	if f.Search != nil {
+		search := strings.ToLower(*f.Search)
+		contains := func(s string) bool { return strings.Contains(strings.ToLower(s), search) }
+		if !contains(uom.Label) && !slices.ContainsFunc(uom.MatchNamesRecipe, contains) &&
+			!slices.ContainsFunc(uom.MatchNamesFoodLabel, contains) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *rootResolver) Uoms(ctx context.Context, args struct{ Filter *uomFilter }) ([]*uomResolver, error) {
+	if args.Filter != nil && args.Filter.System != nil {
+		if _, err := domain.ParseUomSystem(*args.Filter.System); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+	catalog, err := loadersFromContext(ctx).catalog(ctx)
+	if err != nil {
+		return nil, toError(ctx, "failed to get uoms", err)
+	}
+	var resolvers []*uomResolver
+	for _, uom := range catalog.All() {
+		if args.Filter.matches(uom) {
+			resolvers = append(resolvers, newUomResolver(uom))
+		}
+	}
+	return resolvers, nil
+}
+
+func (r *rootResolver) Group(ctx context.Context, args struct{ Name string }) (*groupResolver, error) {
+	uoms, err := loadersFromContext(ctx).groups.Load(ctx, args.Name)
+	if err != nil {
+		return nil, toError(ctx, "failed to get group", err)
+	}
+	if len(uoms) == 0 {
+		return nil, nil
+	}
+	return &groupResolver{name: args.Name}, nil
+}
+
+func (r *rootResolver) MeasureType(ctx context.Context, args struct{ Name string }) (*measureTypeResolver, error) {
+	uoms, err := loadersFromContext(ctx).measureTypes.Load(ctx, args.Name)
+	if err != nil {
+		return nil, toError(ctx, "failed to get measure type", err)
+	}
+	if len(uoms) == 0 {
+		return nil, nil
+	}
+	return &measureTypeResolver{name: args.Name}, nil
+}
+
+func (r *rootResolver) MeasureTypes(ctx context.Context) ([]*measureTypeResolver, error) {
+	catalog, err := loadersFromContext(ctx).catalog(ctx)
+	if err != nil {
+		return nil, toError(ctx, "failed to get measure types", err)
+	}
+	var names []string
+	for _, uom := range catalog.All() {
+		if !slices.Contains(names, uom.MeasureType) {
+			names = append(names, uom.MeasureType)
+		}
+	}
+	slices.Sort(names)
+	resolvers := make([]*measureTypeResolver, len(names))
+	for i, name := range names {
+		resolvers[i] = &measureTypeResolver{name: name}
+	}
+	return resolvers, nil
+}
+
+func (r *rootResolver) CreateUom(ctx context.Context, args struct{ Input uomInput }) (*uomResolver, error) {
+	if err := r.authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+	base, err := args.Input.toBaseUom()
+	if err != nil {
+		return nil, err
+	}
+	uom, err := r.uomService.CreateUom(ctx, base)
+	if err != nil {
+		return nil, toError(ctx, "failed to create uom", err)
+	}
+	loadersFromContext(ctx).clear()
+	return newUomResolver(uom), nil
+}
+
+func (r *rootResolver) UpdateUom(ctx context.Context, args struct {
+	ID    gql.ID
+	Input uomInput
+}) (*uomResolver, error) {
+	if err := r.authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+	base, err := args.Input.toBaseUom()
+	if err != nil {
+		return nil, err
+	}
+	uom, err := r.uomService.UpdateUom(ctx, string(args.ID), base)
+	if err != nil {
+		return nil, toError(ctx, "failed to update uom", err)
+	}
+	loadersFromContext(ctx).clear()
+	return newUomResolver(uom), nil
+}
+
+func (r *rootResolver) DeleteUom(ctx context.Context, args struct{ ID gql.ID }) (gql.ID, error) {
+	if err := r.authorizeWrite(ctx); err != nil {
+		return "", err
+	}
+	if err := r.uomService.DeleteUom(ctx, string(args.ID)); err != nil {
+		return "", toError(ctx, "failed to delete uom", err)
+	}
+	loadersFromContext(ctx).clear()
+	return args.ID, nil
+}
+
+// authorizeWrite applies the REST rules to mutations: editors may change their tenant's
+// uoms, but only admins may change the global catalog. The route itself only demands a
+// reader, which is all queries need.
+func (r *rootResolver) authorizeWrite(ctx context.Context) error {
+	if !r.requireAuth {
+		return nil
+	}
+	principal := auth.PrincipalFromContext(ctx)
+	if !principal.Allows(auth.ROLE_EDITOR) {
+		return errors.New("forbidden: role editor required")
+	}
+	if domain.TenantFromContext(ctx) == "" && !principal.Allows(auth.ROLE_ADMIN) {
+		return errors.New("forbidden: role admin required to change the global catalog")
+	}
+	return nil
+}
+
+// toError passes on the use case errors a caller can act on and hides the rest behind
+// msg, logging them, like the REST adapter's error responses
+func toError(ctx context.Context, msg string, err error) error {
+	errStr := err.Error()
+	for _, phrase := range []string{"not found", "validation failed", "cannot convert", "already exists", "read-only"} {
+		if strings.Contains(errStr, phrase) {
+			return err
+		}
+	}
+	logging.FromContext(ctx).Error(msg, "error", err)
+	return errors.New(msg)
+}
@@ -0,0 +1,387 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/jeffjlins/okra/internal/domain"
+	"golang.org/x/text/language"
+)
+
+type uomResolver struct {
+	uom *domain.Uom
+}
+
+func newUomResolver(uom *domain.Uom) *uomResolver {
+	if uom == nil {
+		return nil
+	}
+	return &uomResolver{uom: uom}
+}
+
+func newUomResolvers(uoms []*domain.Uom) []*uomResolver {
+	resolvers := make([]*uomResolver, len(uoms))
+	for i, uom := range uoms {
+		resolvers[i] = newUomResolver(uom)
+	}
+	return resolvers
+}
+
+func (r *uomResolver) ID() gql.ID {
+	return gql.ID(r.uom.Id)
+}
+
+func (r *uomResolver) Label() string {
+	return r.uom.Label
+}
+
+func (r *uomResolver) Enabled() bool {
+	return r.uom.Enabled
+}
+
+func (r *uomResolver) MeasureType() *measureTypeResolver {
+	return &measureTypeResolver{name: r.uom.MeasureType}
+}
+
+func (r *uomResolver) Group() *groupResolver {
+	if r.uom.Group == nil {
+		return nil
+	}
+	return &groupResolver{name: *r.uom.Group}
+}
+
+func (r *uomResolver) GroupMin() *string {
+	return rationalString(r.uom.GroupMin)
+}
+
+func (r *uomResolver) GroupMax() *string {
+	return rationalString(r.uom.GroupMax)
+}
+
+func (r *uomResolver) SnapAmount() []string {
+	amounts := make([]string, len(r.uom.SnapAmount))
+	for i, a := range r.uom.SnapAmount {
+		amounts[i] = a.String()
+	}
+	return amounts
+}
+
+func (r *uomResolver) SnapSelect() *string {
+	return rationalString(r.uom.SnapSelect)
+}
+
+func (r *uomResolver) PivotRatio() *string {
+	return rationalString(r.uom.PivotRatio)
+}
+
+func (r *uomResolver) PivotUom(ctx context.Context) (*uomResolver, error) {
+	if r.uom.PivotRatio == nil {
+		return nil, nil
+	}
+	return r.MeasureType().Pivot(ctx)
+}
+
+func (r *uomResolver) PivotAmount(args struct{ Amount string }) (*string, error) {
+	if r.uom.PivotRatio == nil {
+		return nil, nil
+	}
+	amount, err := parseRational("amount", args.Amount)
+	if err != nil {
+		return nil, err
+	}
+	pivot := amount.Mul(*r.uom.PivotRatio).String()
+	return &pivot, nil
+}
+
+func (r *uomResolver) PackageAmount() *string {
+	return rationalString(r.uom.PackageAmount)
+}
+
+func (r *uomResolver) PackageUom(ctx context.Context) (*uomResolver, error) {
+	if r.uom.PackageUom == nil {
+		return nil, nil
+	}
+	uom, err := loadersFromContext(ctx).refs.Load(ctx, *r.uom.PackageUom)
+	if err != nil {
+		return nil, toError(ctx, "failed to get package uom", err)
+	}
+	return newUomResolver(uom), nil
+}
+
+func (r *uomResolver) MatchNamesRecipe() []string {
+	return nonNil(r.uom.MatchNamesRecipe)
+}
+
+func (r *uomResolver) MatchNamesFoodLabel() []string {
+	return nonNil(r.uom.MatchNamesFoodLabel)
+}
+
+func (r *uomResolver) DefaultNameType() string {
+	return r.uom.PrintedNameDefaultType
+}
+
+func (r *uomResolver) ShortNameSingular() *string {
+	return r.uom.PrintedNameShortSingular
+}
+
+func (r *uomResolver) ShortNamePlural() *string {
+	return r.uom.PrintedNameShortPlural
+}
+
+func (r *uomResolver) FullNameSingular() *string {
+	return r.uom.PrintedNameFullSingular
+}
+
+func (r *uomResolver) FullNamePlural() *string {
+	return r.uom.PrintedNameFullPlural
+}
+
+func (r *uomResolver) DisambiguatedNameSingular() *string {
+	return r.uom.PrintedNameDisambiguatedSingular
+}
+
+func (r *uomResolver) DisambiguatedNamePlural() *string {
+	return r.uom.PrintedNameDisambiguatedPlural
+}
+
+func (r *uomResolver) Systems() []string {
+	if r.uom.AdditionalInfo == nil {
+		return []string{}
+	}
+	return nonNil(r.uom.AdditionalInfo.Systems)
+}
+
+func (r *uomResolver) NameGroup() *string {
+	if r.uom.AdditionalInfo == nil {
+		return nil
+	}
+	return r.uom.AdditionalInfo.NameGroup
+}
+
+func (r *uomResolver) Locales() []*uomLocaleResolver {
+	tags := slices.Sorted(maps.Keys(r.uom.Locales))
+	resolvers := make([]*uomLocaleResolver, len(tags))
+	for i, tag := range tags {
+		resolvers[i] = &uomLocaleResolver{tag: tag, locale: r.uom.Locales[tag]}
+	}
+	return resolvers
+}
+
+func (r *uomResolver) Formatted(args struct {
+	Amount      string
+	Max         *string
+	Approximate bool
+	Locale      *string
+}) (string, error) {
+	q, err := newQuantity(args.Amount, args.Max, r.uom)
+	if err != nil {
+		return "", err
+	}
+	q.Approximate = args.Approximate
+
+	var locales []language.Tag
+	if args.Locale != nil {
+		tag, err := language.Parse(*args.Locale)
+		if err != nil {
+			return "", fmt.Errorf("validation failed: invalid locale %q", *args.Locale)
+		}
+		locales = []language.Tag{tag}
+	}
+	return domain.FormatQuantity(q, domain.FormatOptions{Locales: locales}), nil
+}
+
+// ConvertTo resolves the target through the request's loaders, so converting every uom
+// of a list loads the catalog once
+func (r *uomResolver) ConvertTo(ctx context.Context, args struct {
+	Uom    string
+	Amount string
+}) (*quantityResolver, error) {
+	q, err := newQuantity(args.Amount, nil, r.uom)
+	if err != nil {
+		return nil, err
+	}
+	to, err := loadersFromContext(ctx).refs.Load(ctx, args.Uom)
+	if err != nil {
+		return nil, toError(ctx, "failed to convert", err)
+	}
+	if to == nil {
+		return nil, fmt.Errorf("uom %s %w", args.Uom, domain.ErrNotFound)
+	}
+	converted, err := domain.ConvertQuantity(q, to)
+	if err != nil {
+		return nil, err
+	}
+	return &quantityResolver{q: converted}, nil
+}
+
+type uomLocaleResolver struct {
+	tag    string
+	locale domain.UomLocale
+}
+
+func (r *uomLocaleResolver) Tag() string {
+	return r.tag
+}
+
+func (r *uomLocaleResolver) ShortNames() []*pluralNameResolver {
+	return newPluralNames(r.locale.ShortNames)
+}
+
+func (r *uomLocaleResolver) FullNames() []*pluralNameResolver {
+	return newPluralNames(r.locale.FullNames)
+}
+
+func (r *uomLocaleResolver) DisambiguatedNames() []*pluralNameResolver {
+	return newPluralNames(r.locale.DisambiguatedNames)
+}
+
+func (r *uomLocaleResolver) MatchNamesRecipe() []string {
+	return nonNil(r.locale.MatchNamesRecipe)
+}
+
+type pluralNameResolver struct {
+	category string
+	name     string
+}
+
+func newPluralNames(names map[domain.UomPluralCategory]string) []*pluralNameResolver {
+	categories := slices.Sorted(maps.Keys(names))
+	resolvers := make([]*pluralNameResolver, len(categories))
+	for i, category := range categories {
+		resolvers[i] = &pluralNameResolver{category: category, name: names[category]}
+	}
+	return resolvers
+}
+
+func (r *pluralNameResolver) Category() string {
+	return r.category
+}
+
+func (r *pluralNameResolver) Name() string {
+	return r.name
+}
+
+type groupResolver struct {
+	name string
+}
+
+func (r *groupResolver) Name() string {
+	return r.name
+}
+
+func (r *groupResolver) Uoms(ctx context.Context) ([]*uomResolver, error) {
+	uoms, err := loadersFromContext(ctx).groups.Load(ctx, r.name)
+	if err != nil {
+		return nil, toError(ctx, "failed to get group", err)
+	}
+	return newUomResolvers(uoms), nil
+}
+
+type measureTypeResolver struct {
+	name string
+}
+
+func (r *measureTypeResolver) Name() string {
+	return r.name
+}
+
+func (r *measureTypeResolver) Pivot(ctx context.Context) (*uomResolver, error) {
+	uoms, err := loadersFromContext(ctx).measureTypes.Load(ctx, r.name)
+	if err != nil {
+		return nil, toError(ctx, "failed to get measure type", err)
+	}
+	one := domain.IntRational(1)
+	for _, uom := range uoms {
+		if uom.PivotRatio != nil && uom.PivotRatio.Cmp(one) == 0 {
+			return newUomResolver(uom), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *measureTypeResolver) Uoms(ctx context.Context) ([]*uomResolver, error) {
+	uoms, err := loadersFromContext(ctx).measureTypes.Load(ctx, r.name)
+	if err != nil {
+		return nil, toError(ctx, "failed to get measure type", err)
+	}
+	return newUomResolvers(uoms), nil
+}
+
+type quantityResolver struct {
+	q domain.Quantity
+}
+
+func (r *quantityResolver) Amount() *string {
+	if r.q.IsQualitative() {
+		return nil
+	}
+	return rationalString(&r.q.Min)
+}
+
+func (r *quantityResolver) Max() *string {
+	if !r.q.IsRange() {
+		return nil
+	}
+	return rationalString(&r.q.Max)
+}
+
+func (r *quantityResolver) Approximate() bool {
+	return r.q.Approximate
+}
+
+func (r *quantityResolver) Qualitative() *string {
+	if r.q.Qualitative == "" {
+		return nil
+	}
+	return &r.q.Qualitative
+}
+
+func (r *quantityResolver) Uom() *uomResolver {
+	return newUomResolver(r.q.Uom)
+}
+
+func (r *quantityResolver) Text() string {
+	return domain.FormatQuantity(r.q, domain.FormatOptions{})
+}
+
+func newQuantity(amount string, max *string, uom *domain.Uom) (domain.Quantity, error) {
+	lo, err := parseRational("amount", amount)
+	if err != nil {
+		return domain.Quantity{}, err
+	}
+	if max == nil {
+		return domain.NewQuantity(lo, uom), nil
+	}
+	hi, err := parseRational("max", *max)
+	if err != nil {
+		return domain.Quantity{}, err
+	}
+	return domain.NewQuantityRange(lo, hi, uom), nil
+}
+
+func parseRational(field, s string) (domain.Rational, error) {
+	r, err := domain.ParseRational(s)
+	if err != nil {
+		return domain.Rational{}, fmt.Errorf("validation failed: %s: %w", field, err)
+	}
+	return r, nil
+}
+
+func rationalString(r *domain.Rational) *string {
+	if r == nil {
+		return nil
+	}
+	s := r.String()
+	return &s
+}
+
+// nonNil turns a missing list into an empty one, as the schema's lists are non-null
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,179 @@
+package grpc
+
+import (
+	"fmt"
+
+	okrav1 "github.com/jeffjlins/okra/api/proto/okra/v1"
+	"github.com/jeffjlins/okra/internal/domain"
+	"golang.org/x/text/language"
+)
+
+// Amounts cross the wire as exact strings, so "1/3" survives the round trip
+
+func rationalString(r *domain.Rational) *string {
+	if r == nil {
+		return nil
+	}
+	s := r.String()
+	return &s
+}
+
+func parseRational(field string, s *string) (*domain.Rational, error) {
+	if s == nil {
+		return nil, nil
+	}
+	r, err := domain.ParseRational(*s)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %s: %w", field, err)
+	}
+	return &r, nil
+}
+
+func toProtoUom(uom *domain.Uom) *okrav1.Uom {
+	if uom == nil {
+		return nil
+	}
+	f := &okrav1.UomFields{
+		Label:                     uom.Label,
+		Enabled:                   uom.Enabled,
+		MeasureType:               uom.MeasureType,
+		Group:                     uom.Group,
+		GroupMin:                  rationalString(uom.GroupMin),
+		GroupMax:                  rationalString(uom.GroupMax),
+		SnapAmount:                make([]string, len(uom.SnapAmount)),
+		SnapSelect:                rationalString(uom.SnapSelect),
+		PivotRatio:                rationalString(uom.PivotRatio),
+		PackageAmount:             rationalString(uom.PackageAmount),
+		PackageUom:                uom.PackageUom,
+		MatchNamesRecipe:          uom.MatchNamesRecipe,
+		MatchNamesFoodLabel:       uom.MatchNamesFoodLabel,
+		DefaultNameType:           uom.PrintedNameDefaultType,
+		ShortNameSingular:         uom.PrintedNameShortSingular,
+		ShortNamePlural:           uom.PrintedNameShortPlural,
+		FullNameSingular:          uom.PrintedNameFullSingular,
+		FullNamePlural:            uom.PrintedNameFullPlural,
+		DisambiguatedNameSingular: uom.PrintedNameDisambiguatedSingular,
+		DisambiguatedNamePlural:   uom.PrintedNameDisambiguatedPlural,
+	}
+	for i, a := range uom.SnapAmount {
+		f.SnapAmount[i] = a.String()
+	}
+	if info := uom.AdditionalInfo; info != nil {
+		f.Systems = info.Systems
+		f.NameGroup = info.NameGroup
+	}
+	if len(uom.Locales) > 0 {
+		f.Locales = make(map[string]*okrav1.UomLocale, len(uom.Locales))
+		for tag, l := range uom.Locales {
+			f.Locales[tag] = &okrav1.UomLocale{
+				ShortNames:         toProtoNames(l.ShortNames),
+				FullNames:          toProtoNames(l.FullNames),
+				DisambiguatedNames: toProtoNames(l.DisambiguatedNames),
+				MatchNamesRecipe:   l.MatchNamesRecipe,
+			}
+		}
+	}
+	return &okrav1.Uom{Id: uom.Id, Fields: f}
+}
+
+func toProtoNames(names map[domain.UomPluralCategory]string) *okrav1.PluralNames {
+	if len(names) == 0 {
+		return nil
+	}
+	return &okrav1.PluralNames{Names: names}
+}
+
+// toBaseUom reads the editable fields of a uom. Validation is left to the use cases.
+func toBaseUom(f *okrav1.UomFields) (*domain.BaseUom, error) {
+	if f == nil {
+		return nil, fmt.Errorf("validation failed: uom is required")
+	}
+	base := &domain.BaseUom{
+		Label:                            f.Label,
+		Enabled:                          f.Enabled,
+		MeasureType:                      f.MeasureType,
+		Group:                            f.Group,
+		SnapAmount:                       make([]domain.Rational, len(f.SnapAmount)),
+		PackageUom:                       f.PackageUom,
+		MatchNamesRecipe:                 f.MatchNamesRecipe,
+		MatchNamesFoodLabel:              f.MatchNamesFoodLabel,
+		PrintedNameDefaultType:           f.DefaultNameType,
+		PrintedNameShortSingular:         f.ShortNameSingular,
+		PrintedNameShortPlural:           f.ShortNamePlural,
+		PrintedNameFullSingular:          f.FullNameSingular,
+		PrintedNameFullPlural:            f.FullNamePlural,
+		PrintedNameDisambiguatedSingular: f.DisambiguatedNameSingular,
+		PrintedNameDisambiguatedPlural:   f.DisambiguatedNamePlural,
+	}
+
+	var err error
+	for _, r := range []struct {
+		field string
+		in    *string
+		out   **domain.Rational
+	}{
+		{"group_min", f.GroupMin, &base.GroupMin},
+		{"group_max", f.GroupMax, &base.GroupMax},
+		{"snap_select", f.SnapSelect, &base.SnapSelect},
+		{"pivot_ratio", f.PivotRatio, &base.PivotRatio},
+		{"package_amount", f.PackageAmount, &base.PackageAmount},
+	} {
+		if *r.out, err = parseRational(r.field, r.in); err != nil {
+			return nil, err
+		}
+	}
+	for i, s := range f.SnapAmount {
+		a, err := parseRational("snap_amount", &s)
+		if err != nil {
+			return nil, err
+		}
+		base.SnapAmount[i] = *a
+	}
+
+	if len(f.Systems) > 0 || f.NameGroup != nil {
+		base.AdditionalInfo = &domain.UomAdditionalInfo{Systems: f.Systems, NameGroup: f.NameGroup}
+	}
+	if len(f.Locales) > 0 {
+		base.Locales = make(map[string]domain.UomLocale, len(f.Locales))
+		for tag, l := range f.Locales {
+			base.Locales[tag] = domain.UomLocale{
+				ShortNames:         l.GetShortNames().GetNames(),
+				FullNames:          l.GetFullNames().GetNames(),
+				DisambiguatedNames: l.GetDisambiguatedNames().GetNames(),
+				MatchNamesRecipe:   l.GetMatchNamesRecipe(),
+			}
+		}
+	}
+	return base, nil
+}
+
+func toProtoQuantity(q domain.Quantity, locales []language.Tag) *okrav1.Quantity {
+	resp := &okrav1.Quantity{
+		Approximate: q.Approximate,
+		Qualitative: q.Qualitative,
+		Text:        domain.FormatQuantity(q, domain.FormatOptions{Locales: locales}),
+	}
+	if !q.IsQualitative() {
+		resp.Amount = q.Min.String()
+		if q.IsRange() {
+			resp.Max = rationalString(&q.Max)
+		}
+	}
+	if q.Uom != nil {
+		resp.UomId = q.Uom.Id
+		resp.Uom = q.Uom.Label
+	}
+	return resp
+}
+
+// parseLocales reads BCP 47 tags, most preferred first, skipping malformed ones like
+// a malformed Accept-Language header is ignored over REST
+func parseLocales(tags []string) []language.Tag {
+	var locales []language.Tag
+	for _, t := range tags {
+		if tag, err := language.Parse(t); err == nil {
+			locales = append(locales, tag)
+		}
+	}
+	return locales
+}
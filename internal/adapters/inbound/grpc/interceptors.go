@@ -0,0 +1,236 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	okrav1 "github.com/jeffjlins/okra/api/proto/okra/v1"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionpbalpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys, the gRPC counterparts of the REST headers
+const (
+	requestIDKey = "x-request-id"
+	apiKeyKey    = "x-api-key"
+	tenantKey    = "x-tenant-id"
+)
+
+const maxRequestIDLength = 128
+
+// methodRoles is the role each okra method needs. Writes are further restricted to
+// admins when they target the global catalog. With auth on, a method that isn't listed
+// here is refused, unless its service is one of openServices.
+var methodRoles = map[string]auth.Role{
+	okrav1.UomService_CreateUom_FullMethodName:     auth.ROLE_EDITOR,
+	okrav1.UomService_GetUom_FullMethodName:        auth.ROLE_READER,
+	okrav1.UomService_GetUomByLabel_FullMethodName: auth.ROLE_READER,
+	okrav1.UomService_ListUoms_FullMethodName:      auth.ROLE_READER,
+	okrav1.UomService_UpdateUom_FullMethodName:     auth.ROLE_EDITOR,
+	okrav1.UomService_DeleteUom_FullMethodName:     auth.ROLE_EDITOR,
+	okrav1.UomService_ParseUoms_FullMethodName:     auth.ROLE_READER,
+	okrav1.UomService_Convert_FullMethodName:       auth.ROLE_READER,
+}
+
+// openServices need no credentials: load balancers and tools like grpcurl call them
+// before they have any
+var openServices = map[string]bool{
+	healthpb.Health_ServiceDesc.ServiceName:                    true,
+	reflectionpb.ServerReflection_ServiceDesc.ServiceName:      true,
+	reflectionpbalpha.ServerReflection_ServiceDesc.ServiceName: true,
+}
+
+// isOpenMethod reports whether a full method name, e.g. "/grpc.health.v1.Health/Check",
+// belongs to one of openServices
+func isOpenMethod(fullMethod string) bool {
+	service, _, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return ok && openServices[service]
+}
+
+// logRequests gives every call a request id, taken from the x-request-id metadata when
+// the caller sent a usable one and echoed in the response header, and logs the call
+func logRequests(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		id := firstMetadata(ctx, requestIDKey)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+
+		reqLogger := logger.With("request_id", id)
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			reqLogger = reqLogger.With("trace_id", sc.TraceID().String())
+		}
+		ctx = logging.WithRequestID(ctx, id)
+		ctx = logging.WithLogger(ctx, reqLogger)
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		if code == codes.Internal || code == codes.Unknown {
+			level = slog.LevelError
+		}
+		reqLogger.Log(ctx, level, "rpc",
+			"method", info.FullMethod,
+			"code", code.String(),
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+		)
+		return resp, err
+	}
+}
+
+// recoverPanics turns a panicking handler into an Internal error instead of a crash
+func recoverPanics(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			logging.FromContext(ctx).Error("handler panicked",
+				"panic", fmt.Sprint(v),
+				"stack", string(debug.Stack()),
+			)
+			err = status.Error(codes.Internal, "the server hit an unexpected error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// authorizer applies the REST adapter's rules to gRPC calls
+type authorizer struct {
+	Options
+}
+
+// authorize authenticates the caller and checks the method's role. Anonymous callers
+// only get reader methods, and only with PublicReads. Methods without a role are
+// refused, so a method added to the proto isn't open until it's given one.
+func (a authorizer) authorize(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if a.Authenticator == nil || isOpenMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	role, guarded := methodRoles[info.FullMethod]
+	if !guarded {
+		logging.FromContext(ctx).Error("refusing a method without a role", "method", info.FullMethod)
+		return nil, status.Error(codes.PermissionDenied, "method "+info.FullMethod+" is not available")
+	}
+
+	creds, err := callCredentials(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	var principal *auth.Principal
+	if creds != (auth.Credentials{}) {
+		principal, err = a.Authenticator.Authenticate(ctx, creds)
+		if err != nil {
+			logging.FromContext(ctx).Warn("authentication failed", "error", err)
+			return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+		}
+		ctx = auth.WithPrincipal(ctx, principal)
+		ctx = logging.With(ctx, "subject", principal.Subject)
+	}
+
+	if principal == nil {
+		if role == auth.ROLE_READER && a.PublicReads {
+			return handler(ctx, req)
+		}
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if !principal.Allows(role) {
+		return nil, status.Error(codes.PermissionDenied, "role "+string(role)+" required")
+	}
+	return handler(ctx, req)
+}
+
+// authorizeStream refuses streaming calls other than to openServices: no okra method
+// streams, and one that did would need its own role checks
+func (a authorizer) authorizeStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if a.Authenticator == nil || isOpenMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	return status.Error(codes.PermissionDenied, "method "+info.FullMethod+" is not available")
+}
+
+// requireGlobalAdmin keeps editors from changing the global catalog. It runs after
+// withTenant, once the call's tenant is known.
+func (a authorizer) requireGlobalAdmin(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if a.Authenticator != nil && methodRoles[info.FullMethod] == auth.ROLE_EDITOR &&
+		domain.TenantFromContext(ctx) == "" && !auth.PrincipalFromContext(ctx).Allows(auth.ROLE_ADMIN) {
+		return nil, status.Error(codes.PermissionDenied, "role admin required to change the global catalog")
+	}
+	return handler(ctx, req)
+}
+
+// withTenant scopes the call to the tenant in the x-tenant-id metadata, which callers
+// bound to a tenant may only set to their own. Callers without a tenant binding may
+// name any tenant. With auth on, anonymous calls act on the global catalog whatever
+// tenant they name.
+func (a authorizer) withTenant(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	tenant := firstMetadata(ctx, tenantKey)
+	principal := auth.PrincipalFromContext(ctx)
+	switch {
+	case principal != nil && principal.Tenant != "":
+		if tenant != "" && tenant != principal.Tenant {
+			return nil, status.Error(codes.PermissionDenied, "credentials are limited to tenant "+principal.Tenant)
+		}
+		tenant = principal.Tenant
+	case principal == nil && a.Authenticator != nil:
+		tenant = ""
+	}
+	if tenant == "" {
+		return handler(ctx, req)
+	}
+	if err := domain.ValidateTenant(tenant); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ctx = domain.WithTenant(ctx, tenant)
+	ctx = logging.With(ctx, "tenant", tenant)
+	return handler(ctx, req)
+}
+
+// callCredentials reads the api key and a bearer token from the call's metadata
+func callCredentials(ctx context.Context) (auth.Credentials, error) {
+	creds := auth.Credentials{APIKey: firstMetadata(ctx, apiKeyKey)}
+	if header := firstMetadata(ctx, "authorization"); header != "" {
+		scheme, token, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			return auth.Credentials{}, errors.New("unsupported authorization scheme")
+		}
+		creds.BearerToken = strings.TrimSpace(token)
+	}
+	return creds, nil
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// validRequestID accepts up to 128 printable ASCII characters, so caller-supplied ids
+// can't inject anything into logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	okrav1 "github.com/jeffjlins/okra/api/proto/okra/v1"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth/authtest"
+	"github.com/jeffjlins/okra/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// incoming returns a context carrying the metadata pairs, as the server sees them
+func incoming(principal *auth.Principal, kv ...string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+	if principal != nil {
+		ctx = auth.WithPrincipal(ctx, principal)
+	}
+	return ctx
+}
+
+func TestWithTenant(t *testing.T) {
+	editor := &auth.Principal{Subject: "app", Role: auth.ROLE_EDITOR}
+	bound := &auth.Principal{Subject: "household-app", Role: auth.ROLE_EDITOR, Tenant: "household-1"}
+	authOn := Options{Authenticator: authtest.Authenticator{}, PublicReads: true}
+
+	tests := []struct {
+		name       string
+		opts       Options
+		principal  *auth.Principal
+		tenant     string
+		wantCode   codes.Code
+		wantTenant string
+	}{
+		{"auth off, no tenant", Options{}, nil, "", codes.OK, ""},
+		{"auth off, tenant", Options{}, nil, "household-1", codes.OK, "household-1"},
+		{"anonymous, no tenant", authOn, nil, "", codes.OK, ""},
+		{"anonymous names a tenant", authOn, nil, "household-1", codes.OK, ""},
+		{"unbound credentials name any tenant", authOn, editor, "household-2", codes.OK, "household-2"},
+		{"bound credentials, no tenant", authOn, bound, "", codes.OK, "household-1"},
+		{"bound credentials, other tenant", authOn, bound, "household-2", codes.PermissionDenied, ""},
+		{"invalid tenant", authOn, editor, "no/slashes", codes.InvalidArgument, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kv []string
+			if tt.tenant != "" {
+				kv = append(kv, tenantKey, tt.tenant)
+			}
+			var gotTenant string
+			_, err := authorizer{tt.opts}.withTenant(incoming(tt.principal, kv...), nil, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, _ any) (any, error) {
+					gotTenant = domain.TenantFromContext(ctx)
+					return nil, nil
+				})
+			if code := status.Code(err); code != tt.wantCode || gotTenant != tt.wantTenant {
+				t.Errorf("code %v, tenant %q; want %v, %q (%v)", code, gotTenant, tt.wantCode, tt.wantTenant, err)
+			}
+		})
+	}
+}
+
+func TestEveryMethodHasARole(t *testing.T) {
+	desc := okrav1.UomService_ServiceDesc
+	for _, m := range desc.Methods {
+		if _, ok := methodRoles["/"+desc.ServiceName+"/"+m.MethodName]; !ok {
+			t.Errorf("%s.%s has no role in methodRoles", desc.ServiceName, m.MethodName)
+		}
+	}
+	if len(desc.Streams) > 0 {
+		t.Errorf("%s has streaming methods, which authorizeStream refuses", desc.ServiceName)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	authOn := Options{Authenticator: authtest.Authenticator{Principal: &auth.Principal{Subject: "app", Role: auth.ROLE_READER}}, PublicReads: true}
+	tests := []struct {
+		name     string
+		opts     Options
+		method   string
+		apiKey   string
+		wantCode codes.Code
+	}{
+		{"auth off", Options{}, "/okra.v1.UomService/NewMethod", "", codes.OK},
+		{"anonymous reader method", authOn, okrav1.UomService_GetUom_FullMethodName, "", codes.OK},
+		{"anonymous editor method", authOn, okrav1.UomService_CreateUom_FullMethodName, "", codes.Unauthenticated},
+		{"reader calls editor method", authOn, okrav1.UomService_CreateUom_FullMethodName, "key", codes.PermissionDenied},
+		{"method without a role", authOn, "/okra.v1.UomService/NewMethod", "key", codes.PermissionDenied},
+		{"method of another okra service", authOn, "/okra.v1.AdminService/Drop", "", codes.PermissionDenied},
+		{"unknown service", authOn, "/other.Service/Call", "", codes.PermissionDenied},
+		{"health check", authOn, "/grpc.health.v1.Health/Check", "", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kv []string
+			if tt.apiKey != "" {
+				kv = append(kv, apiKeyKey, tt.apiKey)
+			}
+			_, err := authorizer{tt.opts}.authorize(incoming(nil, kv...), nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(context.Context, any) (any, error) { return nil, nil })
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("authorize(%s) = %v, want %v", tt.method, err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAuthorizeStream(t *testing.T) {
+	a := authorizer{Options{Authenticator: authtest.Authenticator{}}}
+	tests := []struct {
+		method   string
+		wantCode codes.Code
+	}{
+		{"/grpc.health.v1.Health/Watch", codes.OK},
+		{"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", codes.OK},
+		{"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", codes.OK},
+		{"/okra.v1.UomService/WatchUoms", codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		err := a.authorizeStream(nil, nil, &grpc.StreamServerInfo{FullMethod: tt.method},
+			func(any, grpc.ServerStream) error { return nil })
+		if code := status.Code(err); code != tt.wantCode {
+			t.Errorf("authorizeStream(%s) = %v, want %v", tt.method, err, tt.wantCode)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Package grpc serves the okra.v1 gRPC API, defined in api/proto/okra/v1, from the
+// same use cases as the REST adapter, with the same authentication and tenant rules
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"net"
+
+	okrav1 "github.com/jeffjlins/okra/api/proto/okra/v1"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/usecase"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Options configures authentication and logging of the gRPC server
+type Options struct {
+	Authenticator auth.Authenticator // nil turns auth off: every method is open to anyone
+	PublicReads   bool               // let anonymous callers use the reader methods
+	Logger        *slog.Logger       // Optional: base logger for call logs, defaults to slog.Default()
+}
+
+// Server is a gRPC server with okra.v1.UomService, the standard health service and
+// server reflection
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+func NewServer(
+	uomService *usecase.UomService,
+	conversionService *usecase.ConversionService,
+	opts Options,
+) *Server {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	a := authorizer{opts}
+
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			logRequests(logger),
+			recoverPanics,
+			a.authorize,
+			a.withTenant,
+			a.requireGlobalAdmin,
+		),
+		grpc.StreamInterceptor(a.authorizeStream),
+	)
+	okrav1.RegisterUomServiceServer(server, &uomServer{
+		uomService:        uomService,
+		conversionService: conversionService,
+	})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(okrav1.UomService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return &Server{server: server, health: healthServer}
+}
+
+// Serve accepts connections on lis until Shutdown
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// Shutdown reports NOT_SERVING to health checks and waits for calls in flight to
+// finish. When ctx ends first the remaining calls are cut off.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	okrav1 "github.com/jeffjlins/okra/api/proto/okra/v1"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/jeffjlins/okra/internal/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// uomServer serves okra.v1.UomService from the same use cases as the REST routes
+type uomServer struct {
+	okrav1.UnimplementedUomServiceServer
+	uomService        *usecase.UomService
+	conversionService *usecase.ConversionService
+}
+
+func (s *uomServer) CreateUom(ctx context.Context, req *okrav1.CreateUomRequest) (*okrav1.Uom, error) {
+	base, err := toBaseUom(req.GetUom())
+	if err != nil {
+		return nil, toStatus(ctx, "failed to create uom", err)
+	}
+	uom, err := s.uomService.CreateUom(ctx, base)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to create uom", err)
+	}
+	return toProtoUom(uom), nil
+}
+
+func (s *uomServer) GetUom(ctx context.Context, req *okrav1.GetUomRequest) (*okrav1.Uom, error) {
+	uom, err := s.uomService.GetUomByID(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(ctx, "failed to get uom", err)
+	}
+	return toProtoUom(uom), nil
+}
+
+func (s *uomServer) GetUomByLabel(ctx context.Context, req *okrav1.GetUomByLabelRequest) (*okrav1.Uom, error) {
+	uom, err := s.uomService.GetUomByLabel(ctx, req.GetLabel())
+	if err != nil {
+		return nil, toStatus(ctx, "failed to get uom", err)
+	}
+	return toProtoUom(uom), nil
+}
+
+func (s *uomServer) ListUoms(ctx context.Context, req *okrav1.ListUomsRequest) (*okrav1.ListUomsResponse, error) {
+	system, err := domain.ParseUomSystem(req.GetSystem())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	uoms, err := s.uomService.GetAllUoms(ctx, system)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to get all uoms", err)
+	}
+	resp := &okrav1.ListUomsResponse{Uoms: make([]*okrav1.Uom, len(uoms))}
+	for i, uom := range uoms {
+		resp.Uoms[i] = toProtoUom(uom)
+	}
+	return resp, nil
+}
+
+func (s *uomServer) UpdateUom(ctx context.Context, req *okrav1.UpdateUomRequest) (*okrav1.Uom, error) {
+	base, err := toBaseUom(req.GetUom())
+	if err != nil {
+		return nil, toStatus(ctx, "failed to update uom", err)
+	}
+	uom, err := s.uomService.UpdateUom(ctx, req.GetId(), base)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to update uom", err)
+	}
+	return toProtoUom(uom), nil
+}
+
+func (s *uomServer) DeleteUom(ctx context.Context, req *okrav1.DeleteUomRequest) (*okrav1.DeleteUomResponse, error) {
+	if err := s.uomService.DeleteUom(ctx, req.GetId()); err != nil {
+		return nil, toStatus(ctx, "failed to delete uom", err)
+	}
+	return &okrav1.DeleteUomResponse{}, nil
+}
+
+func (s *uomServer) ParseUoms(ctx context.Context, req *okrav1.ParseUomsRequest) (*okrav1.ParseUomsResponse, error) {
+	matches, err := s.uomService.MatchUoms(ctx, req.GetText(), parseLocales(req.GetLocales()))
+	if err != nil {
+		return nil, toStatus(ctx, "failed to parse uoms", err)
+	}
+	resp := &okrav1.ParseUomsResponse{Matches: make([]*okrav1.UomMatch, len(matches))}
+	for i, m := range matches {
+		resp.Matches[i] = &okrav1.UomMatch{
+			Uom:   toProtoUom(m.Uom),
+			Name:  m.Name,
+			Text:  m.Text,
+			Start: int32(m.Start),
+			End:   int32(m.End),
+		}
+	}
+	return resp, nil
+}
+
+func (s *uomServer) Convert(ctx context.Context, req *okrav1.ConvertRequest) (*okrav1.Quantity, error) {
+	amount, err := parseRational("amount", &req.Amount)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to convert", err)
+	}
+	max, err := parseRational("max", req.Max)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to convert", err)
+	}
+	system, err := domain.ParseUomSystem(req.GetSystem())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	q := usecase.QuantityLine{Amount: amount, Max: max, Approximate: req.GetApproximate(), Uom: req.GetFrom()}
+	var result domain.Quantity
+	if req.GetTo() != "" {
+		result, err = s.conversionService.Convert(ctx, q, req.GetTo())
+	} else {
+		result, err = s.conversionService.Humanize(ctx, q, system)
+	}
+	if err != nil {
+		return nil, toStatus(ctx, "failed to convert", err)
+	}
+	return toProtoQuantity(result, parseLocales(req.GetLocales())), nil
+}
+
+// toStatus maps use case errors to status codes by the same phrases the REST adapter
+// maps to HTTP statuses. Unexpected errors are logged and hidden behind msg.
+func toStatus(ctx context.Context, msg string, err error) error {
+	errStr := err.Error()
+	switch {
+	case strings.Contains(errStr, "not found"):
+		return status.Error(codes.NotFound, errStr)
+	case strings.Contains(errStr, "validation failed"), strings.Contains(errStr, "cannot convert"):
+		return status.Error(codes.InvalidArgument, errStr)
+	case strings.Contains(errStr, "already exists"):
+		return status.Error(codes.AlreadyExists, errStr)
+	case strings.Contains(errStr, "read-only"):
+		return status.Error(codes.PermissionDenied, errStr)
+	}
+	logging.FromContext(ctx).Error(msg, "error", err)
+	return status.Error(codes.Internal, msg)
+}
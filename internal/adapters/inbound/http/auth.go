@@ -0,0 +1,108 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+)
+
+// apiKeyHeader carries a static api key; JWTs use the standard Authorization: Bearer header
+const apiKeyHeader = "X-API-Key"
+
+// AuthOptions configures authentication and per-route roles
+type AuthOptions struct {
+	Authenticator auth.Authenticator // nil turns auth off: every route is open to anyone
+	PublicReads   bool               // let anonymous callers use the reader routes
+}
+
+type authorizer struct {
+	AuthOptions
+}
+
+// authenticate puts the caller identified by the request's credentials in its context.
+// Requests without credentials pass through anonymously and are turned away by require
+// where a role is needed; credentials that don't check out are always rejected.
+func (a authorizer) authenticate(next http.Handler) http.Handler {
+	if a.Authenticator == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creds, ok := requestCredentials(r)
+		if !ok {
+			writeUnauthorized(w, "unsupported authorization scheme")
+			return
+		}
+		if creds == (auth.Credentials{}) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		principal, err := a.Authenticator.Authenticate(r.Context(), creds)
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("authentication failed", "error", err)
+			writeUnauthorized(w, "invalid credentials")
+			return
+		}
+		ctx := auth.WithPrincipal(r.Context(), principal)
+		ctx = logging.With(ctx, "subject", principal.Subject)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// require lets callers with at least the role through. Anonymous callers are only let
+// through reader routes, and only with PublicReads.
+func (a authorizer) require(role auth.Role, next http.HandlerFunc) http.HandlerFunc {
+	if a.Authenticator == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := auth.PrincipalFromContext(r.Context())
+		if principal == nil {
+			if role == auth.ROLE_READER && a.PublicReads {
+				next(w, r)
+				return
+			}
+			writeUnauthorized(w, "authentication required")
+			return
+		}
+		if !principal.Allows(role) {
+			writeJSONError(w, http.StatusForbidden, "role "+string(role)+" required")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireWrite guards uom writes: editors may change their tenant's uoms, but only
+// admins may change the global catalog (a write without a tenant)
+func (a authorizer) requireWrite(next http.HandlerFunc) http.HandlerFunc {
+	return a.require(auth.ROLE_EDITOR, func(w http.ResponseWriter, r *http.Request) {
+		if a.Authenticator != nil && domain.TenantFromContext(r.Context()) == "" &&
+			!auth.PrincipalFromContext(r.Context()).Allows(auth.ROLE_ADMIN) {
+			writeJSONError(w, http.StatusForbidden, "role admin required to change the global catalog")
+			return
+		}
+		next(w, r)
+	})
+}
+
+// requestCredentials reads the api key header and a bearer token. It reports false for
+// an Authorization header with any other scheme.
+func requestCredentials(r *http.Request) (auth.Credentials, bool) {
+	creds := auth.Credentials{APIKey: r.Header.Get(apiKeyHeader)}
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			return auth.Credentials{}, false
+		}
+		creds.BearerToken = strings.TrimSpace(token)
+	}
+	return creds, true
+}
+
+func writeUnauthorized(w http.ResponseWriter, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="okra"`)
+	writeJSONError(w, http.StatusUnauthorized, msg)
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type strictJSONKey struct{}
+
+// withBodyLimits caps request bodies at maxBytes (0 leaves them unbounded) and, with
+// strict, makes decodeJSON reject fields the request types don't know
+func withBodyLimits(maxBytes int64, strict bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if maxBytes > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		}
+		if strict {
+			r = r.WithContext(context.WithValue(r.Context(), strictJSONKey{}, true))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decodeJSON reads the request body into v, honoring the limits set by withBodyLimits
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	if strict, _ := r.Context().Value(strictJSONKey{}).(bool); strict {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(v)
+}
+
+// writeDecodeError answers 413 for a body over the limit and 400 for anything else
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large: the limit is %d bytes", tooLarge.Limit))
+		return
+	}
+	writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %v", err))
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/jeffjlins/okra/internal/usecase"
+	"golang.org/x/text/language"
+)
+
+// convertRequest converts an amount, or a range when max is set
+type convertRequest struct {
+	Amount      domain.Rational  `json:"amount"` // a number, or a string like "1/3" or "1 ⅓"
+	Max         *domain.Rational `json:"max,omitempty"`
+	Approximate bool             `json:"approximate,omitempty"`
+	From        string           `json:"from"`
+	To          string           `json:"to,omitempty"`     // uom id, label or match name
+	System      string           `json:"system,omitempty"` // used when To is empty: pick the best uom in this system
+}
+
+type humanizeRequest struct {
+	Amount      domain.Rational  `json:"amount"`
+	Max         *domain.Rational `json:"max,omitempty"`
+	Approximate bool             `json:"approximate,omitempty"`
+	Uom         string           `json:"uom"`
+	System      string           `json:"system,omitempty"`
+}
+
+type formatItem struct {
+	Amount      domain.Rational  `json:"amount"`
+	Max         *domain.Rational `json:"max,omitempty"`
+	Approximate bool             `json:"approximate,omitempty"`
+	Uom         string           `json:"uom"`
+}
+
+func (i formatItem) toQuantityLine() usecase.QuantityLine {
+	return usecase.QuantityLine{Amount: &i.Amount, Max: i.Max, Approximate: i.Approximate, Uom: i.Uom}
+}
+
+// formatRequest takes either a single amount or a list of items formatted together
+type formatRequest struct {
+	formatItem
+	Items        []formatItem `json:"items,omitempty"`
+	Disambiguate string       `json:"disambiguate,omitempty"` // auto (default), always or never
+}
+
+type formatListResponse struct {
+	Items []*quantityResponse `json:"items"`
+}
+
+func newFormattedQuantityResponse(q domain.Quantity, locales []language.Tag) *quantityResponse {
+	return newQuantityResponse(q, domain.FormatQuantity(q, domain.FormatOptions{Locales: locales}))
+}
+
+func convertHandler(conversionService *usecase.ConversionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		var req convertRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		system, err := domain.ParseUomSystem(req.System)
+		if err != nil {
+			writeConversionError(w, fmt.Errorf("validation failed: %w", err))
+			return
+		}
+
+		ctx := r.Context()
+		q := usecase.QuantityLine{Amount: &req.Amount, Max: req.Max, Approximate: req.Approximate, Uom: req.From}
+		var result domain.Quantity
+		if req.To != "" {
+			result, err = conversionService.Convert(ctx, q, req.To)
+		} else {
+			result, err = conversionService.Humanize(ctx, q, system)
+		}
+		if err != nil {
+			logging.FromContext(r.Context()).Error("failed to convert uom", "error", err)
+			writeConversionError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(newFormattedQuantityResponse(result, requestLocales(r)))
+	}
+}
+
+func humanizeHandler(conversionService *usecase.ConversionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		var req humanizeRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		system, err := domain.ParseUomSystem(req.System)
+		if err != nil {
+			writeConversionError(w, fmt.Errorf("validation failed: %w", err))
+			return
+		}
+
+		ctx := r.Context()
+		q := usecase.QuantityLine{Amount: &req.Amount, Max: req.Max, Approximate: req.Approximate, Uom: req.Uom}
+		result, err := conversionService.Humanize(ctx, q, system)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("failed to humanize uom", "error", err)
+			writeConversionError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(newFormattedQuantityResponse(result, requestLocales(r)))
+	}
+}
+
+func formatHandler(conversionService *usecase.ConversionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		var req formatRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		disambiguate, err := domain.ParseUomDisambiguation(req.Disambiguate)
+		if err != nil {
+			writeConversionError(w, fmt.Errorf("validation failed: %w", err))
+			return
+		}
+
+		single := len(req.Items) == 0
+		items := make([]usecase.QuantityLine, 0, len(req.Items)+1)
+		if single {
+			items = append(items, req.toQuantityLine())
+		}
+		for _, item := range req.Items {
+			items = append(items, item.toQuantityLine())
+		}
+
+		ctx := r.Context()
+		opts := domain.FormatOptions{Locales: requestLocales(r), Disambiguate: disambiguate}
+		quantities, texts, err := conversionService.Format(ctx, items, opts)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("failed to format uom", "error", err)
+			writeConversionError(w, err)
+			return
+		}
+
+		resp := formatListResponse{Items: make([]*quantityResponse, len(quantities))}
+		for i, q := range quantities {
+			resp.Items[i] = newQuantityResponse(q, texts[i])
+		}
+		if single {
+			json.NewEncoder(w).Encode(resp.Items[0])
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func writeConversionError(w http.ResponseWriter, err error) {
+	statusCode := http.StatusInternalServerError
+	errorMsg := "Failed to convert"
+
+	errStr := err.Error()
+	if strings.Contains(errStr, "not found") {
+		statusCode = http.StatusNotFound
+		errorMsg = errStr
+	} else if strings.Contains(errStr, "validation failed") || strings.Contains(errStr, "cannot convert") {
+		statusCode = http.StatusBadRequest
+		errorMsg = errStr
+	}
+
+	writeJSONError(w, statusCode, errorMsg)
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions lets browser apps on other origins, e.g. the admin UI, call the API
+type CORSOptions struct {
+	AllowedOrigins   []string      // origins allowed to call, "*" for any. Empty turns CORS off
+	AllowedMethods   []string      // Optional: defaults to GET, POST, PUT and DELETE
+	AllowedHeaders   []string      // Optional: defaults to the headers the API reads
+	AllowCredentials bool          // let browsers send cookies and authorization headers; ignored for "*"
+	MaxAge           time.Duration // Optional: how long browsers may cache a preflight answer
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+	defaultCORSHeaders = []string{"Accept-Language", "Authorization", "Content-Type", apiKeyHeader, requestIDHeader, tenantHeader}
+)
+
+// withCORS adds the CORS headers for allowed origins and answers their preflight
+// requests itself, before they reach authentication
+func withCORS(opts CORSOptions, next http.Handler) http.Handler {
+	if len(opts.AllowedOrigins) == 0 {
+		return next
+	}
+	anyOrigin := slices.Contains(opts.AllowedOrigins, "*")
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers := opts.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if origin == "" || !(anyOrigin || slices.Contains(opts.AllowedOrigins, origin)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Credentials are only allowed for listed origins: echoing any origin with them
+		// would let every site call the API as the user. Config loading rejects the mix.
+		if anyOrigin {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if opts.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+			if opts.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", requestIDHeader)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithCORS(t *testing.T) {
+	admin := "https://admin.example.com"
+	tests := []struct {
+		name            string
+		opts            CORSOptions
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{"off", CORSOptions{}, admin, "", ""},
+		{"any origin", CORSOptions{AllowedOrigins: []string{"*"}}, admin, "*", ""},
+		{"listed origin", CORSOptions{AllowedOrigins: []string{admin}}, admin, admin, ""},
+		{"listed origin with credentials", CORSOptions{AllowedOrigins: []string{admin}, AllowCredentials: true}, admin, admin, "true"},
+		{"other origin", CORSOptions{AllowedOrigins: []string{admin}, AllowCredentials: true}, "https://evil.example.com", "", ""},
+		// Never echoed with credentials, even if config loading was skipped
+		{"any origin with credentials", CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true}, "https://evil.example.com", "*", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := withCORS(tt.opts, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			for _, preflight := range []bool{false, true} {
+				req := httptest.NewRequest(http.MethodGet, "/uom", nil)
+				if preflight {
+					req = httptest.NewRequest(http.MethodOptions, "/uom", nil)
+					req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				}
+				req.Header.Set("Origin", tt.origin)
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+
+				origin := rec.Header().Get("Access-Control-Allow-Origin")
+				credentials := rec.Header().Get("Access-Control-Allow-Credentials")
+				if origin != tt.wantOrigin || credentials != tt.wantCredentials {
+					t.Errorf("preflight %v: Allow-Origin %q, Allow-Credentials %q; want %q, %q", preflight, origin, credentials, tt.wantOrigin, tt.wantCredentials)
+				}
+			}
+		})
+	}
+}
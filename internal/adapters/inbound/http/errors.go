@@ -0,0 +1,60 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// writeJSONError answers with the body every error of the API has, {"error": msg}
+func writeJSONError(w http.ResponseWriter, statusCode int, msg string) {
+	writeError(w, statusCode, errorResponse{Error: msg})
+}
+
+func writeError(w http.ResponseWriter, statusCode int, body errorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
+// withJSONRouteErrors answers requests no route matches (404) or that use a method the
+// route doesn't serve (405) with a JSON error, instead of the mux's plain text ones
+func withJSONRouteErrors(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		// The mux's answer without a pattern is an error or a redirect; only the
+		// status and headers of errors are kept
+		probe := &headerRecorder{header: make(http.Header)}
+		h.ServeHTTP(probe, r)
+		if probe.status < http.StatusBadRequest {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if allow := probe.header.Get("Allow"); allow != "" {
+			w.Header().Set("Allow", allow)
+		}
+		writeJSONError(w, probe.status, http.StatusText(probe.status))
+	})
+}
+
+// headerRecorder keeps a response's status and headers and drops its body
+type headerRecorder struct {
+	header http.Header
+	status int
+}
+
+func (r *headerRecorder) Header() http.Header { return r.header }
+
+func (r *headerRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *headerRecorder) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return len(b), nil
+}
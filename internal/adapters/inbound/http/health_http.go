@@ -1,10 +1,68 @@
 package http
 
 import (
+	"encoding/json"
 	"net/http"
+
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/jeffjlins/okra/internal/usecase"
 )
 
+type componentHealthResponse struct {
+	Name      string         `json:"name"`
+	Status    string         `json:"status"`
+	LatencyMs float64        `json:"latency_ms"`
+	Error     string         `json:"error,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+type healthResponse struct {
+	Status     string                    `json:"status"`
+	Components []componentHealthResponse `json:"components,omitempty"`
+}
+
+// healthHandler is kept for clients of the original plain text check; it behaves like /healthz
 func healthHandler(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("ok"))
-}
\ No newline at end of file
+}
+
+// livenessHandler answers as long as the process can serve requests. It checks no
+// dependency, so a broken backend doesn't get the instance restarted.
+func livenessHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(healthResponse{Status: usecase.HEALTH_UP})
+}
+
+// readinessHandler probes the dependencies and answers 503 when any of them is down,
+// so the instance is taken out of rotation until they recover
+func readinessHandler(healthService *usecase.HealthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		readiness := healthService.Readiness(r.Context())
+
+		resp := healthResponse{
+			Status:     readiness.Status,
+			Components: make([]componentHealthResponse, len(readiness.Components)),
+		}
+		for i, c := range readiness.Components {
+			resp.Components[i] = componentHealthResponse{
+				Name:      c.Name,
+				Status:    c.Status,
+				LatencyMs: float64(c.Latency.Microseconds()) / 1000,
+				Error:     c.Error,
+				Details:   c.Details,
+			}
+			if c.Status != usecase.HEALTH_UP {
+				logging.FromContext(r.Context()).Warn("readiness check failed", "component", c.Name, "error", c.Error)
+			}
+		}
+
+		statusCode := http.StatusOK
+		if readiness.Status != usecase.HEALTH_UP {
+			statusCode = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/jeffjlins/okra/internal/usecase"
+)
+
+// parseIngredientsRequest takes a single line or a list of lines
+type parseIngredientsRequest struct {
+	Text  string   `json:"text,omitempty"`
+	Lines []string `json:"lines,omitempty"`
+}
+
+type ingredientConfidenceResponse struct {
+	Overall    float64 `json:"overall"`
+	Amount     float64 `json:"amount"`
+	Uom        float64 `json:"uom"`
+	Ingredient float64 `json:"ingredient"`
+}
+
+type ingredientLineResponse struct {
+	Text        string                       `json:"text"`
+	Quantity    *quantityResponse            `json:"quantity"`
+	PackageSize *quantityResponse            `json:"package_size,omitempty"`
+	Size        string                       `json:"size,omitempty"`
+	Ingredient  string                       `json:"ingredient"`
+	Preparation string                       `json:"preparation,omitempty"`
+	Notes       string                       `json:"notes,omitempty"`
+	Confidence  ingredientConfidenceResponse `json:"confidence"`
+}
+
+type parseIngredientsResponse struct {
+	Lines []ingredientLineResponse `json:"lines"`
+}
+
+func parseIngredientsHandler(ingredientService *usecase.IngredientService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		var req parseIngredientsRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		lines := req.Lines
+		if req.Text != "" {
+			lines = append([]string{req.Text}, lines...)
+		}
+		if len(lines) == 0 {
+			writeConversionError(w, fmt.Errorf("validation failed: text or lines is required"))
+			return
+		}
+
+		ctx := r.Context()
+		locales := requestLocales(r)
+		parsed, err := ingredientService.Parse(ctx, lines, locales)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("failed to parse ingredients", "error", err)
+			writeJSONError(w, http.StatusInternalServerError, "Failed to parse ingredients")
+			return
+		}
+
+		resp := parseIngredientsResponse{Lines: make([]ingredientLineResponse, len(parsed))}
+		for i, line := range parsed {
+			item := ingredientLineResponse{
+				Text:        line.Text,
+				Size:        line.Size,
+				Ingredient:  line.Ingredient,
+				Preparation: line.Preparation,
+				Notes:       line.Notes,
+				Confidence: ingredientConfidenceResponse{
+					Overall:    line.Confidence.Overall,
+					Amount:     line.Confidence.Amount,
+					Uom:        line.Confidence.Uom,
+					Ingredient: line.Confidence.Ingredient,
+				},
+			}
+			if line.Quantity != nil {
+				item.Quantity = newFormattedQuantityResponse(*line.Quantity, locales)
+			}
+			if line.PackageSize != nil {
+				item.PackageSize = newFormattedQuantityResponse(domain.NewQuantity(line.PackageSize.Amount, line.PackageSize.Uom), locales)
+			}
+			resp.Lines[i] = item
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
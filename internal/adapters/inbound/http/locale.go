@@ -0,0 +1,27 @@
+package http
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// maxRequestLocales caps how many Accept-Language tags are used; the rest are ignored
+const maxRequestLocales = 5
+
+// requestLocales returns the locales from the Accept-Language header, most preferred first.
+// A missing or malformed header yields no preference, which means the default locale.
+func requestLocales(r *http.Request) []language.Tag {
+	header := r.Header.Get("Accept-Language")
+	if header == "" {
+		return nil
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+	if len(tags) > maxRequestLocales {
+		tags = tags[:maxRequestLocales]
+	}
+	return tags
+}
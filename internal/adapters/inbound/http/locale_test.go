@@ -0,0 +1,29 @@
+package http
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestRequestLocales(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []language.Tag
+	}{
+		{"", nil},
+		{"not a header;;", nil},
+		{"es-MX, es;q=0.8, en;q=0.5", []language.Tag{language.MustParse("es-MX"), language.Spanish, language.English}},
+		{"fr;q=0.1, de", []language.Tag{language.German, language.French}},
+		{"en, fr, de, it, pt, nl, sv, ja", []language.Tag{language.English, language.French, language.German, language.Italian, language.Portuguese}},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", tt.header)
+		if got := requestLocales(r); !slices.Equal(got, tt.want) {
+			t.Errorf("requestLocales(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,482 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+// OPENAPI_VERSION is the version of the API the spec describes, not of the server
+const OPENAPI_VERSION = "1.0.0"
+
+const (
+	tagHealth  = "Health"
+	tagUom     = "Uom"
+	tagConvert = "Conversion"
+	tagRecipe  = "Recipe"
+	tagGraphQL = "GraphQL"
+	tagSpec    = "Spec"
+)
+
+// operation documents one route of NewRouter. The spec is built from these and from
+// the request and response types the handlers encode, so it can't drift from the code:
+// NewRouter refuses to register a route without one.
+type operation struct {
+	pattern     string // as registered, e.g. "GET /uom/{id}"
+	id          string
+	tag         string
+	summary     string
+	description string
+	role        auth.Role // Optional: the least role the route requires; empty for open routes
+	query       []*openapi3.Parameter
+	request     any // Optional: the JSON request body, e.g. convertRequest{}
+	responses   []response
+}
+
+// response documents one status of an operation. A body of type string is served as
+// text/plain, any other body as JSON; no body means an empty response.
+type response struct {
+	status      int
+	description string
+	body        any
+}
+
+// errorResponse is the body of every error response
+type errorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"` // set on unexpected errors, to find them in the logs
+}
+
+// graphQLRequest is what POST /graphql accepts; the schema itself is published by the
+// GraphQL endpoint through introspection
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   any              `json:"data,omitempty"`
+	Errors []map[string]any `json:"errors,omitempty"`
+}
+
+var uomListParams = []*openapi3.Parameter{
+	{
+		Name:        "system",
+		In:          openapi3.ParameterInQuery,
+		Description: "Only list uoms of this measurement system",
+		Schema:      openapi3.NewStringSchema().WithEnum(anySlice(domain.UomSystems)...).NewRef(),
+	},
+	{
+		Name:        "limit",
+		In:          openapi3.ParameterInQuery,
+		Description: "Page size. Pages are ordered by id; without limit or after the whole list is returned",
+		Schema:      openapi3.NewIntegerSchema().WithMin(1).WithMax(maxUomPageSize).NewRef(),
+	},
+	{
+		Name:        "after",
+		In:          openapi3.ParameterInQuery,
+		Description: "Start the page after the uom with this id",
+		Schema:      openapi3.NewStringSchema().NewRef(),
+	},
+}
+
+var operations = []operation{
+	{
+		pattern: "GET /health", id: "getHealth", tag: tagHealth,
+		summary:   "Health check",
+		responses: []response{{http.StatusOK, "Always ok, like /healthz", "ok"}},
+	},
+	{
+		pattern: "GET /healthz", id: "getLiveness", tag: tagHealth,
+		summary:     "Liveness check",
+		description: "Answers as long as the process can serve requests. It checks no dependency.",
+		responses:   []response{{http.StatusOK, "The process is up", healthResponse{}}},
+	},
+	{
+		pattern: "GET /readyz", id: "getReadiness", tag: tagHealth,
+		summary:     "Readiness check",
+		description: "Probes the repository and the uom catalog.",
+		responses: []response{
+			{http.StatusOK, "Every dependency is up", healthResponse{}},
+			{http.StatusServiceUnavailable, "A dependency is down", healthResponse{}},
+		},
+	},
+	{
+		pattern: "GET /openapi.json", id: "getOpenAPI", tag: tagSpec,
+		summary:   "This OpenAPI spec",
+		responses: []response{{http.StatusOK, "The spec", map[string]any{}}},
+	},
+	{
+		pattern: "POST /uom", id: "createUom", tag: tagUom, role: auth.ROLE_EDITOR,
+		summary:     "Create a uom",
+		description: "Only admins may create uoms in the global catalog, that is without a tenant.",
+		request:     domain.BaseUom{},
+		responses: []response{
+			{http.StatusCreated, "The created uom", domain.Uom{}},
+			{http.StatusBadRequest, "The uom is invalid", errorResponse{}},
+			{http.StatusConflict, "The id, label or a match name is taken", errorResponse{}},
+		},
+	},
+	{
+		pattern: "GET /uom/{id}", id: "getUom", tag: tagUom, role: auth.ROLE_READER,
+		summary: "Get a uom by id",
+		responses: []response{
+			{http.StatusOK, "The uom", domain.Uom{}},
+			{http.StatusNotFound, "No uom has the id", errorResponse{}},
+		},
+	},
+	{
+		pattern: "GET /uom/by-label/{label}", id: "getUomByLabel", tag: tagUom, role: auth.ROLE_READER,
+		summary: "Get a uom by label",
+		responses: []response{
+			{http.StatusOK, "The uom", domain.Uom{}},
+			{http.StatusNotFound, "No uom has the label", errorResponse{}},
+		},
+	},
+	{
+		pattern: "GET /uom", id: "listUoms", tag: tagUom, role: auth.ROLE_READER,
+		summary: "List uoms",
+		query:   uomListParams,
+		responses: []response{
+			{http.StatusOK, `The uoms. When there are more, a Link header with rel="next" gives the next page`, []*domain.Uom{}},
+			{http.StatusBadRequest, "Unknown system or invalid limit", errorResponse{}},
+		},
+	},
+	{
+		pattern: "DELETE /uom/{id}", id: "deleteUom", tag: tagUom, role: auth.ROLE_EDITOR,
+		summary: "Delete a uom",
+		responses: []response{
+			{http.StatusNoContent, "Deleted", nil},
+			{http.StatusForbidden, "The uom belongs to the global catalog and can't be changed for a tenant", errorResponse{}},
+			{http.StatusNotFound, "No uom has the id", errorResponse{}},
+		},
+	},
+	{
+		pattern: "PUT /uom/{id}", id: "updateUom", tag: tagUom, role: auth.ROLE_EDITOR,
+		summary: "Replace a uom",
+		request: domain.BaseUom{},
+		responses: []response{
+			{http.StatusOK, "The updated uom", domain.Uom{}},
+			{http.StatusBadRequest, "The uom is invalid", errorResponse{}},
+			{http.StatusForbidden, "The uom belongs to the global catalog and can't be changed for a tenant", errorResponse{}},
+			{http.StatusNotFound, "No uom has the id", errorResponse{}},
+			{http.StatusConflict, "The label or a match name is taken", errorResponse{}},
+		},
+	},
+	{
+		pattern: "POST /uom/parse", id: "parseUoms", tag: tagUom, role: auth.ROLE_READER,
+		summary: "Find the uoms named in a text",
+		request: parseUomRequest{},
+		responses: []response{
+			{http.StatusOK, "The uoms found, in order", parseUomResponse{}},
+		},
+	},
+	{
+		pattern: "POST /uom/convert", id: "convert", tag: tagConvert, role: auth.ROLE_READER,
+		summary:     "Convert an amount to another uom",
+		description: "Without a target uom, picks the most natural uom of the system.",
+		request:     convertRequest{},
+		responses:   conversionResponses(quantityResponse{}),
+	},
+	{
+		pattern: "POST /uom/humanize", id: "humanize", tag: tagConvert, role: auth.ROLE_READER,
+		summary:   "Re-express an amount in the most natural uom",
+		request:   humanizeRequest{},
+		responses: conversionResponses(quantityResponse{}),
+	},
+	{
+		pattern: "POST /uom/format", id: "format", tag: tagConvert, role: auth.ROLE_READER,
+		summary:     "Print amounts",
+		description: "Answers with a single quantity for a single amount, or with a list for items.",
+		request:     formatRequest{},
+		responses:   conversionResponses(oneOf{quantityResponse{}, formatListResponse{}}),
+	},
+	{
+		pattern: "POST /recipes/scale", id: "scaleRecipe", tag: tagRecipe, role: auth.ROLE_READER,
+		summary:   "Scale a recipe",
+		request:   scaleRequest{},
+		responses: conversionResponses(scaleResponse{}),
+	},
+	{
+		pattern: "POST /shopping-list", id: "shoppingList", tag: tagRecipe, role: auth.ROLE_READER,
+		summary:   "Combine recipes into a shopping list",
+		request:   shoppingListRequest{},
+		responses: conversionResponses(shoppingListResponse{}),
+	},
+	{
+		pattern: "POST /ingredients/parse", id: "parseIngredients", tag: tagRecipe, role: auth.ROLE_READER,
+		summary: "Parse ingredient lines",
+		request: parseIngredientsRequest{},
+		responses: []response{
+			{http.StatusOK, "The parsed lines", parseIngredientsResponse{}},
+			{http.StatusBadRequest, "No text or lines", errorResponse{}},
+		},
+	},
+	{
+		pattern: "POST /graphql", id: "graphql", tag: tagGraphQL, role: auth.ROLE_READER,
+		summary:     "GraphQL queries and mutations over the uom catalog",
+		description: "Errors are reported in the body with a 200.",
+		request:     graphQLRequest{},
+		responses:   []response{{http.StatusOK, "The result", graphQLResponse{}}},
+	},
+}
+
+func conversionResponses(body any) []response {
+	return []response{
+		{http.StatusOK, "The result", body},
+		{http.StatusBadRequest, "Invalid amounts, or uoms of measure types that can't be converted", errorResponse{}},
+		{http.StatusNotFound, "A uom doesn't exist", errorResponse{}},
+	}
+}
+
+// oneOf is a body that is any of the given types
+type oneOf []any
+
+// OpenAPI builds the spec of every route the router may serve
+func OpenAPI() (*openapi3.T, error) {
+	return newOpenAPI(operations)
+}
+
+func documentedOperation(pattern string) (operation, bool) {
+	i := slices.IndexFunc(operations, func(op operation) bool { return op.pattern == pattern })
+	if i < 0 {
+		return operation{}, false
+	}
+	return operations[i], true
+}
+
+func newOpenAPI(ops []operation) (*openapi3.T, error) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "Okra API",
+			Description: "Units of measure for recipes: lookups, parsing, conversion, formatting and scaling.\n\nAmounts are accepted as numbers or as exact strings such as \"1/3\" or \"1 ⅓\".",
+			Version:     OPENAPI_VERSION,
+			License:     &openapi3.License{Name: "MIT", URL: "https://opensource.org/licenses/MIT"},
+		},
+		Servers: openapi3.Servers{{URL: "http://localhost:8080", Description: "Local development server"}},
+		Paths:   openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{schemaName(rationalType): {Value: &rationalSchema}},
+			Parameters: openapi3.ParametersMap{
+				"Tenant": {Value: openapi3.NewHeaderParameter(tenantHeader).
+					WithDescription("The tenant to act for; the global catalog without it. Ignored for anonymous callers when auth is on, and credentials bound to a tenant may only name their own").
+					WithSchema(openapi3.NewStringSchema())},
+				"AcceptLanguage": {Value: openapi3.NewHeaderParameter("Accept-Language").
+					WithDescription("Locales to print and match uom names in").
+					WithSchema(openapi3.NewStringSchema())},
+			},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"ApiKey": {Value: openapi3.NewSecurityScheme().WithType("apiKey").WithIn("header").WithName(apiKeyHeader)},
+				"Bearer": {Value: openapi3.NewJWTSecurityScheme()},
+			},
+		},
+	}
+
+	gen := openapi3gen.NewGenerator(
+		openapi3gen.CreateComponentSchemas(openapi3gen.ExportComponentSchemasOptions{ExportComponentSchemas: true, ExportTopLevelSchema: true}),
+		openapi3gen.CreateTypeNameGenerator(schemaName),
+		openapi3gen.SchemaCustomizer(customizeSchema),
+	)
+	schemaFor := func(v any) (*openapi3.SchemaRef, error) {
+		if alts, ok := v.(oneOf); ok {
+			schema := &openapi3.Schema{}
+			for _, alt := range alts {
+				ref, err := gen.NewSchemaRefForValue(alt, doc.Components.Schemas)
+				if err != nil {
+					return nil, err
+				}
+				schema.OneOf = append(schema.OneOf, ref)
+			}
+			return schema.NewRef(), nil
+		}
+		return gen.NewSchemaRefForValue(v, doc.Components.Schemas)
+	}
+	defaultError, err := schemaFor(errorResponse{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		method, path, _ := strings.Cut(op.pattern, " ")
+		o := &openapi3.Operation{
+			OperationID: op.id,
+			Tags:        []string{op.tag},
+			Summary:     op.summary,
+			Description: op.description,
+			Responses:   openapi3.NewResponses(),
+		}
+		for _, name := range pathParams(path) {
+			o.Parameters = append(o.Parameters, &openapi3.ParameterRef{Value: openapi3.NewPathParameter(name).WithSchema(openapi3.NewStringSchema())})
+		}
+		for _, p := range op.query {
+			o.Parameters = append(o.Parameters, &openapi3.ParameterRef{Value: p})
+		}
+
+		if op.request != nil {
+			schema, err := schemaFor(op.request)
+			if err != nil {
+				return nil, fmt.Errorf("%s: request: %w", op.pattern, err)
+			}
+			o.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(schema)}
+		}
+
+		o.Responses.Delete("default")
+		for _, resp := range op.responses {
+			r := openapi3.NewResponse().WithDescription(resp.description)
+			switch body := resp.body.(type) {
+			case nil:
+			case string:
+				r.WithContent(openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/plain"}))
+			default:
+				schema, err := schemaFor(body)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %d response: %w", op.pattern, resp.status, err)
+				}
+				r.WithContent(openapi3.NewContentWithJSONSchemaRef(schema))
+			}
+			o.AddResponse(resp.status, r)
+		}
+		if op.request != nil {
+			addResponse(o, http.StatusRequestEntityTooLarge, "The body is larger than the server accepts", defaultError)
+		}
+		if op.role != "" {
+			o.Security = &openapi3.SecurityRequirements{{"ApiKey": {}}, {"Bearer": {}}}
+			o.Parameters = append(o.Parameters,
+				&openapi3.ParameterRef{Ref: "#/components/parameters/Tenant", Value: doc.Components.Parameters["Tenant"].Value},
+				&openapi3.ParameterRef{Ref: "#/components/parameters/AcceptLanguage", Value: doc.Components.Parameters["AcceptLanguage"].Value},
+			)
+			addResponse(o, http.StatusUnauthorized, "Missing or invalid credentials", defaultError)
+			addResponse(o, http.StatusForbidden, "Role "+string(op.role)+" required", defaultError)
+			addResponse(o, http.StatusTooManyRequests, "Rate limited; retry after the Retry-After header", defaultError)
+		}
+		// Errors raised before a handler runs, e.g. by the panic recovery
+		o.Responses.Set("default", &openapi3.ResponseRef{Value: openapi3.NewResponse().
+			WithDescription("Unexpected error").
+			WithContent(openapi3.NewContentWithJSONSchemaRef(defaultError))})
+
+		doc.AddOperation(path, method, o)
+	}
+
+	// The generator leaves references to component schemas unresolved
+	loader := openapi3.NewLoader()
+	if err := loader.ResolveRefsIn(doc, nil); err != nil {
+		return nil, fmt.Errorf("failed to resolve OpenAPI refs: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+	return doc, nil
+}
+
+// addResponse documents a status unless the operation already does
+func addResponse(o *openapi3.Operation, status int, description string, schema *openapi3.SchemaRef) {
+	if o.Responses.Status(status) != nil {
+		return
+	}
+	o.AddResponse(status, openapi3.NewResponse().WithDescription(description).WithContent(openapi3.NewContentWithJSONSchemaRef(schema)))
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}.]+)\}`)
+
+func pathParams(path string) []string {
+	var names []string
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// schemaName names component schemas after their Go type, e.g. quantityResponse
+// becomes QuantityResponse
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+var rationalType = reflect.TypeFor[domain.Rational]()
+
+// rationalSchema is the schema of domain.Rational, which has no exported fields for the
+// generator to describe
+var rationalSchema = openapi3.Schema{
+	Description: `An exact amount: a number, or a string like "0.25", "1/3" or "1 ⅓"`,
+	OneOf: openapi3.SchemaRefs{
+		openapi3.NewStringSchema().NewRef(),
+		openapi3.NewFloat64Schema().NewRef(),
+	},
+}
+
+// customizeSchema describes the types whose JSON form differs from their Go fields,
+// lets slices and maps be null, as encoding/json writes nil ones, and requires the
+// fields of objects that are always written
+func customizeSchema(_ string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	switch {
+	case t == rationalType:
+		*schema = rationalSchema
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Map:
+		if !omitEmpty(tag) {
+			schema.Nullable = true
+		}
+	case t.Kind() == reflect.Struct && schema.Properties != nil:
+		schema.Required = requiredFields(t)
+		nullablePointers(t, schema)
+	}
+	return nil
+}
+
+// nullablePointers lets the pointer fields that refer to component schemas be null.
+// OpenAPI 3.0 ignores siblings of a $ref, so the reference is wrapped in an allOf.
+func nullablePointers(t reflect.Type, schema *openapi3.Schema) {
+	for _, f := range reflect.VisibleFields(t) {
+		if f.Type.Kind() != reflect.Pointer {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if prop := schema.Properties[name]; prop != nil && strings.HasPrefix(prop.Ref, "#/components/schemas/") {
+			schema.Properties[name] = &openapi3.SchemaRef{Value: &openapi3.Schema{
+				Nullable: true,
+				AllOf:    openapi3.SchemaRefs{prop},
+			}}
+		}
+	}
+}
+
+// requiredFields lists the JSON names of the fields without omitempty. Fields the
+// domain doesn't validate (validate:"-") and fields promoted from an unexported
+// embedded struct, such as the single amount of formatRequest, are optional.
+func requiredFields(t reflect.Type) []string {
+	var required []string
+	for _, f := range reflect.VisibleFields(t) {
+		if f.Anonymous || !f.IsExported() || len(f.Index) > 1 && !t.Field(f.Index[0]).IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" || omitEmpty(f.Tag) || f.Tag.Get("validate") == "-" {
+			continue
+		}
+		required = append(required, name)
+	}
+	return required
+}
+
+func omitEmpty(tag reflect.StructTag) bool {
+	_, opts, _ := strings.Cut(tag.Get("json"), ",")
+	return slices.Contains(strings.Split(opts, ","), "omitempty")
+}
+
+func anySlice[T any](values []T) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
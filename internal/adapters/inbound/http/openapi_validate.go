@@ -0,0 +1,101 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/jeffjlins/okra/internal/logging"
+)
+
+// withOpenAPIValidation checks the route's requests and responses against the spec.
+// Requests that don't match get a 400. Responses that don't match are logged and
+// replaced by a 500, so tests running with validation fail on any drift between the
+// handlers and the spec. Responses are buffered, which is why this is off by default.
+func withOpenAPIValidation(spec *openapi3.T, pattern string, next http.Handler) http.Handler {
+	method, path, _ := strings.Cut(pattern, " ")
+	pathItem := spec.Paths.Value(path)
+	route := &routers.Route{
+		Spec:      spec,
+		Path:      path,
+		PathItem:  pathItem,
+		Method:    method,
+		Operation: pathItem.GetOperation(method),
+	}
+	options := &openapi3filter.Options{
+		// The router authenticates; the spec's security schemes are only documentation
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		MultiError:         true,
+	}
+	// Name the offending field rather than dumping the whole schema
+	options.WithCustomSchemaErrorFunc(func(err *openapi3.SchemaError) string {
+		return "/" + strings.Join(err.JSONPointer(), "/") + ": " + err.Reason
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		params := make(map[string]string)
+		for _, name := range pathParams(path) {
+			params[name] = r.PathValue(name)
+		}
+		input := &openapi3filter.RequestValidationInput{Request: r, PathParams: params, Route: route, Options: options}
+		if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeDecodeError(w, maxBytesErr)
+				return
+			}
+			writeJSONError(w, http.StatusBadRequest, "Request doesn't match the API spec: "+err.Error())
+			return
+		}
+
+		rec := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		// Sniff the type as net/http would on the first write, which hasn't happened yet
+		if w.Header().Get("Content-Type") == "" && rec.body.Len() > 0 {
+			w.Header().Set("Content-Type", http.DetectContentType(rec.body.Bytes()))
+		}
+
+		err := openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 rec.status,
+			Header:                 w.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+			Options:                options,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("response doesn't match the API spec", "status", rec.status, "error", err)
+			writeJSONError(w, http.StatusInternalServerError, "Response doesn't match the API spec: "+err.Error())
+			return
+		}
+		w.WriteHeader(rec.status)
+		w.Write(rec.body.Bytes())
+	})
+}
+
+// bufferedResponse holds back the status and body until the response is validated.
+// Headers go straight to the underlying writer.
+type bufferedResponse struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.wroteHeader {
+		return
+	}
+	b.status = status
+	b.wroteHeader = true
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
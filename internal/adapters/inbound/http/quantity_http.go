@@ -0,0 +1,40 @@
+package http
+
+import (
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/usecase"
+)
+
+// quantityLineRequest is a recipe line as free text or as a structured quantity. A
+// range is given as amount (the lower bound) and max.
+type quantityLineRequest struct {
+	Text        string           `json:"text,omitempty"`
+	Amount      *domain.Rational `json:"amount,omitempty"` // a number, or a string like "1/3" or "1 ⅓"
+	Max         *domain.Rational `json:"max,omitempty"`
+	Approximate bool             `json:"approximate,omitempty"`
+	Qualitative string           `json:"qualitative,omitempty"` // e.g. "to taste"
+	Uom         string           `json:"uom,omitempty"`
+	Ingredient  string           `json:"ingredient,omitempty"`
+}
+
+func (l quantityLineRequest) toQuantityLine() usecase.QuantityLine {
+	return usecase.QuantityLine{
+		Text:        l.Text,
+		Amount:      l.Amount,
+		Max:         l.Max,
+		Approximate: l.Approximate,
+		Qualitative: l.Qualitative,
+		Uom:         l.Uom,
+		Ingredient:  l.Ingredient,
+	}
+}
+
+// quantityResponse is the JSON form of a quantity along with its printed form
+type quantityResponse struct {
+	domain.QuantityJSON
+	Text string `json:"text"`
+}
+
+func newQuantityResponse(q domain.Quantity, text string) *quantityResponse {
+	return &quantityResponse{QuantityJSON: q.JSON(), Text: text}
+}
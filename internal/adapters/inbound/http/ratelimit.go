@@ -0,0 +1,94 @@
+package http
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/ratelimit"
+	"github.com/jeffjlins/okra/internal/logging"
+)
+
+// RateLimitOptions meters each caller per route: authenticated callers by the name of
+// their api key or token subject, anonymous ones by client IP
+type RateLimitOptions struct {
+	Limiter        ratelimit.Limiter          // nil turns rate limiting off
+	Default        ratelimit.Limit            // applies to routes without their own limit. The zero Limit means unlimited
+	Routes         map[string]ratelimit.Limit // keyed by route pattern, e.g. "POST /ingredients/parse"
+	TrustedProxies int                        // proxies in front of the server that append to X-Forwarded-For; 0 ignores the header
+}
+
+// withRateLimit takes a token for the caller from the route's bucket, refusing the
+// request with 429 when there is none. A failing limiter lets requests through: an
+// outage of a shared store shouldn't take the API down with it.
+func withRateLimit(opts RateLimitOptions, pattern string, next http.Handler) http.Handler {
+	limit, ok := opts.Routes[pattern]
+	if !ok {
+		limit = opts.Default
+	}
+	if opts.Limiter == nil || limit.Unlimited() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, err := opts.Limiter.Take(r.Context(), pattern+" "+rateLimitKey(r, opts.TrustedProxies), limit)
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("rate limiter failed, letting the request through", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Window())))
+		if !res.Allowed {
+			retryAfter := ceilSeconds(res.RetryAfter)
+			h.Set("Retry-After", strconv.Itoa(retryAfter))
+			logging.FromContext(r.Context()).Info("rate limit exceeded", "route", pattern)
+			writeJSONError(w, http.StatusTooManyRequests, fmt.Sprintf("rate limit exceeded, retry in %ds", retryAfter))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey names the caller's bucket
+func rateLimitKey(r *http.Request, trustedProxies int) string {
+	if principal := auth.PrincipalFromContext(r.Context()); principal != nil {
+		return "subject:" + principal.Subject
+	}
+	return "ip:" + clientIP(r, trustedProxies)
+}
+
+// clientIP is the request's remote address or, behind trustedProxies proxies, the
+// address the outermost of them received the request from. Each proxy appends the
+// address it was called from to X-Forwarded-For, so that is the entry trustedProxies
+// from the right. Entries further left were sent by the client and may be forged.
+func clientIP(r *http.Request, trustedProxies int) string {
+	if trustedProxies > 0 {
+		// Proxies may add their own header line rather than extend the first one
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			entries := strings.Split(strings.Join(forwarded, ","), ",")
+			ip := strings.TrimSpace(entries[max(len(entries)-trustedProxies, 0)])
+			if net.ParseIP(ip) != nil {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ceilSeconds rounds up, so clients told to wait never come back too early
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
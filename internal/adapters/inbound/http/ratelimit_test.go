@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		forwarded      []string // X-Forwarded-For header lines
+		trustedProxies int
+		want           string
+	}{
+		{"no proxy", nil, 0, "10.0.0.1"},
+		{"header ignored without trusted proxies", []string{"203.0.113.7"}, 0, "10.0.0.1"},
+		{"one proxy", []string{"203.0.113.7"}, 1, "203.0.113.7"},
+		{"one proxy, forged entry", []string{"1.2.3.4, 203.0.113.7"}, 1, "203.0.113.7"},
+		{"two proxies", []string{"1.2.3.4, 203.0.113.7, 198.51.100.2"}, 2, "203.0.113.7"},
+		{"two header lines", []string{"1.2.3.4, 203.0.113.7", "198.51.100.2"}, 2, "203.0.113.7"},
+		{"fewer entries than proxies", []string{"203.0.113.7"}, 2, "203.0.113.7"},
+		{"ipv6", []string{"2001:db8::1"}, 1, "2001:db8::1"},
+		{"not an ip", []string{"1.2.3.4, unknown"}, 1, "10.0.0.1"},
+		{"no header", nil, 1, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/uom", nil)
+			r.RemoteAddr = "10.0.0.1:4321"
+			for _, v := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := clientIP(r, tt.trustedProxies); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/jeffjlins/okra/internal/usecase"
+)
+
+// scaleRequest takes either a factor or a from/to serving count
+type scaleRequest struct {
+	Factor       domain.Rational       `json:"factor,omitempty"` // a number, or a string like "2/3"
+	ServingsFrom domain.Rational       `json:"servings_from,omitempty"`
+	ServingsTo   domain.Rational       `json:"servings_to,omitempty"`
+	System       string                `json:"system,omitempty"`
+	Disambiguate string                `json:"disambiguate,omitempty"`
+	Lines        []quantityLineRequest `json:"lines"`
+}
+
+type scaledLineResponse struct {
+	Ingredient string            `json:"ingredient"`
+	Original   *quantityResponse `json:"original"`
+	Scaled     *quantityResponse `json:"scaled"`
+	Text       string            `json:"text"`
+}
+
+type scaleResponse struct {
+	Factor float64              `json:"factor"`
+	Lines  []scaledLineResponse `json:"lines"`
+}
+
+func newScaledQuantityResponse(q *usecase.ScaledQuantity) *quantityResponse {
+	if q == nil {
+		return nil
+	}
+	return newQuantityResponse(q.Quantity, q.Text)
+}
+
+func scaleRecipeHandler(scaleService *usecase.ScaleService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		var req scaleRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+
+		// An exact ratio: 3 to 10 servings is 10/3, not 3.3333333333333335
+		factor := req.Factor
+		if factor.IsZero() && req.ServingsFrom.Sign() > 0 {
+			factor = req.ServingsTo.Quo(req.ServingsFrom)
+		}
+		system, err := domain.ParseUomSystem(req.System)
+		if err != nil {
+			writeConversionError(w, fmt.Errorf("validation failed: %w", err))
+			return
+		}
+		disambiguate, err := domain.ParseUomDisambiguation(req.Disambiguate)
+		if err != nil {
+			writeConversionError(w, fmt.Errorf("validation failed: %w", err))
+			return
+		}
+
+		lines := make([]usecase.QuantityLine, len(req.Lines))
+		for i, line := range req.Lines {
+			lines[i] = line.toQuantityLine()
+		}
+
+		ctx := r.Context()
+		scaled, err := scaleService.Scale(ctx, lines, usecase.ScaleOptions{
+			Factor: factor,
+			System: system,
+			Format: domain.FormatOptions{Locales: requestLocales(r), Disambiguate: disambiguate},
+		})
+		if err != nil {
+			logging.FromContext(r.Context()).Error("failed to scale recipe", "error", err)
+			writeConversionError(w, err)
+			return
+		}
+
+		resp := scaleResponse{Factor: factor.Float64(), Lines: make([]scaledLineResponse, len(scaled))}
+		for i, line := range scaled {
+			resp.Lines[i] = scaledLineResponse{
+				Ingredient: line.Ingredient,
+				Original:   newScaledQuantityResponse(line.Original),
+				Scaled:     newScaledQuantityResponse(line.Scaled),
+				Text:       line.Text,
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/jeffjlins/okra/internal/logging"
+)
+
+// withRecovery turns a panicking handler into a logged 500 instead of a dropped
+// connection. The panic value stays in the log; the caller only gets the request id.
+func withRecovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			// net/http's own way to abort a response; let the server handle it
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			logging.FromContext(r.Context()).Error("handler panicked",
+				"panic", fmt.Sprint(v),
+				"stack", string(debug.Stack()),
+			)
+			// Too late for a status once the handler has started its response
+			if rec.status != 0 {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, errorResponse{
+				Error:     "the server hit an unexpected error",
+				RequestID: logging.RequestIDFromContext(r.Context()),
+			})
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
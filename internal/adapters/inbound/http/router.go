@@ -1,20 +1,126 @@
 package http
 
 import (
+	"log/slog"
 	"net/http"
+	"strings"
 
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
 	"github.com/jeffjlins/okra/internal/usecase"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func NewRouter(uomService *usecase.UomService) *http.ServeMux {
+// RouterOptions configures the middleware around the routes
+type RouterOptions struct {
+	Auth      AuthOptions
+	Logger    *slog.Logger // Optional: base logger for request logs, defaults to slog.Default()
+	CORS      CORSOptions
+	RateLimit RateLimitOptions
+
+	MaxBodyBytes int64 // Optional: largest request body accepted, 0 for no limit
+	StrictJSON   bool  // reject request bodies with fields the API doesn't know
+
+	// Optional: served at POST /graphql to readers; mutations check roles themselves
+	GraphQL http.Handler
+
+	// ValidateOpenAPI checks every request and response against the spec served at
+	// /openapi.json. It buffers responses, so it's meant for tests and staging.
+	ValidateOpenAPI bool
+
+	// Optional: wraps each route's handler, e.g. to record per-route metrics
+	RouteMiddleware func(pattern string, h http.Handler) http.Handler
+}
+
+func NewRouter(
+	uomService *usecase.UomService,
+	conversionService *usecase.ConversionService,
+	scaleService *usecase.ScaleService,
+	shoppingListService *usecase.ShoppingListService,
+	ingredientService *usecase.IngredientService,
+	healthService *usecase.HealthService,
+	opts RouterOptions,
+) http.Handler {
 	mux := http.NewServeMux()
+	a := authorizer{opts.Auth}
+	read := func(h http.HandlerFunc) http.HandlerFunc { return a.require(auth.ROLE_READER, h) }
+	// Routes are mounted once they're all known, as the spec is built from them
+	var routes []operation
+	handlers := make(map[string]http.Handler)
+	route := func(pattern string, h http.Handler) {
+		op, ok := documentedOperation(pattern)
+		if !ok {
+			panic("http: route " + pattern + " is not documented in the OpenAPI operations")
+		}
+		routes = append(routes, op)
+		handlers[pattern] = h
+	}
+	handle := func(pattern string, h http.HandlerFunc) { route(pattern, withRateLimit(opts.RateLimit, pattern, h)) }
+	// Health checks are never rate limited, so a busy instance isn't taken for a dead one
+	probe := func(pattern string, h http.HandlerFunc) { route(pattern, h) }
+
+	probe("GET /health", healthHandler)
+	probe("GET /healthz", livenessHandler)
+	probe("GET /readyz", readinessHandler(healthService))
+	var specJSON []byte
+	probe("GET /openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(specJSON)
+	})
+	handle("POST /uom", a.requireWrite(createUomHandler(uomService)))
+	handle("GET /uom/{id}", read(getUomByIDHandler(uomService)))
+	handle("GET /uom/by-label/{label}", read(getUomByLabelHandler(uomService)))
+	handle("GET /uom", read(getAllUomsHandler(uomService)))
+	handle("DELETE /uom/{id}", a.requireWrite(deleteUomHandler(uomService)))
+	handle("PUT /uom/{id}", a.requireWrite(updateUomHandler(uomService)))
+	handle("POST /uom/parse", read(parseUomHandler(uomService)))
+	handle("POST /uom/convert", read(convertHandler(conversionService)))
+	handle("POST /uom/humanize", read(humanizeHandler(conversionService)))
+	handle("POST /uom/format", read(formatHandler(conversionService)))
+	handle("POST /recipes/scale", read(scaleRecipeHandler(scaleService)))
+	handle("POST /shopping-list", read(shoppingListHandler(shoppingListService)))
+	handle("POST /ingredients/parse", read(parseIngredientsHandler(ingredientService)))
+	if opts.GraphQL != nil {
+		handle("POST /graphql", read(opts.GraphQL.ServeHTTP))
+	}
 
-	mux.HandleFunc("GET /health", healthHandler)
-	mux.HandleFunc("POST /uom", createUomHandler(uomService))
-	mux.HandleFunc("GET /uom/{id}", getUomByIDHandler(uomService))
-	mux.HandleFunc("GET /uom", getAllUomsHandler(uomService))
-	mux.HandleFunc("DELETE /uom/{id}", deleteUomHandler(uomService))
-	mux.HandleFunc("PUT /uom/{id}", updateUomHandler(uomService))
+	spec, err := newOpenAPI(routes)
+	if err != nil {
+		panic("http: " + err.Error())
+	}
+	if specJSON, err = spec.MarshalJSON(); err != nil {
+		panic("http: failed to encode the OpenAPI spec: " + err.Error())
+	}
+	for _, op := range routes {
+		h := handlers[op.pattern]
+		if opts.ValidateOpenAPI {
+			h = withOpenAPIValidation(spec, op.pattern, h)
+		}
+		if opts.RouteMiddleware != nil {
+			h = opts.RouteMiddleware(op.pattern, h)
+		}
+		mux.Handle(op.pattern, withRoute(op.pattern, h))
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	handler := a.authenticate(a.withTenant(withJSONRouteErrors(mux)))
+	handler = withBodyLimits(opts.MaxBodyBytes, opts.StrictJSON, handler)
+	handler = withCORS(opts.CORS, handler)
+	handler = withRequestLogging(logger, withRecovery(handler))
+	// Outermost, so rejected requests are traced too and request logs can carry the trace id
+	return otelhttp.NewHandler(handler, "http.server")
+}
 
-	return mux
+// withRoute names the request's span and tags its metrics after the route, e.g.
+// "GET /uom/{id}", so spans and RED metrics are grouped per route rather than per URL
+func withRoute(pattern string, h http.Handler) http.Handler {
+	_, route, _ := strings.Cut(pattern, " ")
+	tagged := otelhttp.WithRouteTag(route, h)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace.SpanFromContext(r.Context()).SetName(pattern)
+		tagged.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,191 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	graphqladapter "github.com/jeffjlins/okra/internal/adapters/inbound/graphql"
+	"github.com/jeffjlins/okra/internal/adapters/outbound/memory"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/usecase"
+	"github.com/jeffjlins/okra/pkg/okra"
+)
+
+// newTestRouter serves the default catalog pkg/okra embeds from the memory repository,
+// checking every request and response against the spec
+func newTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	uoms, err := okra.DefaultUoms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := memory.NewUomRepository(uoms)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uomService := usecase.NewUomService(repo, domain.LabelIDGenerator)
+	return NewRouter(
+		uomService,
+		usecase.NewConversionService(repo),
+		usecase.NewScaleService(repo),
+		usecase.NewShoppingListService(repo),
+		usecase.NewIngredientService(repo),
+		usecase.NewHealthService(repo, repo, 0),
+		RouterOptions{
+			StrictJSON:      true,
+			ValidateOpenAPI: true,
+			GraphQL:         graphqladapter.NewHandler(uomService, graphqladapter.Options{}),
+		},
+	)
+}
+
+// mugUom is a tenant uom in the form POST /uom takes
+const mugUom = `{
+	"label": "mug",
+	"enabled": true,
+	"measure_type": "volume",
+	"snap_amount": ["1/4"],
+	"pivot_ratio": "350",
+	"match_names_recipe": ["mug", "mugs"],
+	"default_name_type": "full",
+	"full_name_singular": "mug",
+	"full_name_plural": "mugs"
+}`
+
+func TestRouterMatchesSpec(t *testing.T) {
+	router := newTestRouter(t)
+	cupID, err := domain.LabelIDGenerator(&domain.BaseUom{Label: "cup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mugID, err := domain.LabelIDGenerator(&domain.BaseUom{Label: "mug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const tenant = "household-1"
+
+	// In order: the uom routes create, read, change and delete a tenant uom
+	tests := []struct {
+		pattern    string
+		path       string // defaults to the pattern's path
+		tenant     string
+		body       string
+		wantStatus int
+	}{
+		{pattern: "GET /health", wantStatus: http.StatusOK},
+		{pattern: "GET /healthz", wantStatus: http.StatusOK},
+		{pattern: "GET /readyz", wantStatus: http.StatusOK},
+		{pattern: "GET /openapi.json", wantStatus: http.StatusOK},
+
+		{pattern: "POST /uom", tenant: tenant, body: mugUom, wantStatus: http.StatusCreated},
+		{pattern: "POST /uom", tenant: tenant, body: mugUom, wantStatus: http.StatusConflict},
+		{pattern: "POST /uom", tenant: tenant, body: `{"label": "mug"}`, wantStatus: http.StatusBadRequest},
+		{pattern: "GET /uom/{id}", path: "/uom/" + mugID, tenant: tenant, wantStatus: http.StatusOK},
+		{pattern: "GET /uom/{id}", path: "/uom/" + mugID, wantStatus: http.StatusNotFound},
+		{pattern: "GET /uom/by-label/{label}", path: "/uom/by-label/mug", tenant: tenant, wantStatus: http.StatusOK},
+		{pattern: "GET /uom/by-label/{label}", path: "/uom/by-label/cup", wantStatus: http.StatusOK},
+		{pattern: "GET /uom/by-label/{label}", path: "/uom/by-label/nope", wantStatus: http.StatusNotFound},
+		{pattern: "GET /uom", wantStatus: http.StatusOK},
+		{pattern: "GET /uom", path: "/uom?system=metric&limit=2", tenant: tenant, wantStatus: http.StatusOK},
+		{pattern: "GET /uom", path: "/uom?system=cubits", wantStatus: http.StatusBadRequest},
+		{pattern: "PUT /uom/{id}", path: "/uom/" + mugID, tenant: tenant, body: strings.Replace(mugUom, `"350"`, `"300"`, 1), wantStatus: http.StatusOK},
+		{pattern: "PUT /uom/{id}", path: "/uom/" + mugID, tenant: tenant, body: `{"label": "mug"}`, wantStatus: http.StatusBadRequest},
+		{pattern: "PUT /uom/{id}", path: "/uom/" + cupID, tenant: tenant, body: mugUom, wantStatus: http.StatusForbidden},
+		{pattern: "PUT /uom/{id}", path: "/uom/nope", tenant: tenant, body: mugUom, wantStatus: http.StatusNotFound},
+		{pattern: "DELETE /uom/{id}", path: "/uom/" + cupID, tenant: tenant, wantStatus: http.StatusForbidden},
+		{pattern: "DELETE /uom/{id}", path: "/uom/" + mugID, tenant: tenant, wantStatus: http.StatusNoContent},
+		{pattern: "DELETE /uom/{id}", path: "/uom/" + mugID, tenant: tenant, wantStatus: http.StatusNotFound},
+
+		{pattern: "POST /uom/parse", body: `{"text": "2 tbsp butter and 1 cup flour"}`, wantStatus: http.StatusOK},
+		{pattern: "POST /uom/convert", body: `{"amount": "1/3", "from": "cup", "to": "tsp"}`, wantStatus: http.StatusOK},
+		{pattern: "POST /uom/convert", body: `{"amount": 2, "max": 3, "from": "cup", "system": "metric"}`, wantStatus: http.StatusOK},
+		{pattern: "POST /uom/convert", body: `{"amount": 1, "from": "cup", "to": "gram"}`, wantStatus: http.StatusBadRequest},
+		{pattern: "POST /uom/humanize", body: `{"amount": 16, "uom": "tbsp"}`, wantStatus: http.StatusOK},
+		{pattern: "POST /uom/format", body: `{"amount": "1 1/2", "uom": "cup"}`, wantStatus: http.StatusOK},
+		{pattern: "POST /uom/format", body: `{"items": [{"amount": 1, "uom": "cup"}, {"amount": 2, "max": 3, "uom": "tbsp"}]}`, wantStatus: http.StatusOK},
+		{pattern: "POST /recipes/scale", body: `{"factor": 4, "lines": [{"text": "2-3 tbsp butter"}, {"amount": 1, "uom": "cup", "ingredient": "flour"}, {"text": "salt to taste"}]}`, wantStatus: http.StatusOK},
+		{pattern: "POST /recipes/scale", body: `{"servings_from": 4, "servings_to": 6, "lines": [{"text": "2 eggs"}]}`, wantStatus: http.StatusOK},
+		{pattern: "POST /shopping-list", body: `{"recipes": [{"name": "cake", "lines": [{"text": "1 cup sugar"}]}, {"lines": [{"text": "2 tbsp sugar"}, {"text": "salt"}]}]}`, wantStatus: http.StatusOK},
+		{pattern: "POST /ingredients/parse", body: `{"lines": ["2 (14.5 oz) cans diced tomatoes, drained", "salt"]}`, wantStatus: http.StatusOK},
+		{pattern: "POST /ingredients/parse", body: `{}`, wantStatus: http.StatusBadRequest},
+		{pattern: "POST /graphql", body: `{"query": "{ uom(label: \"cup\") { label formatted(amount: \"1 1/2\") } }"}`, wantStatus: http.StatusOK},
+	}
+
+	called := make(map[string]bool)
+	for _, tt := range tests {
+		method, path, _ := strings.Cut(tt.pattern, " ")
+		if tt.path != "" {
+			path = tt.path
+		}
+		var body io.Reader
+		if tt.body != "" {
+			body = strings.NewReader(tt.body)
+		}
+		req := httptest.NewRequest(method, path, body)
+		if tt.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if tt.tenant != "" {
+			req.Header.Set(tenantHeader, tt.tenant)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s %s (tenant %q) = %d, want %d: %s", method, path, tt.tenant, rec.Code, tt.wantStatus, rec.Body)
+		}
+		called[tt.pattern] = true
+	}
+
+	for _, op := range operations {
+		if !called[op.pattern] {
+			t.Errorf("%s is documented but not called by the test", op.pattern)
+		}
+	}
+}
+
+func TestRouterErrorsAreJSON(t *testing.T) {
+	router := newTestRouter(t)
+	panicking := withRecovery(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") }))
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantAllow  string
+	}{
+		{"unknown route", router, http.MethodGet, "/nope", "", http.StatusNotFound, ""},
+		{"unknown method", router, http.MethodGet, "/shopping-list", "", http.StatusMethodNotAllowed, "POST"},
+		{"invalid JSON", router, http.MethodPost, "/uom/convert", `{"amount": `, http.StatusBadRequest, ""},
+		{"missing uom", router, http.MethodGet, "/uom/by-label/nope", "", http.StatusNotFound, ""},
+		{"panic", panicking, http.MethodGet, "/uom", "", http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+			var body errorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error == "" {
+				t.Errorf("body %q isn't an error response: %v", rec.Body, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/jeffjlins/okra/internal/usecase"
+)
+
+type shoppingListRecipeRequest struct {
+	Name  string                `json:"name,omitempty"`
+	Lines []quantityLineRequest `json:"lines"`
+}
+
+type shoppingListRequest struct {
+	Recipes      []shoppingListRecipeRequest `json:"recipes"`
+	System       string                      `json:"system,omitempty"`
+	Densities    map[string]float64          `json:"densities,omitempty"` // grams per millilitre, keyed by ingredient
+	Disambiguate string                      `json:"disambiguate,omitempty"`
+}
+
+type shoppingLineRefResponse struct {
+	Recipe int `json:"recipe"`
+	Line   int `json:"line"`
+}
+
+type shoppingItemResponse struct {
+	Ingredient string                    `json:"ingredient"`
+	Quantity   *quantityResponse         `json:"quantity"`
+	Text       string                    `json:"text"`
+	Sources    []shoppingLineRefResponse `json:"sources"`
+}
+
+type shoppingListResponse struct {
+	Items []shoppingItemResponse `json:"items"`
+}
+
+func shoppingListHandler(shoppingListService *usecase.ShoppingListService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		var req shoppingListRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		system, err := domain.ParseUomSystem(req.System)
+		if err != nil {
+			writeConversionError(w, fmt.Errorf("validation failed: %w", err))
+			return
+		}
+		disambiguate, err := domain.ParseUomDisambiguation(req.Disambiguate)
+		if err != nil {
+			writeConversionError(w, fmt.Errorf("validation failed: %w", err))
+			return
+		}
+
+		recipes := make([][]usecase.QuantityLine, len(req.Recipes))
+		for i, recipe := range req.Recipes {
+			recipes[i] = make([]usecase.QuantityLine, len(recipe.Lines))
+			for j, line := range recipe.Lines {
+				recipes[i][j] = line.toQuantityLine()
+			}
+		}
+
+		ctx := r.Context()
+		items, err := shoppingListService.Aggregate(ctx, recipes, usecase.ShoppingListOptions{
+			System:    system,
+			Densities: req.Densities,
+			Format:    domain.FormatOptions{Locales: requestLocales(r), Disambiguate: disambiguate},
+		})
+		if err != nil {
+			logging.FromContext(r.Context()).Error("failed to build shopping list", "error", err)
+			writeConversionError(w, err)
+			return
+		}
+
+		resp := shoppingListResponse{Items: make([]shoppingItemResponse, len(items))}
+		for i, item := range items {
+			resp.Items[i] = shoppingItemResponse{
+				Ingredient: item.Ingredient,
+				Text:       item.Text,
+				Sources:    make([]shoppingLineRefResponse, len(item.Sources)),
+			}
+			if item.Quantity != nil {
+				resp.Items[i].Quantity = newQuantityResponse(*item.Quantity, item.QuantityText)
+			}
+			for j, src := range item.Sources {
+				resp.Items[i].Sources[j] = shoppingLineRefResponse{Recipe: src.Recipe, Line: src.Line}
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
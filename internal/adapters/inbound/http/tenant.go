@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+)
+
+// tenantHeader names the tenant (household or account) a request acts for
+const tenantHeader = "X-Tenant-ID"
+
+// withTenant scopes each request's context to the tenant in the X-Tenant-ID header, so
+// services read the tenant's merged catalog and write to its own uoms. Requests without
+// the header act on the global catalog. Callers whose credentials are bound to a tenant
+// always act for that tenant and may not name another one; callers whose credentials
+// have no tenant binding may act for any tenant. With auth on, anonymous requests act
+// on the global catalog whatever tenant they name, so they can't read a tenant's uoms.
+func (a authorizer) withTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get(tenantHeader)
+		principal := auth.PrincipalFromContext(r.Context())
+		switch {
+		case principal != nil && principal.Tenant != "":
+			if tenant != "" && tenant != principal.Tenant {
+				writeJSONError(w, http.StatusForbidden, "credentials are limited to tenant "+principal.Tenant)
+				return
+			}
+			tenant = principal.Tenant
+		case principal == nil && a.Authenticator != nil:
+			tenant = ""
+		}
+		if tenant == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := domain.ValidateTenant(tenant); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx := domain.WithTenant(r.Context(), tenant)
+		ctx = logging.With(ctx, "tenant", tenant)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
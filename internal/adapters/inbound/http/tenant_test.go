@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth/authtest"
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+func TestWithTenant(t *testing.T) {
+	editor := &auth.Principal{Subject: "app", Role: auth.ROLE_EDITOR}
+	bound := &auth.Principal{Subject: "household-app", Role: auth.ROLE_EDITOR, Tenant: "household-1"}
+	authOn := AuthOptions{Authenticator: authtest.Authenticator{}, PublicReads: true}
+
+	tests := []struct {
+		name       string
+		opts       AuthOptions
+		principal  *auth.Principal
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"auth off, no header", AuthOptions{}, nil, "", http.StatusOK, ""},
+		{"auth off, header", AuthOptions{}, nil, "household-1", http.StatusOK, "household-1"},
+		{"anonymous, no header", authOn, nil, "", http.StatusOK, ""},
+		{"anonymous names a tenant", authOn, nil, "household-1", http.StatusOK, ""},
+		{"unbound credentials, no header", authOn, editor, "", http.StatusOK, ""},
+		{"unbound credentials name any tenant", authOn, editor, "household-2", http.StatusOK, "household-2"},
+		{"bound credentials, no header", authOn, bound, "", http.StatusOK, "household-1"},
+		{"bound credentials, own tenant", authOn, bound, "household-1", http.StatusOK, "household-1"},
+		{"bound credentials, other tenant", authOn, bound, "household-2", http.StatusForbidden, ""},
+		{"invalid tenant", authOn, editor, "no/slashes", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTenant string
+			h := authorizer{tt.opts}.withTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotTenant = domain.TenantFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/uom", nil)
+			if tt.header != "" {
+				req.Header.Set(tenantHeader, tt.header)
+			}
+			if tt.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), tt.principal))
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus || gotTenant != tt.wantTenant {
+				t.Errorf("status %d, tenant %q; want %d, %q (%s)", rec.Code, gotTenant, tt.wantStatus, tt.wantTenant, rec.Body)
+			}
+		})
+	}
+}
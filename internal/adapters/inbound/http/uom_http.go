@@ -3,33 +3,36 @@ package http
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
 	"github.com/jeffjlins/okra/internal/usecase"
 )
 
 func createUomHandler(uomService *usecase.UomService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 
 		var base domain.BaseUom
-		if err := json.NewDecoder(r.Body).Decode(&base); err != nil {
-			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		if err := decodeJSON(r, &base); err != nil {
+			writeDecodeError(w, err)
 			return
 		}
 
 		ctx := r.Context()
 		uom, err := uomService.CreateUom(ctx, &base)
 		if err != nil {
-			log.Printf("Error creating Uom: %v", err)
+			logging.FromContext(r.Context()).Error("failed to create uom", "error", err)
 
 			statusCode := http.StatusInternalServerError
 			errorMsg := "Failed to create Uom"
@@ -43,8 +46,7 @@ func createUomHandler(uomService *usecase.UomService) http.HandlerFunc {
 				errorMsg = errStr
 			}
 
-			w.WriteHeader(statusCode)
-			json.NewEncoder(w).Encode(map[string]string{"error": errorMsg})
+			writeJSONError(w, statusCode, errorMsg)
 			return
 		}
 
@@ -56,7 +58,7 @@ func createUomHandler(uomService *usecase.UomService) http.HandlerFunc {
 func getUomByIDHandler(uomService *usecase.UomService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			return
 		}
 
@@ -64,22 +66,54 @@ func getUomByIDHandler(uomService *usecase.UomService) http.HandlerFunc {
 
 		id := r.PathValue("id")
 		if id == "" {
-			http.Error(w, "id is required", http.StatusBadRequest)
+			writeJSONError(w, http.StatusBadRequest, "id is required")
 			return
 		}
 
 		ctx := r.Context()
 		uom, err := uomService.GetUomByID(ctx, id)
 		if err != nil {
-			log.Printf("Error getting Uom: %v", err)
+			logging.FromContext(r.Context()).Error("failed to get uom", "error", err)
 
 			statusCode := http.StatusInternalServerError
 			if strings.Contains(err.Error(), "not found") {
 				statusCode = http.StatusNotFound
 			}
 
-			w.WriteHeader(statusCode)
-			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			writeJSONError(w, statusCode, err.Error())
+			return
+		}
+
+		json.NewEncoder(w).Encode(uom)
+	}
+}
+
+func getUomByLabelHandler(uomService *usecase.UomService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		label := r.PathValue("label")
+		if label == "" {
+			writeJSONError(w, http.StatusBadRequest, "label is required")
+			return
+		}
+
+		ctx := r.Context()
+		uom, err := uomService.GetUomByLabel(ctx, label)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("failed to get uom by label", "error", err)
+
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "not found") {
+				statusCode = http.StatusNotFound
+			}
+
+			writeJSONError(w, statusCode, err.Error())
 			return
 		}
 
@@ -90,28 +124,85 @@ func getUomByIDHandler(uomService *usecase.UomService) http.HandlerFunc {
 func getAllUomsHandler(uomService *usecase.UomService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 
+		system, err := domain.ParseUomSystem(r.URL.Query().Get("system"))
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		page, err := parseUomPage(r.URL.Query())
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		ctx := r.Context()
-		uoms, err := uomService.GetAllUoms(ctx)
+		uoms, err := uomService.GetAllUoms(ctx, system)
 		if err != nil {
-			log.Printf("Error getting all Uoms: %v", err)
-			http.Error(w, "Failed to get Uoms", http.StatusInternalServerError)
+			logging.FromContext(r.Context()).Error("failed to get all uoms", "error", err)
+			writeJSONError(w, http.StatusInternalServerError, "Failed to get Uoms")
 			return
 		}
 
+		if page.limit > 0 || page.after != "" {
+			var next string
+			uoms, next = page.apply(uoms)
+			if next != "" {
+				q := r.URL.Query()
+				q.Set("after", next)
+				w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, q.Encode()))
+			}
+		}
 		json.NewEncoder(w).Encode(uoms)
 	}
 }
 
+// maxUomPageSize bounds the limit of a uom list request
+const maxUomPageSize = 1000
+
+// uomPage selects a page of a uom list ordered by id: the uoms after the after id,
+// at most limit of them. Without either the whole list is returned, in store order.
+type uomPage struct {
+	after string
+	limit int
+}
+
+func parseUomPage(query url.Values) (uomPage, error) {
+	page := uomPage{after: query.Get("after")}
+	if s := query.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > maxUomPageSize {
+			return uomPage{}, fmt.Errorf("limit must be between 1 and %d", maxUomPageSize)
+		}
+		page.limit = limit
+	}
+	return page, nil
+}
+
+// apply returns the page and the id to continue after, empty on the last page
+func (p uomPage) apply(uoms []*domain.Uom) ([]*domain.Uom, string) {
+	sorted := slices.SortedFunc(slices.Values(uoms), func(a, b *domain.Uom) int { return strings.Compare(a.Id, b.Id) })
+	start, _ := slices.BinarySearchFunc(sorted, p.after, func(u *domain.Uom, id string) int { return strings.Compare(u.Id, id) })
+	if start < len(sorted) && p.after != "" && sorted[start].Id == p.after {
+		start++
+	}
+	sorted = sorted[start:]
+	if p.limit == 0 || len(sorted) <= p.limit {
+		return sorted, ""
+	}
+	return sorted[:p.limit], sorted[p.limit-1].Id
+}
+
 func deleteUomHandler(uomService *usecase.UomService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodDelete {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			return
 		}
 
@@ -119,21 +210,22 @@ func deleteUomHandler(uomService *usecase.UomService) http.HandlerFunc {
 
 		id := r.PathValue("id")
 		if id == "" {
-			http.Error(w, "id is required", http.StatusBadRequest)
+			writeJSONError(w, http.StatusBadRequest, "id is required")
 			return
 		}
 
 		ctx := r.Context()
 		if err := uomService.DeleteUom(ctx, id); err != nil {
-			log.Printf("Error deleting Uom: %v", err)
+			logging.FromContext(r.Context()).Error("failed to delete uom", "error", err)
 
 			statusCode := http.StatusInternalServerError
 			if strings.Contains(err.Error(), "not found") {
 				statusCode = http.StatusNotFound
+			} else if strings.Contains(err.Error(), "read-only") {
+				statusCode = http.StatusForbidden
 			}
 
-			w.WriteHeader(statusCode)
-			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			writeJSONError(w, statusCode, err.Error())
 			return
 		}
 
@@ -144,7 +236,7 @@ func deleteUomHandler(uomService *usecase.UomService) http.HandlerFunc {
 func updateUomHandler(uomService *usecase.UomService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPut {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			return
 		}
 
@@ -152,20 +244,20 @@ func updateUomHandler(uomService *usecase.UomService) http.HandlerFunc {
 
 		id := r.PathValue("id")
 		if id == "" {
-			http.Error(w, "id is required", http.StatusBadRequest)
+			writeJSONError(w, http.StatusBadRequest, "id is required")
 			return
 		}
 
 		var base domain.BaseUom
-		if err := json.NewDecoder(r.Body).Decode(&base); err != nil {
-			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		if err := decodeJSON(r, &base); err != nil {
+			writeDecodeError(w, err)
 			return
 		}
 
 		ctx := r.Context()
 		uom, err := uomService.UpdateUom(ctx, id, &base)
 		if err != nil {
-			log.Printf("Error updating Uom: %v", err)
+			logging.FromContext(r.Context()).Error("failed to update uom", "error", err)
 
 			statusCode := http.StatusInternalServerError
 			errorMsg := "Failed to update Uom"
@@ -174,16 +266,59 @@ func updateUomHandler(uomService *usecase.UomService) http.HandlerFunc {
 			if strings.Contains(errStr, "not found") {
 				statusCode = http.StatusNotFound
 				errorMsg = errStr
+			} else if strings.Contains(errStr, "read-only") {
+				statusCode = http.StatusForbidden
+				errorMsg = errStr
 			} else if strings.Contains(errStr, "validation failed") {
 				statusCode = http.StatusBadRequest
 				errorMsg = errStr
+			} else if strings.Contains(errStr, "already exists") {
+				statusCode = http.StatusConflict
+				errorMsg = errStr
 			}
 
-			w.WriteHeader(statusCode)
-			json.NewEncoder(w).Encode(map[string]string{"error": errorMsg})
+			writeJSONError(w, statusCode, errorMsg)
 			return
 		}
 
 		json.NewEncoder(w).Encode(uom)
 	}
 }
+
+type parseUomRequest struct {
+	Text string `json:"text"`
+}
+
+type parseUomResponse struct {
+	Matches []domain.UomMatch `json:"matches"`
+}
+
+func parseUomHandler(uomService *usecase.UomService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		var req parseUomRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+
+		ctx := r.Context()
+		matches, err := uomService.MatchUoms(ctx, req.Text, requestLocales(r))
+		if err != nil {
+			logging.FromContext(r.Context()).Error("failed to parse uoms", "error", err)
+			writeJSONError(w, http.StatusInternalServerError, "Failed to parse Uoms")
+			return
+		}
+		if matches == nil {
+			matches = []domain.UomMatch{}
+		}
+
+		json.NewEncoder(w).Encode(parseUomResponse{Matches: matches})
+	}
+}
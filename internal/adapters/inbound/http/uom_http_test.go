@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+func TestParseUomPage(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    uomPage
+		wantErr bool
+	}{
+		{"", uomPage{}, false},
+		{"limit=10", uomPage{limit: 10}, false},
+		{"after=b&limit=1000", uomPage{after: "b", limit: 1000}, false},
+		{"after=b", uomPage{after: "b"}, false},
+		{"limit=0", uomPage{}, true},
+		{"limit=1001", uomPage{}, true},
+		{"limit=ten", uomPage{}, true},
+	}
+	for _, tt := range tests {
+		query, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := parseUomPage(query)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseUomPage(%q) = %+v, %v; want %+v, error %v", tt.query, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestUomPageApply(t *testing.T) {
+	var uoms []*domain.Uom
+	for _, id := range []string{"d", "a", "e", "c", "b"} { // store order
+		uoms = append(uoms, &domain.Uom{Id: id})
+	}
+	tests := []struct {
+		page     uomPage
+		wantIDs  []string
+		wantNext string
+	}{
+		{uomPage{limit: 2}, []string{"a", "b"}, "b"},
+		{uomPage{after: "b", limit: 2}, []string{"c", "d"}, "d"},
+		{uomPage{after: "d", limit: 2}, []string{"e"}, ""},
+		{uomPage{after: "c", limit: 2}, []string{"d", "e"}, ""},
+		{uomPage{after: "e", limit: 2}, nil, ""},
+		{uomPage{after: "bb", limit: 2}, []string{"c", "d"}, "d"}, // a deleted id continues after where it was
+		{uomPage{after: "b"}, []string{"c", "d", "e"}, ""},
+		{uomPage{limit: 5}, []string{"a", "b", "c", "d", "e"}, ""},
+	}
+	for _, tt := range tests {
+		got, next := tt.page.apply(uoms)
+		var ids []string
+		for _, uom := range got {
+			ids = append(ids, uom.Id)
+		}
+		if !slices.Equal(ids, tt.wantIDs) || next != tt.wantNext {
+			t.Errorf("%+v.apply = %v, next %q; want %v, next %q", tt.page, ids, next, tt.wantIDs, tt.wantNext)
+		}
+	}
+	if uoms[0].Id != "d" {
+		t.Error("apply reordered the uoms it was given")
+	}
+}
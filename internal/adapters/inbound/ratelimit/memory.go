@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle buckets are dropped
+const sweepInterval = time.Minute
+
+// MemoryLimiter keeps the buckets in process, so each instance enforces its own limits
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// bucket stores when it will be full rather than a token count, which makes refilling
+// a matter of comparing against the clock
+type bucket struct {
+	fullAt time.Time
+	limit  Limit
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryLimiter) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{fullAt: now, limit: limit}
+		m.buckets[key] = b
+	}
+	if b.fullAt.Before(now) {
+		b.fullAt = now
+	}
+
+	every := limit.refillEvery()
+	capacity := time.Duration(limit.burst()) * every
+	res := Result{Limit: limit.burst()}
+
+	// The bucket is short of tokens for the time it still needs to be full
+	missing := b.fullAt.Sub(now)
+	if missing+every > capacity {
+		res.Remaining = int((capacity - missing) / every)
+		res.RetryAfter = missing + every - capacity
+		res.Reset = missing
+		return res, nil
+	}
+	b.fullAt = b.fullAt.Add(every)
+	res.Allowed = true
+	res.Remaining = int((capacity - missing - every) / every)
+	res.Reset = missing + every
+	return res, nil
+}
+
+// sweep drops the buckets that have refilled, which behave like new ones anyway
+func (m *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if !b.fullAt.After(now) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package ratelimit meters callers with token buckets. Each bucket holds up to Burst
+// tokens and refills at Requests per Period; a request takes one token or is refused.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Limit is a token bucket policy. The zero Limit means unlimited.
+type Limit struct {
+	Requests int           // tokens added per period
+	Period   time.Duration // defaults to a minute
+	Burst    int           // bucket size, defaults to Requests
+}
+
+// Unlimited reports whether the limit lets every request through
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0
+}
+
+// Validate checks a limit set in config
+func (l Limit) Validate() error {
+	if l.Requests < 0 || l.Burst < 0 || l.Period < 0 {
+		return errors.New("requests, burst and period can't be negative")
+	}
+	if l.Requests == 0 && l.Burst > 0 {
+		return fmt.Errorf("burst %d needs requests", l.Burst)
+	}
+	return nil
+}
+
+// Window is the period, defaulting to a minute
+func (l Limit) Window() time.Duration {
+	if l.Period <= 0 {
+		return time.Minute
+	}
+	return l.Period
+}
+
+func (l Limit) burst() int {
+	if l.Burst <= 0 {
+		return l.Requests
+	}
+	return l.Burst
+}
+
+// refillEvery is how long one token takes to come back
+func (l Limit) refillEvery() time.Duration {
+	return l.Window() / time.Duration(l.Requests)
+}
+
+// Result is the state of a bucket after a request tried to take a token
+type Result struct {
+	Allowed    bool
+	Limit      int           // bucket size
+	Remaining  int           // tokens left
+	RetryAfter time.Duration // when Allowed is false, how long until a token is back
+	Reset      time.Duration // how long until the bucket is full again
+}
+
+// Limiter keeps the buckets. Implementations must be safe for concurrent use; a shared
+// store, e.g. Redis, lets several instances enforce one limit together.
+type Limiter interface {
+	// Take takes a token from the bucket of key, creating it full if it doesn't exist
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
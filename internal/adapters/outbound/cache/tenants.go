@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DEFAULT_MAX_TENANTS is how many tenant catalogs are cached when WithMaxTenants isn't given
+const DEFAULT_MAX_TENANTS = 1000
+
+// tenantPartitions holds the partitions of the most recently used tenants, so an
+// instance serving many tenants keeps the catalogs of the busy ones rather than one
+// for every tenant it has ever seen
+type tenantPartitions struct {
+	mu       sync.Mutex
+	max      int
+	order    *list.List // of *partition, most recently used first
+	byTenant map[string]*list.Element
+}
+
+func newTenantPartitions(max int) *tenantPartitions {
+	return &tenantPartitions{
+		max:      max,
+		order:    list.New(),
+		byTenant: make(map[string]*list.Element),
+	}
+}
+
+// get returns the tenant's partition, creating it and evicting the least recently used
+// one past the limit
+func (t *tenantPartitions) get(tenant string) *partition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.byTenant[tenant]; ok {
+		t.order.MoveToFront(e)
+		return e.Value.(*partition)
+	}
+	p := &partition{tenant: tenant}
+	t.byTenant[tenant] = t.order.PushFront(p)
+	for t.order.Len() > t.max {
+		oldest := t.order.Back()
+		t.order.Remove(oldest)
+		delete(t.byTenant, oldest.Value.(*partition).tenant)
+	}
+	return p
+}
+
+// remove drops the partition, unless the tenant has been given a new one since. A load
+// still running on a removed partition publishes into it, where nobody reads it.
+func (t *tenantPartitions) remove(p *partition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.byTenant[p.tenant]; ok && e.Value == p {
+		t.order.Remove(e)
+		delete(t.byTenant, p.tenant)
+	}
+}
+
+// clear drops every partition and returns how many there were
+func (t *tenantPartitions) clear() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := t.order.Len()
+	t.order.Init()
+	clear(t.byTenant)
+	return n
+}
+
+// each calls fn with every partition, most recently used first
+func (t *tenantPartitions) each(fn func(p *partition)) {
+	t.mu.Lock()
+	partitions := make([]*partition, 0, t.order.Len())
+	for e := t.order.Front(); e != nil; e = e.Next() {
+		partitions = append(partitions, e.Value.(*partition))
+	}
+	t.mu.Unlock()
+	for _, p := range partitions {
+		fn(p)
+	}
+}
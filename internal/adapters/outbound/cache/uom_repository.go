@@ -0,0 +1,360 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+)
+
+// UomWatcher streams the full uom collection whenever it changes
+type UomWatcher interface {
+	Watch(ctx context.Context, onSnapshot func(uoms []*domain.Uom)) error
+}
+
+// UomRepository is a read-through cache in front of another domain.UomRepository.
+// Reads are served from an in-memory domain.UomCatalog per partition (the global
+// catalog and each tenant's uoms) which is dropped on local writes to that partition,
+// expires after the TTL and, for the global catalog, is replaced whenever a watcher
+// reports changes. Only the catalogs of the most recently used tenants are kept, see
+// WithMaxTenants.
+type UomRepository struct {
+	next    domain.UomRepository
+	ttl     time.Duration
+	metrics bool
+
+	global  partition
+	tenants *tenantPartitions
+	version atomic.Uint64
+
+	watchErr      atomic.Pointer[error] // why Watch isn't receiving snapshots, nil while it is
+	watchRestarts atomic.Uint64
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+	watchUpdates  atomic.Uint64
+}
+
+// partition caches the catalog of the global uoms or of one tenant's uoms
+type partition struct {
+	tenant   string     // empty for the global catalog
+	mu       sync.Mutex // serializes loads so concurrent misses hit the backend once
+	snapshot atomic.Pointer[entry]
+	// generation changes on every invalidation so a load that raced a write isn't published
+	generation atomic.Uint64
+}
+
+type entry struct {
+	catalog  *domain.UomCatalog
+	loadedAt time.Time
+}
+
+// Stats is a point-in-time view of the cache counters. Counters stay at zero when metrics are disabled.
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Invalidations uint64
+	WatchUpdates  uint64
+	Version       uint64 // of the global catalog
+	Size          int    // of the global catalog
+	Tenants       int    // tenants with a cached catalog
+}
+
+// Watch restarts a failed watch after a backoff between these
+const (
+	minWatchBackoff = time.Second
+	maxWatchBackoff = time.Minute
+)
+
+type Option func(*UomRepository)
+
+// WithTTL sets how long a snapshot is served before it is reloaded. Zero means it never expires.
+func WithTTL(ttl time.Duration) Option {
+	return func(r *UomRepository) {
+		r.ttl = ttl
+	}
+}
+
+// WithMaxTenants sets how many tenant catalogs are cached. The least recently used one
+// is dropped to make room for another. It defaults to DEFAULT_MAX_TENANTS.
+func WithMaxTenants(max int) Option {
+	return func(r *UomRepository) {
+		if max > 0 {
+			r.tenants = newTenantPartitions(max)
+		}
+	}
+}
+
+// WithMetrics enables the hit/miss counters reported by Stats
+func WithMetrics(enabled bool) Option {
+	return func(r *UomRepository) {
+		r.metrics = enabled
+	}
+}
+
+func NewUomRepository(next domain.UomRepository, opts ...Option) *UomRepository {
+	r := &UomRepository{
+		next:    next,
+		tenants: newTenantPartitions(DEFAULT_MAX_TENANTS),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Catalog returns the cached snapshot of the partition in ctx, loading it from the
+// backend if missing or expired
+func (r *UomRepository) Catalog(ctx context.Context) (*domain.UomCatalog, error) {
+	p := r.partition(ctx)
+	if e := r.fresh(p); e != nil {
+		r.count(&r.hits)
+		return e.catalog, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Another caller may have loaded it while we waited
+	if e := r.fresh(p); e != nil {
+		r.count(&r.hits)
+		return e.catalog, nil
+	}
+	r.count(&r.misses)
+
+	generation := p.generation.Load()
+	uoms, err := r.next.GetAll(ctx)
+	if err != nil {
+		if p.snapshot.Load() == nil {
+			r.drop(p)
+		}
+		return nil, err
+	}
+	if p.generation.Load() != generation {
+		// A write landed while loading; serve this result but don't keep it
+		return domain.NewUomCatalog(uoms, r.version.Load()), nil
+	}
+	return r.store(p, uoms), nil
+}
+
+func (r *UomRepository) Create(ctx context.Context, uom *domain.Uom) error {
+	defer r.invalidate(r.partition(ctx))
+	return r.next.Create(ctx, uom)
+}
+
+func (r *UomRepository) Update(ctx context.Context, uom *domain.Uom) error {
+	defer r.invalidate(r.partition(ctx))
+	return r.next.Update(ctx, uom)
+}
+
+func (r *UomRepository) Delete(ctx context.Context, id string) error {
+	defer r.invalidate(r.partition(ctx))
+	return r.next.Delete(ctx, id)
+}
+
+func (r *UomRepository) GetByID(ctx context.Context, id string) (*domain.Uom, error) {
+	catalog, err := r.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.ByID(id), nil
+}
+
+func (r *UomRepository) GetByLabel(ctx context.Context, label string) (*domain.Uom, error) {
+	catalog, err := r.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.ByLabel(label), nil
+}
+
+func (r *UomRepository) GetAll(ctx context.Context) ([]*domain.Uom, error) {
+	catalog, err := r.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.All(), nil
+}
+
+// Invalidate drops every snapshot so the next read of each partition reloads it
+func (r *UomRepository) Invalidate() {
+	r.invalidate(&r.global)
+	for range r.tenants.clear() {
+		r.count(&r.invalidations)
+	}
+}
+
+// Watch replaces the global snapshot every time the watcher reports a change. Tenant
+// snapshots rely on the TTL to pick up writes made by other instances. It blocks until
+// ctx is done.
+//
+// When the watch fails, the global snapshot is dropped so reads go back to the backend,
+// and the watch is started again after a backoff that doubles from minWatchBackoff up
+// to maxWatchBackoff. The backoff starts over once a snapshot arrives.
+func (r *UomRepository) Watch(ctx context.Context, watcher UomWatcher) {
+	ctx = domain.WithTenant(ctx, "")
+	logger := logging.FromContext(ctx)
+	r.setWatchErr(errors.New("watch not started yet"))
+
+	backoff := minWatchBackoff
+	for {
+		err := watcher.Watch(ctx, func(uoms []*domain.Uom) {
+			r.global.mu.Lock()
+			defer r.global.mu.Unlock()
+			r.store(&r.global, uoms)
+			r.count(&r.watchUpdates)
+			r.setWatchErr(nil)
+			backoff = minWatchBackoff
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = errors.New("watch ended")
+		}
+		r.setWatchErr(err)
+		r.watchRestarts.Add(1)
+		r.invalidate(&r.global)
+		logger.Warn("uom cache watch failed, restarting", "error", err, "retry_in", backoff)
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+		backoff = min(backoff*2, maxWatchBackoff)
+	}
+}
+
+// WatchHealth reports whether Watch is receiving snapshots, for readiness probes
+func (r *UomRepository) WatchHealth(_ context.Context) (map[string]any, error) {
+	details := map[string]any{"restarts": r.watchRestarts.Load()}
+	if err := r.watchErr.Load(); err != nil {
+		return details, *err
+	}
+	return details, nil
+}
+
+func (r *UomRepository) setWatchErr(err error) {
+	if err == nil {
+		r.watchErr.Store(nil)
+		return
+	}
+	r.watchErr.Store(&err)
+}
+
+// LogStats logs the cache counters every interval until ctx is done
+func (r *UomRepository) LogStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s := r.Stats()
+			logging.FromContext(ctx).Info("uom cache stats",
+				"version", s.Version,
+				"size", s.Size,
+				"tenants", s.Tenants,
+				"hits", s.Hits,
+				"misses", s.Misses,
+				"hit_ratio", s.HitRatio(),
+				"invalidations", s.Invalidations,
+				"watch_updates", s.WatchUpdates,
+			)
+		}
+	}
+}
+
+func (r *UomRepository) Stats() Stats {
+	s := Stats{
+		Hits:          r.hits.Load(),
+		Misses:        r.misses.Load(),
+		Invalidations: r.invalidations.Load(),
+		WatchUpdates:  r.watchUpdates.Load(),
+	}
+	if e := r.global.snapshot.Load(); e != nil {
+		s.Version = e.catalog.Version()
+		s.Size = e.catalog.Len()
+	}
+	r.tenants.each(func(p *partition) {
+		if p.snapshot.Load() != nil {
+			s.Tenants++
+		}
+	})
+	return s
+}
+
+// HitRatio is the share of reads served from a snapshot, or 0 before any read
+func (s Stats) HitRatio() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+func (s Stats) String() string {
+	ratio := s.HitRatio()
+	return fmt.Sprintf("version=%d size=%d tenants=%d hits=%d misses=%d hit_ratio=%.3f invalidations=%d watch_updates=%d",
+		s.Version, s.Size, s.Tenants, s.Hits, s.Misses, ratio, s.Invalidations, s.WatchUpdates)
+}
+
+// partition returns the partition of the tenant in ctx, or the global one when there is no tenant
+func (r *UomRepository) partition(ctx context.Context) *partition {
+	tenant := domain.TenantFromContext(ctx)
+	if tenant == "" {
+		return &r.global
+	}
+	return r.tenants.get(tenant)
+}
+
+// invalidate drops the partition's snapshot so the next read reloads it. A tenant
+// partition is dropped altogether rather than kept empty.
+func (r *UomRepository) invalidate(p *partition) {
+	p.generation.Add(1)
+	p.snapshot.Store(nil)
+	r.drop(p)
+	r.count(&r.invalidations)
+}
+
+// drop forgets a tenant partition without a snapshot, so tenants whose reads fail or
+// that only write don't hold a slot
+func (r *UomRepository) drop(p *partition) {
+	if p != &r.global {
+		r.tenants.remove(p)
+	}
+}
+
+// fresh returns the partition's snapshot if there is one and it hasn't expired
+func (r *UomRepository) fresh(p *partition) *entry {
+	e := p.snapshot.Load()
+	if e == nil {
+		return nil
+	}
+	if r.ttl > 0 && time.Since(e.loadedAt) > r.ttl {
+		return nil
+	}
+	return e
+}
+
+// store builds and publishes a new catalog version in the partition. Callers must hold p.mu.
+func (r *UomRepository) store(p *partition, uoms []*domain.Uom) *domain.UomCatalog {
+	catalog := domain.NewUomCatalog(uoms, r.version.Add(1))
+	p.snapshot.Store(&entry{catalog: catalog, loadedAt: time.Now()})
+	return catalog
+}
+
+func (r *UomRepository) count(counter *atomic.Uint64) {
+	if r.metrics {
+		counter.Add(1)
+	}
+}
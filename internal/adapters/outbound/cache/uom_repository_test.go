@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+// countingRepo counts the catalog loads per tenant and fails them for tenants in fail
+type countingRepo struct {
+	domain.UomRepository
+	mu    sync.Mutex
+	loads map[string]int
+	fail  map[string]bool
+}
+
+func newCountingRepo() *countingRepo {
+	return &countingRepo{loads: make(map[string]int), fail: make(map[string]bool)}
+}
+
+func (r *countingRepo) GetAll(ctx context.Context) ([]*domain.Uom, error) {
+	tenant := domain.TenantFromContext(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loads[tenant]++
+	if r.fail[tenant] {
+		return nil, errors.New("backend unavailable")
+	}
+	return nil, nil
+}
+
+func (r *countingRepo) Create(context.Context, *domain.Uom) error {
+	return nil
+}
+
+func (r *countingRepo) load(tenant string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.loads[tenant]
+}
+
+func tenantCtx(tenant string) context.Context {
+	return domain.WithTenant(context.Background(), tenant)
+}
+
+func TestTenantPartitionsAreBounded(t *testing.T) {
+	backend := newCountingRepo()
+	repo := NewUomRepository(backend, WithMaxTenants(3))
+
+	for i := range 5 {
+		if _, err := repo.Catalog(tenantCtx(fmt.Sprintf("t%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := repo.Stats().Tenants; got != 3 {
+		t.Errorf("cache holds %d tenants, want 3", got)
+	}
+
+	// t2..t4 are still cached, t0 was evicted
+	for _, tenant := range []string{"t2", "t3", "t4"} {
+		repo.Catalog(tenantCtx(tenant))
+		if n := backend.load(tenant); n != 1 {
+			t.Errorf("tenant %s loaded %d times, want 1", tenant, n)
+		}
+	}
+	repo.Catalog(tenantCtx("t0"))
+	if n := backend.load("t0"); n != 2 {
+		t.Errorf("evicted tenant t0 loaded %d times, want 2", n)
+	}
+}
+
+func TestTenantPartitionsUseRecency(t *testing.T) {
+	backend := newCountingRepo()
+	repo := NewUomRepository(backend, WithMaxTenants(2))
+
+	repo.Catalog(tenantCtx("a"))
+	repo.Catalog(tenantCtx("b"))
+	repo.Catalog(tenantCtx("a")) // a is now the most recently used
+	repo.Catalog(tenantCtx("c")) // evicts b
+	repo.Catalog(tenantCtx("a"))
+
+	if n := backend.load("a"); n != 1 {
+		t.Errorf("recently used tenant a loaded %d times, want 1", n)
+	}
+	repo.Catalog(tenantCtx("b"))
+	if n := backend.load("b"); n != 2 {
+		t.Errorf("least recently used tenant b loaded %d times, want 2", n)
+	}
+}
+
+func TestEmptyTenantPartitionsAreDropped(t *testing.T) {
+	backend := newCountingRepo()
+	repo := NewUomRepository(backend)
+
+	// A write drops the tenant's partition instead of keeping it empty
+	repo.Catalog(tenantCtx("writer"))
+	if err := repo.Create(tenantCtx("writer"), &domain.Uom{}); err != nil {
+		t.Fatal(err)
+	}
+	// So does a failed load
+	backend.fail["broken"] = true
+	if _, err := repo.Catalog(tenantCtx("broken")); err == nil {
+		t.Fatal("Catalog succeeded, want the backend error")
+	}
+
+	held := 0
+	repo.tenants.each(func(*partition) { held++ })
+	if held != 0 {
+		t.Errorf("cache holds %d tenant partitions, want 0", held)
+	}
+
+	// The global catalog is never dropped
+	repo.Catalog(context.Background())
+	repo.Invalidate()
+	repo.Catalog(context.Background())
+	if n := backend.load(""); n != 2 {
+		t.Errorf("global catalog loaded %d times, want 2", n)
+	}
+}
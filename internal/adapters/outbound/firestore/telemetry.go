@@ -0,0 +1,74 @@
+package firestore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jeffjlins/okra/internal/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/jeffjlins/okra/internal/adapters/outbound/firestore"
+
+var (
+	tracer = otel.Tracer(instrumentationName)
+
+	// Rate, errors and duration of repository calls: the histogram's count is the rate
+	// and its outcome attribute separates the errors
+	callDuration, _ = otel.Meter(instrumentationName).Float64Histogram("okra.repository.call.duration",
+		metric.WithDescription("Duration of uom repository calls against Firestore"),
+		metric.WithUnit("s"),
+	)
+)
+
+// repoCall is one instrumented repository call: a span, a duration measurement and a log line
+type repoCall struct {
+	ctx   context.Context
+	op    string
+	start time.Time
+	span  trace.Span
+	args  []any // slog key/value pairs describing the call
+}
+
+// startCall starts a span for the repository call. args are string key/value pairs
+// added to the span and the log line. The returned context carries the span.
+func startCall(ctx context.Context, op string, args ...any) (context.Context, *repoCall) {
+	attrs := []attribute.KeyValue{attribute.String("db.system.name", "firestore"), attribute.String("okra.repository.op", op)}
+	for i := 0; i+1 < len(args); i += 2 {
+		key, _ := args[i].(string)
+		value, _ := args[i+1].(string)
+		attrs = append(attrs, attribute.String("okra."+key, value))
+	}
+	ctx, span := tracer.Start(ctx, op+" uoms", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, &repoCall{ctx: ctx, op: op, start: time.Now(), span: span, args: args}
+}
+
+// end records the outcome of the call. It logs at debug level, or at error level if the
+// call failed, with the request's logger so Firestore errors can be tied to their request.
+func (c *repoCall) end(err *error) {
+	elapsed := time.Since(c.start)
+	outcome := "ok"
+	level := slog.LevelDebug
+	args := c.args
+	if *err != nil {
+		outcome = "error"
+		level = slog.LevelError
+		args = append(args, "error", *err)
+		c.span.RecordError(*err)
+		c.span.SetStatus(codes.Error, (*err).Error())
+	}
+	c.span.End()
+
+	callDuration.Record(c.ctx, elapsed.Seconds(), metric.WithAttributes(
+		attribute.String("okra.repository.op", c.op),
+		attribute.String("okra.outcome", outcome),
+	))
+
+	args = append(args, "op", c.op, "duration_ms", float64(elapsed.Microseconds())/1000)
+	logging.FromContext(c.ctx).Log(c.ctx, level, "firestore uom repository call", args...)
+}
@@ -0,0 +1,109 @@
+package firestore
+
+import (
+	"fmt"
+	"math"
+
+	"cloud.google.com/go/firestore"
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+// uomDocument is how a uom is stored. Firestore can't store the domain's exact
+// rationals, so the rational fields are shadowed by fraction strings ("1/3", "473/2").
+// Documents written before fractions were stored hold floats, which are still read.
+type uomDocument struct {
+	domain.Uom
+
+	GroupMin      any
+	GroupMax      any
+	SnapAmount    []any
+	SnapSelect    any
+	PivotRatio    any
+	PackageAmount any
+}
+
+func newUomDocument(uom *domain.Uom) uomDocument {
+	doc := uomDocument{
+		Uom:           *uom,
+		GroupMin:      encodeRational(uom.GroupMin),
+		GroupMax:      encodeRational(uom.GroupMax),
+		SnapSelect:    encodeRational(uom.SnapSelect),
+		PivotRatio:    encodeRational(uom.PivotRatio),
+		PackageAmount: encodeRational(uom.PackageAmount),
+		SnapAmount:    make([]any, len(uom.SnapAmount)),
+	}
+	for i, s := range uom.SnapAmount {
+		doc.SnapAmount[i] = s.String()
+	}
+	return doc
+}
+
+// decodeUom reads a uom document, converting the stored fractions back to rationals
+func decodeUom(snap *firestore.DocumentSnapshot) (*domain.Uom, error) {
+	var doc uomDocument
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, err
+	}
+
+	uom := doc.Uom
+	fields := []struct {
+		name  string
+		value any
+		dst   **domain.Rational
+	}{
+		{"GroupMin", doc.GroupMin, &uom.GroupMin},
+		{"GroupMax", doc.GroupMax, &uom.GroupMax},
+		{"SnapSelect", doc.SnapSelect, &uom.SnapSelect},
+		{"PivotRatio", doc.PivotRatio, &uom.PivotRatio},
+		{"PackageAmount", doc.PackageAmount, &uom.PackageAmount},
+	}
+	for _, f := range fields {
+		r, err := decodeRational(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.dst = r
+	}
+
+	uom.SnapAmount = make([]domain.Rational, 0, len(doc.SnapAmount))
+	for _, v := range doc.SnapAmount {
+		r, err := decodeRational(v)
+		if err != nil {
+			return nil, fmt.Errorf("SnapAmount: %w", err)
+		}
+		if r != nil {
+			uom.SnapAmount = append(uom.SnapAmount, *r)
+		}
+	}
+	return &uom, nil
+}
+
+func encodeRational(r *domain.Rational) any {
+	if r == nil {
+		return nil
+	}
+	return r.String()
+}
+
+func decodeRational(v any) (*domain.Rational, error) {
+	var r domain.Rational
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		parsed, err := domain.ParseRational(v)
+		if err != nil {
+			return nil, err
+		}
+		r = parsed
+	case int64:
+		r = domain.IntRational(v)
+	case float64:
+		// Written as float32 before fractions were stored; round off the float32 noise
+		// the same way its JSON form did
+		r = domain.FloatRational(math.Round(v*1e6) / 1e6)
+	default:
+		return nil, fmt.Errorf("unexpected stored value %v (%T)", v, v)
+	}
+	return &r, nil
+}
@@ -4,12 +4,34 @@ import (
 	"context"
 	"fmt"
 
+	"cloud.google.com/go/firestore"
 	"github.com/jeffjlins/okra/internal/domain"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-const uomCollection = "uoms"
+const (
+	uomCollection = "uoms"
+
+	// Each tenant keeps its uoms and unique indexes under tenants/{tenant}/, using the same collection names
+	tenantCollection = "tenants"
+
+	// Unique indexes: one document per normalized key, holding the id of the owning uom
+	uomLabelCollection              = "uom_labels"
+	uomMatchNameRecipeCollection    = "uom_match_names_recipe"
+	uomMatchNameFoodLabelCollection = "uom_match_names_food_label"
+)
+
+// uomIndexEntry is the document stored in a unique index collection
+type uomIndexEntry struct {
+	UomID string `firestore:"uom_id"`
+}
+
+// uniqueKey is a claim a uom holds in one of the unique indexes
+type uniqueKey struct {
+	ref  *firestore.DocumentRef
+	desc string // used in conflict errors, e.g. `label "tbsp"`
+}
 
 type UomRepository struct {
 	client *Client
@@ -21,19 +43,123 @@ func NewUomRepository(client *Client) *UomRepository {
 	}
 }
 
-func (r *UomRepository) Save(ctx context.Context, uom *domain.Uom) error {
+// Create writes a new uom and claims its unique keys in a single transaction.
+// Both the uom document and the index documents are written with create-if-absent semantics.
+func (r *UomRepository) Create(ctx context.Context, uom *domain.Uom) (err error) {
+	ctx, call := startCall(ctx, "create", "uom_id", uom.Id)
+	defer call.end(&err)
 	if err := uom.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
-	_, err := r.client.Collection(uomCollection).Doc(uom.Id).Set(ctx, uom)
+
+	uomRef := r.collection(ctx, uomCollection).Doc(uom.Id)
+	keys := r.uniqueKeys(ctx, uom)
+
+	err = r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := checkUniqueKeys(tx, keys, uom.Id); err != nil {
+			return err
+		}
+
+		if err := tx.Create(uomRef, newUomDocument(uom)); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := tx.Create(key.ref, uomIndexEntry{UomID: uom.Id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return r.createConflict(ctx, uomRef, keys, uom.Id)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to save uom %s: %w", uom.Id, err)
+		return fmt.Errorf("failed to create uom %s: %w", uom.Id, err)
 	}
 	return nil
 }
 
-func (r *UomRepository) GetByID(ctx context.Context, id string) (*domain.Uom, error) {
-	doc, err := r.client.Collection(uomCollection).Doc(id).Get(ctx)
+// createConflict names what a failed create collided with. The commit only says that
+// one of the create-if-absent writes found its document taken, which happens when a
+// concurrent create claimed it after our reads, so the documents are read again.
+func (r *UomRepository) createConflict(ctx context.Context, uomRef *firestore.DocumentRef, keys []uniqueKey, id string) error {
+	unknown := fmt.Errorf("uom %s already exists or takes a label or match name that does", id)
+	_, err := uomRef.Get(ctx)
+	if err == nil {
+		return fmt.Errorf("uom with id %s already exists", id)
+	}
+	if status.Code(err) != codes.NotFound {
+		return unknown
+	}
+	for _, key := range keys {
+		doc, err := key.ref.Get(ctx)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		var entry uomIndexEntry
+		if err != nil || doc.DataTo(&entry) != nil {
+			return unknown
+		}
+		if entry.UomID != id {
+			return fmt.Errorf("uom with %s already exists (%s)", key.desc, entry.UomID)
+		}
+	}
+	return unknown
+}
+
+// Update replaces an existing uom, moving its unique key claims in the same transaction
+func (r *UomRepository) Update(ctx context.Context, uom *domain.Uom) (err error) {
+	ctx, call := startCall(ctx, "update", "uom_id", uom.Id)
+	defer call.end(&err)
+	if err := uom.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	uomRef := r.collection(ctx, uomCollection).Doc(uom.Id)
+	keys := r.uniqueKeys(ctx, uom)
+
+	err = r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		previous, err := getUom(tx, uomRef)
+		if err != nil {
+			return err
+		}
+		if previous == nil {
+			return fmt.Errorf("uom with id %s %w", uom.Id, domain.ErrNotFound)
+		}
+		if err := checkUniqueKeys(tx, keys, uom.Id); err != nil {
+			return err
+		}
+
+		// Release claims the uom no longer needs
+		claimed := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			claimed[key.ref.Path] = true
+		}
+		for _, old := range r.uniqueKeys(ctx, previous) {
+			if !claimed[old.ref.Path] {
+				if err := tx.Delete(old.ref); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, key := range keys {
+			if err := tx.Set(key.ref, uomIndexEntry{UomID: uom.Id}); err != nil {
+				return err
+			}
+		}
+		return tx.Set(uomRef, newUomDocument(uom))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update uom %s: %w", uom.Id, err)
+	}
+	return nil
+}
+
+func (r *UomRepository) GetByID(ctx context.Context, id string) (_ *domain.Uom, err error) {
+	ctx, call := startCall(ctx, "get_by_id", "uom_id", id)
+	defer call.end(&err)
+	doc, err := r.collection(ctx, uomCollection).Doc(id).Get(ctx)
 	if err != nil {
 		// Check if document doesn't exist (NotFound error)
 		if status.Code(err) == codes.NotFound {
@@ -42,36 +168,216 @@ func (r *UomRepository) GetByID(ctx context.Context, id string) (*domain.Uom, er
 		return nil, fmt.Errorf("failed to get uom %s: %w", id, err)
 	}
 
-	var uom domain.Uom
-	if err := doc.DataTo(&uom); err != nil {
+	uom, err := decodeUom(doc)
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal uom %s: %w", id, err)
 	}
 
-	return &uom, nil
+	return uom, nil
+}
+
+// GetByLabel resolves the label through the label index, so "Fl. Oz" and "fl-oz" find the same uom
+func (r *UomRepository) GetByLabel(ctx context.Context, label string) (_ *domain.Uom, err error) {
+	ctx, call := startCall(ctx, "get_by_label", "label", label)
+	defer call.end(&err)
+	labelRef := r.labelRef(ctx, label)
+	if labelRef == nil {
+		return nil, nil
+	}
+
+	doc, err := labelRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to get uom by label %q: %w", label, err)
+	}
+
+	var entry uomIndexEntry
+	if err := doc.DataTo(&entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal label index %q: %w", label, err)
+	}
+
+	return r.GetByID(ctx, entry.UomID)
 }
 
-func (r *UomRepository) GetAll(ctx context.Context) ([]*domain.Uom, error) {
-	docs, err := r.client.Collection(uomCollection).Documents(ctx).GetAll()
+func (r *UomRepository) GetAll(ctx context.Context) (_ []*domain.Uom, err error) {
+	ctx, call := startCall(ctx, "get_all")
+	defer call.end(&err)
+	docs, err := r.collection(ctx, uomCollection).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all uoms: %w", err)
 	}
 
 	uoms := make([]*domain.Uom, 0, len(docs))
 	for _, doc := range docs {
-		var uom domain.Uom
-		if err := doc.DataTo(&uom); err != nil {
+		uom, err := decodeUom(doc)
+		if err != nil {
 			return nil, fmt.Errorf("failed to unmarshal uom %s: %w", doc.Ref.ID, err)
 		}
-		uoms = append(uoms, &uom)
+		uoms = append(uoms, uom)
 	}
 
 	return uoms, nil
 }
 
-func (r *UomRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.client.Collection(uomCollection).Doc(id).Delete(ctx)
+// Delete removes the uom together with its unique index entries
+func (r *UomRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, call := startCall(ctx, "delete", "uom_id", id)
+	defer call.end(&err)
+	uomRef := r.collection(ctx, uomCollection).Doc(id)
+
+	err = r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		existing, err := getUom(tx, uomRef)
+		if err != nil {
+			return err
+		}
+		var owned []uniqueKey
+		if existing != nil {
+			for _, key := range r.uniqueKeys(ctx, existing) {
+				owner, err := getIndexOwner(tx, key.ref)
+				if err != nil {
+					return err
+				}
+				if owner == id {
+					owned = append(owned, key)
+				}
+			}
+		}
+
+		for _, key := range owned {
+			if err := tx.Delete(key.ref); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(uomRef)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete uom %s: %w", id, err)
 	}
 	return nil
 }
+
+// collection returns the named collection of the tenant in ctx, or the global one when there is no tenant
+func (r *UomRepository) collection(ctx context.Context, name string) *firestore.CollectionRef {
+	if tenant := domain.TenantFromContext(ctx); tenant != "" {
+		return r.client.Collection(tenantCollection).Doc(tenant).Collection(name)
+	}
+	return r.client.Collection(name)
+}
+
+// labelRef returns the label index document for a label, or nil if the label slugs to nothing
+func (r *UomRepository) labelRef(ctx context.Context, label string) *firestore.DocumentRef {
+	slug := domain.Slug(label)
+	if slug == "" {
+		return nil
+	}
+	return r.collection(ctx, uomLabelCollection).Doc(slug)
+}
+
+// uniqueKeys returns the index documents a uom must own: its label and each of its match names.
+// Keys are deduplicated since different spellings can normalize to the same slug. Indexes are
+// per tenant, so a tenant uom may reuse a global label to override that uom.
+func (r *UomRepository) uniqueKeys(ctx context.Context, uom *domain.Uom) []uniqueKey {
+	var keys []uniqueKey
+	seen := make(map[string]bool)
+	add := func(collection, kind, value string) {
+		slug := domain.Slug(value)
+		if slug == "" {
+			return
+		}
+		ref := r.collection(ctx, collection).Doc(slug)
+		if seen[ref.Path] {
+			return
+		}
+		seen[ref.Path] = true
+		keys = append(keys, uniqueKey{ref: ref, desc: fmt.Sprintf("%s %q", kind, value)})
+	}
+
+	add(uomLabelCollection, "label", uom.Label)
+	for _, name := range uom.MatchNamesRecipe {
+		add(uomMatchNameRecipeCollection, "recipe match name", name)
+	}
+	for _, name := range uom.MatchNamesFoodLabel {
+		add(uomMatchNameFoodLabelCollection, "food label match name", name)
+	}
+	return keys
+}
+
+// checkUniqueKeys fails if any of the keys is owned by a uom other than id
+func checkUniqueKeys(tx *firestore.Transaction, keys []uniqueKey, id string) error {
+	for _, key := range keys {
+		owner, err := getIndexOwner(tx, key.ref)
+		if err != nil {
+			return err
+		}
+		if owner != "" && owner != id {
+			return fmt.Errorf("uom with %s already exists (%s)", key.desc, owner)
+		}
+	}
+	return nil
+}
+
+// getUom reads a uom inside a transaction, returning nil if it doesn't exist
+func getUom(tx *firestore.Transaction, ref *firestore.DocumentRef) (*domain.Uom, error) {
+	doc, err := tx.Get(ref)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	uom, err := decodeUom(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal uom %s: %w", ref.ID, err)
+	}
+	return uom, nil
+}
+
+// getIndexOwner reads a unique index entry inside a transaction, returning "" if it is unclaimed
+func getIndexOwner(tx *firestore.Transaction, ref *firestore.DocumentRef) (string, error) {
+	doc, err := tx.Get(ref)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	var entry uomIndexEntry
+	if err := doc.DataTo(&entry); err != nil {
+		return "", fmt.Errorf("failed to unmarshal index entry %s: %w", ref.ID, err)
+	}
+	return entry.UomID, nil
+}
+
+// Watch listens to the uom collection of the tenant in ctx (the global one without a tenant)
+// and calls onSnapshot with the full set of uoms every time it changes, starting with the
+// current state. It blocks until ctx is done.
+func (r *UomRepository) Watch(ctx context.Context, onSnapshot func(uoms []*domain.Uom)) error {
+	it := r.collection(ctx, uomCollection).Snapshots(ctx)
+	defer it.Stop()
+
+	for {
+		snap, err := it.Next()
+		if err != nil {
+			if ctx.Err() != nil || status.Code(err) == codes.Canceled {
+				return nil
+			}
+			return fmt.Errorf("failed to watch uoms: %w", err)
+		}
+
+		docs, err := snap.Documents.GetAll()
+		if err != nil {
+			return fmt.Errorf("failed to read uom snapshot: %w", err)
+		}
+		uoms := make([]*domain.Uom, 0, len(docs))
+		for _, doc := range docs {
+			uom, err := decodeUom(doc)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal uom %s: %w", doc.Ref.ID, err)
+			}
+			uoms = append(uoms, uom)
+		}
+		onSnapshot(uoms)
+	}
+}
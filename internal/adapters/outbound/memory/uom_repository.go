@@ -0,0 +1,160 @@
+// Package memory keeps the uom catalog in memory, for running the use cases without a
+// database
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+// UomRepository is a domain.UomRepository over catalogs held in memory. Like the
+// Firestore repository, it keeps the global uoms apart from each tenant's own, and ids,
+// labels and match names are unique within each. Nothing is persisted.
+type UomRepository struct {
+	mu         sync.RWMutex
+	partitions map[string]*domain.UomCatalog // tenant -> its own uoms, "" for the global ones
+	version    uint64
+	empty      *domain.UomCatalog
+}
+
+// NewUomRepository validates the uoms and fails with an "already exists" error if two
+// of them share an id, a label or a match name, as a database-backed repository would.
+// They become the global catalog.
+func NewUomRepository(uoms []*domain.Uom) (*UomRepository, error) {
+	for _, uom := range uoms {
+		if err := uom.Validate(); err != nil {
+			return nil, fmt.Errorf("validation failed: uom %s: %w", uom.Label, err)
+		}
+	}
+	if err := checkUnique(uoms); err != nil {
+		return nil, err
+	}
+
+	return &UomRepository{
+		partitions: map[string]*domain.UomCatalog{"": domain.NewUomCatalog(uoms, 1)},
+		version:    1,
+		empty:      domain.NewUomCatalog(nil, 0),
+	}, nil
+}
+
+// checkUnique fails with an "already exists" error if two uoms share an id, a label or
+// a match name. Labels and names are compared by their slug, as the Firestore indexes do.
+func checkUnique(uoms []*domain.Uom) error {
+	owners := make(map[string]string) // kind and slug -> id of the uom that claimed it
+	claim := func(uom *domain.Uom, kind, value string) error {
+		key := kind + " " + domain.Slug(value)
+		if owner, taken := owners[key]; taken && owner != uom.Id {
+			return fmt.Errorf("uom with %s %q already exists (%s)", kind, value, owner)
+		}
+		owners[key] = uom.Id
+		return nil
+	}
+
+	ids := make(map[string]bool, len(uoms))
+	for _, uom := range uoms {
+		if ids[uom.Id] {
+			return fmt.Errorf("uom with id %s already exists", uom.Id)
+		}
+		ids[uom.Id] = true
+		if err := claim(uom, "label", uom.Label); err != nil {
+			return err
+		}
+		for _, name := range uom.MatchNamesRecipe {
+			if err := claim(uom, "recipe match name", name); err != nil {
+				return err
+			}
+		}
+		for _, name := range uom.MatchNamesFoodLabel {
+			if err := claim(uom, "food label match name", name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *UomRepository) Create(ctx context.Context, uom *domain.Uom) error {
+	if err := uom.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	return r.write(ctx, func(uoms []*domain.Uom) ([]*domain.Uom, error) {
+		return append(uoms, uom), nil
+	})
+}
+
+func (r *UomRepository) Update(ctx context.Context, uom *domain.Uom) error {
+	if err := uom.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	return r.write(ctx, func(uoms []*domain.Uom) ([]*domain.Uom, error) {
+		i := slices.IndexFunc(uoms, func(u *domain.Uom) bool { return u.Id == uom.Id })
+		if i < 0 {
+			return nil, fmt.Errorf("uom with id %s %w", uom.Id, domain.ErrNotFound)
+		}
+		uoms[i] = uom
+		return uoms, nil
+	})
+}
+
+// Delete succeeds whether or not the uom exists, like the Firestore repository
+func (r *UomRepository) Delete(ctx context.Context, id string) error {
+	return r.write(ctx, func(uoms []*domain.Uom) ([]*domain.Uom, error) {
+		return slices.DeleteFunc(uoms, func(u *domain.Uom) bool { return u.Id == id }), nil
+	})
+}
+
+// GetByID returns nil without an error when the uom doesn't exist, like the other repositories
+func (r *UomRepository) GetByID(ctx context.Context, id string) (*domain.Uom, error) {
+	return r.partition(ctx).ByID(id), nil
+}
+
+func (r *UomRepository) GetByLabel(ctx context.Context, label string) (*domain.Uom, error) {
+	return r.partition(ctx).ByLabel(label), nil
+}
+
+func (r *UomRepository) GetAll(ctx context.Context) ([]*domain.Uom, error) {
+	return r.partition(ctx).All(), nil
+}
+
+// Catalog implements domain.UomCatalogSource, so the use cases skip rebuilding the indexes
+func (r *UomRepository) Catalog(ctx context.Context) (*domain.UomCatalog, error) {
+	return r.partition(ctx), nil
+}
+
+// partition returns the catalog of the tenant in ctx, or the global one when there is no tenant
+func (r *UomRepository) partition(ctx context.Context) *domain.UomCatalog {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if catalog, ok := r.partitions[domain.TenantFromContext(ctx)]; ok {
+		return catalog
+	}
+	return r.empty
+}
+
+// write applies change to a copy of the uoms of the tenant in ctx and publishes the
+// result as a new catalog if they are still unique. Catalogs already handed out are
+// left as they were.
+func (r *UomRepository) write(ctx context.Context, change func(uoms []*domain.Uom) ([]*domain.Uom, error)) error {
+	tenant := domain.TenantFromContext(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.empty
+	if catalog, ok := r.partitions[tenant]; ok {
+		current = catalog
+	}
+	uoms, err := change(slices.Clone(current.All()))
+	if err != nil {
+		return err
+	}
+	if err := checkUnique(uoms); err != nil {
+		return err
+	}
+	r.version++
+	r.partitions[tenant] = domain.NewUomCatalog(uoms, r.version)
+	return nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+func newUom(id, label string, names ...string) *domain.Uom {
+	return &domain.Uom{Id: id, BaseUom: domain.BaseUom{
+		Label:                  label,
+		Enabled:                true,
+		MeasureType:            domain.VOL,
+		SnapAmount:             []domain.Rational{domain.NewRational(1, 4)},
+		PrintedNameDefaultType: domain.SHORT,
+		MatchNamesRecipe:       names,
+	}}
+}
+
+func TestUomRepositoryWrites(t *testing.T) {
+	global := context.Background()
+	tenant := domain.WithTenant(global, "household-1")
+	repo, err := NewUomRepository([]*domain.Uom{newUom("cup", "cup", "cups")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := repo.Catalog(global)
+
+	// A tenant's uoms are kept apart from the global ones, so it may reuse a global label
+	if err := repo.Create(tenant, newUom("my-cup", "cup", "mug")); err != nil {
+		t.Fatalf("Create in tenant: %v", err)
+	}
+	if err := repo.Create(tenant, newUom("mug", "big mug", "Mug")); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Create with a taken match name = %v, want an already exists error", err)
+	}
+	if err := repo.Create(global, newUom("cup", "teacup")); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Create with a taken id = %v, want an already exists error", err)
+	}
+	if uom, _ := repo.GetByLabel(tenant, "cup"); uom == nil || uom.Id != "my-cup" {
+		t.Errorf("tenant GetByLabel(cup) = %v, want its own cup", uom)
+	}
+	if uoms, _ := repo.GetAll(domain.WithTenant(global, "household-2")); len(uoms) != 0 {
+		t.Errorf("another tenant has %d uoms, want none", len(uoms))
+	}
+
+	if err := repo.Update(global, newUom("cup", "US cup", "cups")); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := repo.Update(global, newUom("pint", "pint")); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Update of a missing uom = %v, want a not found error", err)
+	}
+	if uom, _ := repo.GetByID(global, "cup"); uom == nil || uom.Label != "US cup" {
+		t.Errorf("GetByID(cup) = %v after the update", uom)
+	}
+	// Catalogs handed out before a write don't change
+	if before.ByID("cup").Label != "cup" {
+		t.Errorf("an earlier catalog sees the update")
+	}
+
+	if err := repo.Delete(tenant, "my-cup"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Delete(tenant, "my-cup"); err != nil {
+		t.Errorf("Delete of a missing uom = %v, want nil", err)
+	}
+	if uom, _ := repo.GetByID(tenant, "my-cup"); uom != nil {
+		t.Errorf("deleted uom is still there")
+	}
+}
+
+func TestNewUomRepositoryRejectsDuplicates(t *testing.T) {
+	_, err := NewUomRepository([]*domain.Uom{newUom("cup", "cup", "c"), newUom("cubic", "cubic", "C")})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("NewUomRepository = %v, want an already exists error", err)
+	}
+}
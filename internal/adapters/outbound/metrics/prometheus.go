@@ -0,0 +1,164 @@
+// Package metrics keeps okra's Prometheus metrics: HTTP requests per route, the domain
+// events reported through usecase.Metrics, and the state of the uom cache and catalog,
+// which are read when scraped.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jeffjlins/okra/internal/adapters/outbound/cache"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// catalogTimeout bounds loading the catalog during a scrape
+const catalogTimeout = 5 * time.Second
+
+// Prometheus implements usecase.Metrics on its own registry
+type Prometheus struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	parses       *prometheus.CounterVec
+	conversions  *prometheus.CounterVec
+	selections   *prometheus.CounterVec
+}
+
+func NewPrometheus() *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "okra_http_requests_total",
+			Help: "HTTP requests by route and status code.",
+		}, []string{"route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "okra_http_request_duration_seconds",
+			Help:    "HTTP request duration by route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route"}),
+		parses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "okra_uom_parse_total",
+			Help: "Uom lookups in free text by the match name found; misses have an empty match_name.",
+		}, []string{"match_name", "result"}),
+		conversions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "okra_uom_conversions_total",
+			Help: "Conversions by source and target measure type.",
+		}, []string{"from", "to"}),
+		selections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "okra_humanize_selections_total",
+			Help: "Uoms chosen when humanizing amounts.",
+		}, []string{"measure_type", "uom"}),
+	}
+	p.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.httpRequests, p.httpDuration, p.parses, p.conversions, p.selections,
+	)
+	return p
+}
+
+// Handler serves the registry in the Prometheus text format
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{Registry: p.registry})
+}
+
+// InstrumentRoute counts and times the requests served by h under the route's pattern
+func (p *Prometheus) InstrumentRoute(pattern string, h http.Handler) http.Handler {
+	labels := prometheus.Labels{"route": pattern}
+	return promhttp.InstrumentHandlerCounter(p.httpRequests.MustCurryWith(labels),
+		promhttp.InstrumentHandlerDuration(p.httpDuration.MustCurryWith(labels), h))
+}
+
+func (p *Prometheus) UomParsed(matchName string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	p.parses.WithLabelValues(strings.ToLower(matchName), result).Inc()
+}
+
+func (p *Prometheus) Converted(from, to domain.UomMeasureType) {
+	p.conversions.WithLabelValues(from, to).Inc()
+}
+
+func (p *Prometheus) HumanizeSelected(uom *domain.Uom) {
+	if uom == nil {
+		return
+	}
+	p.selections.WithLabelValues(uom.MeasureType, uom.Label).Inc()
+}
+
+// RegisterCache exports the uom cache counters and hit ratio
+func (p *Prometheus) RegisterCache(stats func() cache.Stats) {
+	p.registry.MustRegister(&cacheCollector{stats: stats})
+}
+
+// RegisterCatalog exports the number of uoms in the global catalog per measure type,
+// loading the catalog on each scrape
+func (p *Prometheus) RegisterCatalog(load func(ctx context.Context) (*domain.UomCatalog, error)) {
+	p.registry.MustRegister(&catalogCollector{load: load})
+}
+
+var (
+	cacheHitsDesc          = prometheus.NewDesc("okra_uom_cache_hits_total", "Uom reads served from the cache.", nil, nil)
+	cacheMissesDesc        = prometheus.NewDesc("okra_uom_cache_misses_total", "Uom reads that loaded the catalog from the backend.", nil, nil)
+	cacheHitRatioDesc      = prometheus.NewDesc("okra_uom_cache_hit_ratio", "Share of uom reads served from the cache since startup.", nil, nil)
+	cacheInvalidationsDesc = prometheus.NewDesc("okra_uom_cache_invalidations_total", "Cache snapshots dropped by writes.", nil, nil)
+	cacheTenantsDesc       = prometheus.NewDesc("okra_uom_cache_tenants", "Tenants with a cached catalog.", nil, nil)
+	catalogUomsDesc        = prometheus.NewDesc("okra_uom_catalog_uoms", "Uoms in the global catalog by measure type.", []string{"measure_type"}, nil)
+)
+
+type cacheCollector struct {
+	stats func() cache.Stats
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+	ch <- cacheHitRatioDesc
+	ch <- cacheInvalidationsDesc
+	ch <- cacheTenantsDesc
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats()
+	ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(s.Hits))
+	ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(s.Misses))
+	ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue, s.HitRatio())
+	ch <- prometheus.MustNewConstMetric(cacheInvalidationsDesc, prometheus.CounterValue, float64(s.Invalidations))
+	ch <- prometheus.MustNewConstMetric(cacheTenantsDesc, prometheus.GaugeValue, float64(s.Tenants))
+}
+
+type catalogCollector struct {
+	load func(ctx context.Context) (*domain.UomCatalog, error)
+}
+
+func (c *catalogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- catalogUomsDesc
+}
+
+func (c *catalogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogTimeout)
+	defer cancel()
+	catalog, err := c.load(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to load uom catalog for metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(catalogUomsDesc, err)
+		return
+	}
+
+	counts := make(map[domain.UomMeasureType]int)
+	for _, uom := range catalog.All() {
+		counts[uom.MeasureType]++
+	}
+	for measureType, n := range counts {
+		ch <- prometheus.MustNewConstMetric(catalogUomsDesc, prometheus.GaugeValue, float64(n), measureType)
+	}
+}
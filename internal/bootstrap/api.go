@@ -2,59 +2,284 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/jeffjlins/okra/internal/adapters/inbound/auth"
+	graphqladapter "github.com/jeffjlins/okra/internal/adapters/inbound/graphql"
+	grpcadapter "github.com/jeffjlins/okra/internal/adapters/inbound/grpc"
 	httpadapter "github.com/jeffjlins/okra/internal/adapters/inbound/http"
+	"github.com/jeffjlins/okra/internal/adapters/inbound/ratelimit"
+	"github.com/jeffjlins/okra/internal/adapters/outbound/cache"
 	"github.com/jeffjlins/okra/internal/adapters/outbound/firestore"
+	"github.com/jeffjlins/okra/internal/adapters/outbound/metrics"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"github.com/jeffjlins/okra/internal/telemetry"
 	"github.com/jeffjlins/okra/internal/usecase"
 )
 
 type App struct {
-	Server     *http.Server
-	Firestore  *firestore.Client
+	Server      *http.Server
+	AdminServer *http.Server        // serves /metrics, nil when server.admin_port is empty
+	GRPCServer  *grpcadapter.Server // nil when server.grpc_port is empty
+	GRPCAddr    string
+	Firestore   *firestore.Client
+	Logger      *slog.Logger
+
+	stopBackground    context.CancelFunc              // stops watchers and other background loops
+	shutdownTelemetry func(ctx context.Context) error // flushes and stops the telemetry exporters
 }
 
 func NewApp(cfg *Config) (*App, error) {
-	ctx := context.Background()
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log config: %w", err)
+	}
+	// Also routes the standard log package, e.g. net/http's own errors, through slog
+	slog.SetDefault(logger)
+	ctx := logging.WithLogger(context.Background(), logger)
+
+	newUomID, err := domain.NewUomIDGenerator(cfg.Uom.IDStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uom config: %w", err)
+	}
+	authOpts, err := newAuthOptions(logger, cfg.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth config: %w", err)
+	}
+	rateLimitOpts, err := newRateLimitOptions(cfg.RateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate limit config: %w", err)
+	}
+
+	shutdownTelemetry, err := telemetry.Setup(ctx, telemetry.Options{
+		ServiceName:    cfg.Telemetry.ServiceName,
+		Exporter:       cfg.Telemetry.Exporter,
+		Protocol:       cfg.Telemetry.Protocol,
+		Endpoint:       cfg.Telemetry.Endpoint,
+		Insecure:       cfg.Telemetry.Insecure,
+		File:           cfg.Telemetry.File,
+		SampleRatio:    cfg.Telemetry.SampleRatio,
+		MetricInterval: cfg.Telemetry.MetricInterval,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid telemetry config: %w", err)
+	}
 
 	// Initialize Firestore client
 	var fsClient *firestore.Client
-	var err error
 	if cfg.Firestore.CredentialsFile != "" {
 		fsClient, err = firestore.NewClientWithCredentials(ctx, cfg.Firestore.ProjectID, cfg.Firestore.DatabaseID, cfg.Firestore.CredentialsFile)
 	} else {
 		fsClient, err = firestore.NewClient(ctx, cfg.Firestore.ProjectID, cfg.Firestore.DatabaseID)
 	}
 	if err != nil {
+		shutdownTelemetry(ctx)
 		return nil, fmt.Errorf("failed to initialize firestore client: %w", err)
 	}
 
+	promMetrics := metrics.NewPrometheus()
+
 	// Create repositories
-	uomRepo := firestore.NewUomRepository(fsClient)
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	fsUomRepo := firestore.NewUomRepository(fsClient)
+	var uomRepo domain.UomRepository = fsUomRepo
+	var healthProbes []usecase.HealthProbe
+	if cfg.Cache.Enabled {
+		cachedUomRepo := cache.NewUomRepository(fsUomRepo,
+			cache.WithTTL(cfg.Cache.TTL),
+			cache.WithMaxTenants(cfg.Cache.MaxTenants),
+			cache.WithMetrics(cfg.Cache.Metrics),
+		)
+		if cfg.Cache.Watch {
+			go cachedUomRepo.Watch(logging.WithLogger(backgroundCtx, logger), fsUomRepo)
+			healthProbes = append(healthProbes, usecase.HealthProbe{Name: "cache_watch", Probe: cachedUomRepo.WatchHealth})
+		}
+		if cfg.Cache.Metrics && cfg.Cache.MetricsLogInterval > 0 {
+			go cachedUomRepo.LogStats(backgroundCtx, cfg.Cache.MetricsLogInterval)
+		}
+		if cfg.Cache.Metrics {
+			promMetrics.RegisterCache(cachedUomRepo.Stats)
+		}
+		uomRepo = cachedUomRepo
+	}
+	promMetrics.RegisterCatalog(func(ctx context.Context) (*domain.UomCatalog, error) {
+		return domain.LoadUomCatalog(ctx, uomRepo)
+	})
 
 	// Create use cases/services
-	uomService := usecase.NewUomService(uomRepo)
+	withMetrics := usecase.WithMetrics(promMetrics)
+	uomService := usecase.NewUomService(uomRepo, newUomID, withMetrics)
+	conversionService := usecase.NewConversionService(uomRepo, withMetrics)
+	scaleService := usecase.NewScaleService(uomRepo, withMetrics)
+	shoppingListService := usecase.NewShoppingListService(uomRepo, withMetrics)
+	ingredientService := usecase.NewIngredientService(uomRepo, withMetrics)
+	healthService := usecase.NewHealthService(fsUomRepo, uomRepo, cfg.Server.ReadinessTimeout, healthProbes...)
 
 	// Create router with repositories and services
-	mux := httpadapter.NewRouter(uomService)
+	handler := httpadapter.NewRouter(uomService, conversionService, scaleService, shoppingListService, ingredientService, healthService,
+		httpadapter.RouterOptions{
+			Auth:            authOpts,
+			Logger:          logger,
+			RouteMiddleware: promMetrics.InstrumentRoute,
+			RateLimit:       rateLimitOpts,
+			MaxBodyBytes:    cfg.Server.MaxBodyBytes,
+			StrictJSON:      cfg.Server.StrictJSON,
+			ValidateOpenAPI: cfg.Server.ValidateOpenAPI,
+			GraphQL:         graphqladapter.NewHandler(uomService, graphqladapter.Options{RequireAuth: authOpts.Authenticator != nil}),
+			CORS: httpadapter.CORSOptions{
+				AllowedOrigins:   cfg.Server.CORS.AllowedOrigins,
+				AllowedMethods:   cfg.Server.CORS.AllowedMethods,
+				AllowedHeaders:   cfg.Server.CORS.AllowedHeaders,
+				AllowCredentials: cfg.Server.CORS.AllowCredentials,
+				MaxAge:           cfg.Server.CORS.MaxAge,
+			},
+		})
 
 	server := &http.Server{
 		Addr:              ":" + cfg.Server.Port,
-		Handler:           mux,
+		Handler:           handler,
 		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+
+	// The admin server is kept off the public port so metrics need not go through auth
+	var adminServer *http.Server
+	if cfg.Server.AdminPort != "" {
+		adminMux := http.NewServeMux()
+		adminMux.Handle("GET /metrics", promMetrics.Handler())
+		adminServer = &http.Server{
+			Addr:              ":" + cfg.Server.AdminPort,
+			Handler:           adminMux,
+			ReadHeaderTimeout: 5 * time.Second,
+			ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+		}
+	}
+
+	var grpcServer *grpcadapter.Server
+	if cfg.Server.GRPCPort != "" {
+		grpcServer = grpcadapter.NewServer(uomService, conversionService, grpcadapter.Options{
+			Authenticator: authOpts.Authenticator,
+			PublicReads:   authOpts.PublicReads,
+			Logger:        logger,
+		})
 	}
 
 	return &App{
-		Server:     server,
-		Firestore:  fsClient,
+		Server:            server,
+		AdminServer:       adminServer,
+		GRPCServer:        grpcServer,
+		GRPCAddr:          ":" + cfg.Server.GRPCPort,
+		Firestore:         fsClient,
+		Logger:            logger,
+		stopBackground:    stopBackground,
+		shutdownTelemetry: shutdownTelemetry,
 	}, nil
 }
 
+// newAuthOptions builds the authenticators enabled in the config: api keys, JWTs or both
+func newAuthOptions(logger *slog.Logger, cfg AuthConfig) (httpadapter.AuthOptions, error) {
+	if !cfg.Enabled {
+		logger.Warn("auth is disabled: every route is open to anyone")
+		return httpadapter.AuthOptions{}, nil
+	}
+
+	var authenticators []auth.Authenticator
+	if len(cfg.APIKeys) > 0 {
+		keys := make([]auth.APIKey, len(cfg.APIKeys))
+		for i, k := range cfg.APIKeys {
+			keys[i] = auth.APIKey{Name: k.Name, Key: k.Key, Role: auth.Role(k.Role), Tenant: k.Tenant}
+		}
+		apiKeys, err := auth.NewAPIKeyAuthenticator(keys)
+		if err != nil {
+			return httpadapter.AuthOptions{}, err
+		}
+		authenticators = append(authenticators, apiKeys)
+	}
+	if cfg.JWT.JWKSFile != "" {
+		jwks, err := auth.LoadJWKS(cfg.JWT.JWKSFile)
+		if err != nil {
+			return httpadapter.AuthOptions{}, err
+		}
+		authenticators = append(authenticators, auth.NewJWTAuthenticator(jwks, auth.JWTOptions{
+			Issuer:      cfg.JWT.Issuer,
+			Audience:    cfg.JWT.Audience,
+			RoleClaim:   cfg.JWT.RoleClaim,
+			TenantClaim: cfg.JWT.TenantClaim,
+			Leeway:      cfg.JWT.Leeway,
+		}))
+	}
+
+	return httpadapter.AuthOptions{
+		Authenticator: auth.Chain(authenticators...),
+		PublicReads:   cfg.PublicReads,
+	}, nil
+}
+
+// newRateLimitOptions builds in-memory per-route limits, so each instance enforces them on its own
+func newRateLimitOptions(cfg RateLimitConfig) (httpadapter.RateLimitOptions, error) {
+	if !cfg.Enabled {
+		return httpadapter.RateLimitOptions{}, nil
+	}
+	toLimit := func(p RateLimitPolicy) ratelimit.Limit {
+		return ratelimit.Limit{Requests: p.Requests, Period: p.Period, Burst: p.Burst}
+	}
+
+	opts := httpadapter.RateLimitOptions{
+		Limiter:        ratelimit.NewMemoryLimiter(),
+		Default:        toLimit(cfg.Default),
+		Routes:         make(map[string]ratelimit.Limit, len(cfg.Routes)),
+		TrustedProxies: cfg.TrustedProxies,
+	}
+	if err := opts.Default.Validate(); err != nil {
+		return httpadapter.RateLimitOptions{}, fmt.Errorf("default: %w", err)
+	}
+	for _, r := range cfg.Routes {
+		method, path, ok := strings.Cut(r.Route, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			return httpadapter.RateLimitOptions{}, fmt.Errorf("route %q: expected a method and a path, e.g. \"POST /ingredients/parse\"", r.Route)
+		}
+		limit := toLimit(r.RateLimitPolicy)
+		if err := limit.Validate(); err != nil {
+			return httpadapter.RateLimitOptions{}, fmt.Errorf("route %s: %w", r.Route, err)
+		}
+		opts.Routes[r.Route] = limit
+	}
+	return opts, nil
+}
+
+// Shutdown drains the servers first, then stops the backends and finally flushes
+// telemetry, so spans and metrics of the last requests are exported
 func (a *App) Shutdown(ctx context.Context) error {
+	var errs []error
+	if err := a.Server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down server: %w", err))
+	}
+	if a.GRPCServer != nil {
+		if err := a.GRPCServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down grpc server: %w", err))
+		}
+	}
+	if a.AdminServer != nil {
+		if err := a.AdminServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down admin server: %w", err))
+		}
+	}
+	a.stopBackground()
 	if err := a.Firestore.Close(); err != nil {
-		return fmt.Errorf("failed to close firestore client: %w", err)
+		errs = append(errs, fmt.Errorf("failed to close firestore client: %w", err))
+	}
+	if err := a.shutdownTelemetry(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down telemetry: %w", err))
 	}
-	return a.Server.Shutdown(ctx)
+	return errors.Join(errs...)
 }
@@ -3,6 +3,8 @@ package bootstrap
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -10,10 +12,34 @@ import (
 type Config struct {
 	Server    ServerConfig
 	Firestore FirestoreConfig
+	Uom       UomConfig
+	Cache     CacheConfig
+	Auth      AuthConfig
+	RateLimit RateLimitConfig
+	Log       LogConfig
+	Telemetry TelemetryConfig
 }
 
 type ServerConfig struct {
-	Port string
+	Port             string
+	AdminPort        string        // Optional: port of the admin server serving /metrics. Empty disables it
+	GRPCPort         string        // Optional: port of the gRPC API. Empty disables it
+	ReadinessTimeout time.Duration // Optional: how long each /readyz probe may take, defaults to 2s
+	ReadTimeout      time.Duration // Optional: how long reading a whole request may take. 0 means no limit
+	WriteTimeout     time.Duration // Optional: how long writing a response may take. 0 means no limit
+	IdleTimeout      time.Duration // Optional: how long a keep-alive connection may wait for the next request
+	MaxBodyBytes     int64         // Optional: largest request body accepted, 0 for no limit
+	StrictJSON       bool          // Reject request bodies with unknown fields
+	ValidateOpenAPI  bool          // Check requests and responses against the OpenAPI spec; for tests and staging
+	CORS             CORSConfig
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string      // Origins of browser apps allowed to call the API, "*" for any. Empty disables CORS
+	AllowedMethods   []string      // Optional: defaults to GET, POST, PUT and DELETE
+	AllowedHeaders   []string      // Optional: defaults to the headers the API reads
+	AllowCredentials bool          // Let browsers send cookies and authorization headers. Needs explicit origins, not "*"
+	MaxAge           time.Duration // Optional: how long browsers may cache a preflight answer
 }
 
 type FirestoreConfig struct {
@@ -22,6 +48,77 @@ type FirestoreConfig struct {
 	CredentialsFile string // Optional: path to service account JSON file. If empty, uses GOOGLE_APPLICATION_CREDENTIALS env var or default credentials
 }
 
+type UomConfig struct {
+	IDStrategy string // Optional: "uuidv1" (default), "uuidv7" or "label" for deterministic label-derived ids
+}
+
+type CacheConfig struct {
+	Enabled            bool          // Serve uom reads from an in-memory catalog snapshot
+	TTL                time.Duration // Optional: reload the snapshot after this long. 0 keeps it until invalidated
+	Watch              bool          // Refresh the snapshot from a Firestore snapshot listener
+	MaxTenants         int           // Optional: how many tenant catalogs are cached, least recently used dropped first. Defaults to 1000
+	Metrics            bool          // Count hits, misses and invalidations
+	MetricsLogInterval time.Duration // Optional: log the cache counters at this interval. 0 disables logging
+}
+
+type LogConfig struct {
+	Level  string // Optional: debug, info (default), warn or error
+	Format string // Optional: json (default) or text
+}
+
+type TelemetryConfig struct {
+	ServiceName    string        // Optional: service.name on spans and metrics, defaults to "okra"
+	Exporter       string        // Optional: none (default), otlp, stdout or file
+	Protocol       string        // Optional: otlp protocol, grpc (default) or http
+	Endpoint       string        // Optional: otlp collector address. Defaults to the OTEL_EXPORTER_OTLP_* env vars, then localhost
+	Insecure       bool          // Optional: connect to the collector without TLS
+	File           string        // Optional: path the file exporter appends to
+	SampleRatio    float64       // Optional: share of traces recorded, defaults to 1
+	MetricInterval time.Duration // Optional: how often metrics are exported, defaults to 60s
+}
+
+type RateLimitConfig struct {
+	Enabled        bool
+	TrustedProxies int                    // Optional: proxies in front of the server that append to X-Forwarded-For. 0 ignores the header
+	Default        RateLimitPolicy        // Optional: applies to routes without their own limit. No requests means unlimited
+	Routes         []RouteRateLimitConfig // Optional: per-route limits
+}
+
+// RateLimitPolicy is a token bucket: up to Burst requests at once, refilled at Requests per Period
+type RateLimitPolicy struct {
+	Requests int           `mapstructure:"requests"`
+	Period   time.Duration `mapstructure:"period"` // Optional: defaults to 1m
+	Burst    int           `mapstructure:"burst"`  // Optional: defaults to Requests
+}
+
+type RouteRateLimitConfig struct {
+	Route           string `mapstructure:"route"` // route pattern as registered, e.g. "POST /ingredients/parse"
+	RateLimitPolicy `mapstructure:",squash"`
+}
+
+type AuthConfig struct {
+	Enabled     bool           // Require credentials on the uom routes. When false every route is open to anyone
+	PublicReads bool           // Let anonymous callers use the reader routes (lookups, parsing, conversion, scaling)
+	APIKeys     []APIKeyConfig // Optional: static api keys, sent in the X-API-Key header
+	JWT         JWTConfig
+}
+
+type APIKeyConfig struct {
+	Name   string `mapstructure:"name"`
+	Key    string `mapstructure:"key"`
+	Role   string `mapstructure:"role"`   // reader, editor or admin
+	Tenant string `mapstructure:"tenant"` // Optional: restricts the key to one tenant
+}
+
+type JWTConfig struct {
+	JWKSFile    string        // Optional: accept bearer tokens signed by a key in this JWKS file
+	Issuer      string        // Optional: required "iss" claim
+	Audience    string        // Optional: required "aud" claim
+	RoleClaim   string        // Optional: claim holding the role(s), defaults to "role"
+	TenantClaim string        // Optional: claim binding the token to a tenant, defaults to "tenant"
+	Leeway      time.Duration // Optional: allowed clock skew
+}
+
 func LoadConfig() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -31,17 +128,83 @@ func LoadConfig() (*Config, error) {
 
 	// Set defaults
 	viper.SetDefault("server.port", "8080")
+	viper.SetDefault("server.admin_port", "9090")
+	viper.SetDefault("server.grpc_port", "50051")
+	viper.SetDefault("server.readiness_timeout", "2s")
+	viper.SetDefault("server.read_timeout", "15s")
+	viper.SetDefault("server.write_timeout", "30s")
+	viper.SetDefault("server.idle_timeout", "120s")
+	viper.SetDefault("server.max_body_bytes", 1<<20)
+	viper.SetDefault("server.strict_json", false)
+	viper.SetDefault("server.validate_openapi", false)
+	viper.SetDefault("server.cors.allowed_origins", []string{})
+	viper.SetDefault("server.cors.allow_credentials", false)
+	viper.SetDefault("server.cors.max_age", "10m")
 	viper.SetDefault("firestore.project_id", "")
 	viper.SetDefault("firestore.database_id", "(default)")
 	viper.SetDefault("firestore.credentials_file", "")
+	viper.SetDefault("uom.id_strategy", "uuidv1")
+	viper.SetDefault("cache.enabled", true)
+	viper.SetDefault("cache.ttl", "5m")
+	viper.SetDefault("cache.watch", true)
+	viper.SetDefault("cache.max_tenants", 1000)
+	viper.SetDefault("cache.metrics", true)
+	viper.SetDefault("cache.metrics_log_interval", "0s")
+	viper.SetDefault("rate_limit.enabled", false)
+	viper.SetDefault("rate_limit.trusted_proxies", 0)
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "json")
+	viper.SetDefault("telemetry.service_name", "okra")
+	viper.SetDefault("telemetry.exporter", "none")
+	viper.SetDefault("telemetry.protocol", "grpc")
+	viper.SetDefault("telemetry.endpoint", "")
+	viper.SetDefault("telemetry.insecure", false)
+	viper.SetDefault("telemetry.file", "")
+	viper.SetDefault("telemetry.sample_ratio", 1.0)
+	viper.SetDefault("telemetry.metric_interval", "60s")
+	viper.SetDefault("auth.enabled", false)
+	viper.SetDefault("auth.public_reads", false)
+	viper.SetDefault("auth.jwt.jwks_file", "")
+	viper.SetDefault("auth.jwt.issuer", "")
+	viper.SetDefault("auth.jwt.audience", "")
+	viper.SetDefault("auth.jwt.role_claim", "role")
+	viper.SetDefault("auth.jwt.tenant_claim", "tenant")
+	viper.SetDefault("auth.jwt.leeway", "30s")
 
 	// Environment variables
 	viper.SetEnvPrefix("OKRA")
 	viper.AutomaticEnv()
 	viper.BindEnv("server.port", "OKRA_SERVER_PORT")
+	viper.BindEnv("server.admin_port", "OKRA_SERVER_ADMIN_PORT")
+	viper.BindEnv("server.grpc_port", "OKRA_SERVER_GRPC_PORT")
+	viper.BindEnv("server.max_body_bytes", "OKRA_SERVER_MAX_BODY_BYTES")
+	viper.BindEnv("server.strict_json", "OKRA_SERVER_STRICT_JSON")
+	viper.BindEnv("server.validate_openapi", "OKRA_SERVER_VALIDATE_OPENAPI")
+	viper.BindEnv("server.cors.allowed_origins", "OKRA_SERVER_CORS_ALLOWED_ORIGINS")
 	viper.BindEnv("firestore.project_id", "OKRA_FIRESTORE_PROJECT_ID")
 	viper.BindEnv("firestore.database_id", "OKRA_FIRESTORE_DATABASE_ID")
 	viper.BindEnv("firestore.credentials_file", "OKRA_FIRESTORE_CREDENTIALS_FILE")
+	viper.BindEnv("uom.id_strategy", "OKRA_UOM_ID_STRATEGY")
+	viper.BindEnv("cache.enabled", "OKRA_CACHE_ENABLED")
+	viper.BindEnv("cache.ttl", "OKRA_CACHE_TTL")
+	viper.BindEnv("cache.watch", "OKRA_CACHE_WATCH")
+	viper.BindEnv("cache.metrics", "OKRA_CACHE_METRICS")
+	viper.BindEnv("cache.metrics_log_interval", "OKRA_CACHE_METRICS_LOG_INTERVAL")
+	viper.BindEnv("rate_limit.enabled", "OKRA_RATE_LIMIT_ENABLED")
+	viper.BindEnv("rate_limit.trusted_proxies", "OKRA_RATE_LIMIT_TRUSTED_PROXIES")
+	viper.BindEnv("rate_limit.trust_proxy", "OKRA_RATE_LIMIT_TRUST_PROXY")
+	viper.BindEnv("log.level", "OKRA_LOG_LEVEL")
+	viper.BindEnv("log.format", "OKRA_LOG_FORMAT")
+	viper.BindEnv("telemetry.exporter", "OKRA_TELEMETRY_EXPORTER")
+	viper.BindEnv("telemetry.protocol", "OKRA_TELEMETRY_PROTOCOL")
+	viper.BindEnv("telemetry.endpoint", "OKRA_TELEMETRY_ENDPOINT")
+	viper.BindEnv("telemetry.insecure", "OKRA_TELEMETRY_INSECURE")
+	viper.BindEnv("telemetry.sample_ratio", "OKRA_TELEMETRY_SAMPLE_RATIO")
+	viper.BindEnv("auth.enabled", "OKRA_AUTH_ENABLED")
+	viper.BindEnv("auth.public_reads", "OKRA_AUTH_PUBLIC_READS")
+	viper.BindEnv("auth.jwt.jwks_file", "OKRA_AUTH_JWT_JWKS_FILE")
+	viper.BindEnv("auth.jwt.issuer", "OKRA_AUTH_JWT_ISSUER")
+	viper.BindEnv("auth.jwt.audience", "OKRA_AUTH_JWT_AUDIENCE")
 
 	// Read config file (optional - will use defaults if not found)
 	if err := viper.ReadInConfig(); err != nil {
@@ -51,35 +214,136 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
-	// Resolve relative paths relative to the config file location (if config file was found)
-	// or relative to current working directory
-	credentialsFile := viper.GetString("firestore.credentials_file")
-	if credentialsFile != "" && !filepath.IsAbs(credentialsFile) {
-		if configFile := viper.ConfigFileUsed(); configFile != "" {
-			// Resolve relative to config file directory
-			configDir := filepath.Dir(configFile)
-			credentialsFile = filepath.Join(configDir, credentialsFile)
-			// Clean the path (removes ./ and ../)
-			credentialsFile = filepath.Clean(credentialsFile)
-		}
-		// If no config file was used, relative paths are resolved from current working directory
-		// which is the default behavior, so no change needed
+	credentialsFile := resolvePath(viper.GetString("firestore.credentials_file"))
+
+	var apiKeys []APIKeyConfig
+	if err := viper.UnmarshalKey("auth.api_keys", &apiKeys); err != nil {
+		return nil, fmt.Errorf("invalid auth.api_keys: %w", err)
+	}
+	var defaultRateLimit RateLimitPolicy
+	if err := viper.UnmarshalKey("rate_limit.default", &defaultRateLimit); err != nil {
+		return nil, fmt.Errorf("invalid rate_limit.default: %w", err)
+	}
+	var routeRateLimits []RouteRateLimitConfig
+	if err := viper.UnmarshalKey("rate_limit.routes", &routeRateLimits); err != nil {
+		return nil, fmt.Errorf("invalid rate_limit.routes: %w", err)
 	}
 
 	config := &Config{
 		Server: ServerConfig{
-			Port: viper.GetString("server.port"),
+			Port:             viper.GetString("server.port"),
+			AdminPort:        viper.GetString("server.admin_port"),
+			GRPCPort:         viper.GetString("server.grpc_port"),
+			ReadinessTimeout: viper.GetDuration("server.readiness_timeout"),
+			ReadTimeout:      viper.GetDuration("server.read_timeout"),
+			WriteTimeout:     viper.GetDuration("server.write_timeout"),
+			IdleTimeout:      viper.GetDuration("server.idle_timeout"),
+			MaxBodyBytes:     viper.GetInt64("server.max_body_bytes"),
+			StrictJSON:       viper.GetBool("server.strict_json"),
+			ValidateOpenAPI:  viper.GetBool("server.validate_openapi"),
+			CORS: CORSConfig{
+				AllowedOrigins:   viper.GetStringSlice("server.cors.allowed_origins"),
+				AllowedMethods:   viper.GetStringSlice("server.cors.allowed_methods"),
+				AllowedHeaders:   viper.GetStringSlice("server.cors.allowed_headers"),
+				AllowCredentials: viper.GetBool("server.cors.allow_credentials"),
+				MaxAge:           viper.GetDuration("server.cors.max_age"),
+			},
 		},
 		Firestore: FirestoreConfig{
 			ProjectID:       viper.GetString("firestore.project_id"),
 			DatabaseID:      viper.GetString("firestore.database_id"),
 			CredentialsFile: credentialsFile,
 		},
+		Uom: UomConfig{
+			IDStrategy: viper.GetString("uom.id_strategy"),
+		},
+		Cache: CacheConfig{
+			Enabled:            viper.GetBool("cache.enabled"),
+			TTL:                viper.GetDuration("cache.ttl"),
+			Watch:              viper.GetBool("cache.watch"),
+			MaxTenants:         viper.GetInt("cache.max_tenants"),
+			Metrics:            viper.GetBool("cache.metrics"),
+			MetricsLogInterval: viper.GetDuration("cache.metrics_log_interval"),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        viper.GetBool("rate_limit.enabled"),
+			TrustedProxies: trustedProxies(),
+			Default:        defaultRateLimit,
+			Routes:         routeRateLimits,
+		},
+		Log: LogConfig{
+			Level:  viper.GetString("log.level"),
+			Format: viper.GetString("log.format"),
+		},
+		Telemetry: TelemetryConfig{
+			ServiceName:    viper.GetString("telemetry.service_name"),
+			Exporter:       viper.GetString("telemetry.exporter"),
+			Protocol:       viper.GetString("telemetry.protocol"),
+			Endpoint:       viper.GetString("telemetry.endpoint"),
+			Insecure:       viper.GetBool("telemetry.insecure"),
+			File:           resolvePath(viper.GetString("telemetry.file")),
+			SampleRatio:    viper.GetFloat64("telemetry.sample_ratio"),
+			MetricInterval: viper.GetDuration("telemetry.metric_interval"),
+		},
+		Auth: AuthConfig{
+			Enabled:     viper.GetBool("auth.enabled"),
+			PublicReads: viper.GetBool("auth.public_reads"),
+			APIKeys:     apiKeys,
+			JWT: JWTConfig{
+				JWKSFile:    resolvePath(viper.GetString("auth.jwt.jwks_file")),
+				Issuer:      viper.GetString("auth.jwt.issuer"),
+				Audience:    viper.GetString("auth.jwt.audience"),
+				RoleClaim:   viper.GetString("auth.jwt.role_claim"),
+				TenantClaim: viper.GetString("auth.jwt.tenant_claim"),
+				Leeway:      viper.GetDuration("auth.jwt.leeway"),
+			},
+		},
 	}
 
 	if config.Firestore.ProjectID == "" {
 		return nil, fmt.Errorf("firestore.project_id is required (set via config file or OKRA_FIRESTORE_PROJECT_ID env var)")
 	}
 
+	if config.Auth.Enabled && len(config.Auth.APIKeys) == 0 && config.Auth.JWT.JWKSFile == "" {
+		return nil, fmt.Errorf("auth.enabled needs auth.api_keys or auth.jwt.jwks_file")
+	}
+
+	if err := validateCORS(config.Server.CORS); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
+
+// validateCORS rejects credentials for any origin: every site a user visits could then
+// call the API with the user's cookies and authorization headers
+func validateCORS(c CORSConfig) error {
+	if c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		return fmt.Errorf("server.cors.allow_credentials needs explicit server.cors.allowed_origins, not \"*\"")
+	}
+	return nil
+}
+
+// trustedProxies reads rate_limit.trusted_proxies, or 1 for the older
+// rate_limit.trust_proxy: true, which meant a single load balancer
+func trustedProxies() int {
+	if n := viper.GetInt("rate_limit.trusted_proxies"); n > 0 || !viper.GetBool("rate_limit.trust_proxy") {
+		return n
+	}
+	return 1
+}
+
+// resolvePath resolves a relative path relative to the config file location (if a
+// config file was found) or relative to the current working directory
+func resolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		// Resolve relative to config file directory and clean the path (removes ./ and ../)
+		return filepath.Clean(filepath.Join(filepath.Dir(configFile), path))
+	}
+	// If no config file was used, relative paths are resolved from current working directory
+	// which is the default behavior, so no change needed
+	return path
+}
@@ -0,0 +1,47 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestValidateCORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		cors    CORSConfig
+		wantErr bool
+	}{
+		{"disabled", CORSConfig{}, false},
+		{"any origin", CORSConfig{AllowedOrigins: []string{"*"}}, false},
+		{"listed origins with credentials", CORSConfig{AllowedOrigins: []string{"https://admin.example.com"}, AllowCredentials: true}, false},
+		{"any origin with credentials", CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, true},
+		{"any among listed origins with credentials", CORSConfig{AllowedOrigins: []string{"https://admin.example.com", "*"}, AllowCredentials: true}, true},
+	}
+	for _, tt := range tests {
+		if err := validateCORS(tt.cors); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateCORS() = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	tests := []struct {
+		trustedProxies int
+		trustProxy     bool
+		want           int
+	}{
+		{0, false, 0},
+		{0, true, 1},
+		{2, false, 2},
+		{2, true, 2},
+	}
+	for _, tt := range tests {
+		viper.Set("rate_limit.trusted_proxies", tt.trustedProxies)
+		viper.Set("rate_limit.trust_proxy", tt.trustProxy)
+		if got := trustedProxies(); got != tt.want {
+			t.Errorf("trusted_proxies %d, trust_proxy %v: got %d, want %d", tt.trustedProxies, tt.trustProxy, got, tt.want)
+		}
+	}
+	viper.Reset()
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// unicodeFractions maps the vulgar fraction glyphs to their values
+var unicodeFractions = map[rune]Rational{
+	'¼': NewRational(1, 4), '½': NewRational(1, 2), '¾': NewRational(3, 4),
+	'⅓': NewRational(1, 3), '⅔': NewRational(2, 3),
+	'⅕': NewRational(1, 5), '⅖': NewRational(2, 5), '⅗': NewRational(3, 5), '⅘': NewRational(4, 5),
+	'⅙': NewRational(1, 6), '⅚': NewRational(5, 6),
+	'⅛': NewRational(1, 8), '⅜': NewRational(3, 8), '⅝': NewRational(5, 8), '⅞': NewRational(7, 8),
+}
+
+// ParseAmount reads an amount from the start of text, ignoring leading whitespace.
+// It understands integers, decimals, fractions ("1/2"), mixed numbers ("1 1/2") and
+// vulgar fraction glyphs ("½", "1½", "1 ½"). It returns the exact value and the number
+// of bytes consumed, or ok=false if text doesn't start with an amount.
+func ParseAmount(text string) (value Rational, n int, ok bool) {
+	i := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+
+	whole, end, ok := parseNumber(text, i)
+	if !ok {
+		// A lone glyph ("½ cup")
+		if frac, size := parseGlyph(text, i); size > 0 {
+			return frac, i + size, true
+		}
+		return Rational{}, 0, false
+	}
+
+	// Simple fraction "1/2"
+	if end < len(text) && text[end] == '/' {
+		if den, denEnd, ok := parseDigits(text, end+1); ok && !den.IsZero() {
+			return whole.Quo(den), denEnd, true
+		}
+		return whole, end, true
+	}
+
+	// Glyph directly after the whole number ("1½")
+	if frac, size := parseGlyph(text, end); size > 0 {
+		return whole.Add(frac), end + size, true
+	}
+
+	// Mixed number: whole, whitespace, then a fraction or glyph ("1 1/2", "1 ½")
+	j := end
+	for j < len(text) && (text[j] == ' ' || text[j] == '\t') {
+		j++
+	}
+	if j > end && whole.rat().IsInt() {
+		if frac, size := parseGlyph(text, j); size > 0 {
+			return whole.Add(frac), j + size, true
+		}
+		if num, numEnd, ok := parseDigits(text, j); ok && numEnd < len(text) && text[numEnd] == '/' {
+			if den, denEnd, ok := parseDigits(text, numEnd+1); ok && !den.IsZero() && num.Cmp(den) < 0 {
+				return whole.Add(num.Quo(den)), denEnd, true
+			}
+		}
+	}
+
+	return whole, end, true
+}
+
+// rangeSeparators join the bounds of a range, longest first so " to " wins over "-"
+var rangeSeparators = []string{"to", "or", "–", "—", "-"}
+
+// ParseAmountRange reads an amount or a range of amounts ("2-3", "2–3", "1 to 1 ½",
+// "2 or 3") from the start of text. For a single amount lo == hi.
+func ParseAmountRange(text string) (lo, hi Rational, n int, ok bool) {
+	lo, n, ok = ParseAmount(text)
+	if !ok {
+		return Rational{}, Rational{}, 0, false
+	}
+	rest := strings.TrimLeft(text[n:], " \t")
+	skipped := len(text) - n - len(rest)
+	for _, sep := range rangeSeparators {
+		if !strings.HasPrefix(rest, sep) {
+			continue
+		}
+		// Words need a space after them ("2 tomatoes" isn't "2 to matoes")
+		after := rest[len(sep):]
+		if r, _ := utf8.DecodeRuneInString(sep); unicode.IsLetter(r) && !strings.HasPrefix(after, " ") {
+			continue
+		}
+		if v, m, ok := ParseAmount(after); ok && v.Cmp(lo) >= 0 {
+			return lo, v, n + skipped + len(sep) + m, true
+		}
+	}
+	return lo, lo, n, true
+}
+
+// parseNumber reads an integer or decimal starting at i
+func parseNumber(text string, i int) (Rational, int, bool) {
+	j := i
+	for j < len(text) && (isDigit(text[j]) || text[j] == '.') {
+		j++
+	}
+	// Don't swallow a trailing period ("2. cups")
+	for j > i && text[j-1] == '.' {
+		j--
+	}
+	return parseRat(text, i, j)
+}
+
+// parseDigits reads an unsigned integer starting at i
+func parseDigits(text string, i int) (Rational, int, bool) {
+	j := i
+	for j < len(text) && isDigit(text[j]) {
+		j++
+	}
+	return parseRat(text, i, j)
+}
+
+// parseRat reads the decimal text[i:j] exactly
+func parseRat(text string, i, j int) (Rational, int, bool) {
+	if j == i {
+		return Rational{}, i, false
+	}
+	r, ok := new(big.Rat).SetString(text[i:j])
+	if !ok {
+		return Rational{}, i, false
+	}
+	return Rational{r: r}, j, true
+}
+
+// parseGlyph reads a vulgar fraction glyph at i, returning its value and size in bytes
+func parseGlyph(text string, i int) (Rational, int) {
+	if i >= len(text) {
+		return Rational{}, 0
+	}
+	r, size := utf8.DecodeRuneInString(text[i:])
+	if v, ok := unicodeFractions[r]; ok {
+		return v, size
+	}
+	return Rational{}, 0
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
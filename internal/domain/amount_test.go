@@ -0,0 +1,70 @@
+package domain
+
+import "testing"
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		text string
+		want string // the exact value, empty when text doesn't start with an amount
+		n    int    // bytes consumed
+	}{
+		{"2", "2", 1},
+		{"2 cups", "2", 1},
+		{"  2 cups", "2", 3},
+		{"0.5", "1/2", 3},
+		{"0.333", "333/1000", 5},
+		{"1/3", "1/3", 3},
+		{"4/3 cup", "4/3", 3},
+		{"1 1/2 cups", "3/2", 5},
+		{"1\t1/2", "3/2", 5},
+		{"½ cup", "1/2", len("½")},
+		{"⅓", "1/3", len("⅓")},
+		{"⅞ tsp", "7/8", len("⅞")},
+		{"1½ cups", "3/2", len("1½")},
+		{"1 ⅓ cups", "4/3", len("1 ⅓")},
+		{"2 ¾", "11/4", len("2 ¾")},
+		{"2. cups", "2", 1},
+		{"1/0 cups", "1", 1},
+		{"1.5 1/2", "3/2", 3},  // a decimal doesn't start a mixed number
+		{"1 3/2 cups", "1", 1}, // nor does an improper fraction
+		{"2 14.5 oz", "2", 1},
+		{"cup", "", 0},
+		{"", "", 0},
+		{"/2", "", 0},
+	}
+	for _, tt := range tests {
+		got, n, ok := ParseAmount(tt.text)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("ParseAmount(%q) = %s, want no amount", tt.text, got)
+			}
+			continue
+		}
+		if !ok || got.String() != tt.want || n != tt.n {
+			t.Errorf("ParseAmount(%q) = %s, %d, %v; want %s, %d", tt.text, got, n, ok, tt.want, tt.n)
+		}
+	}
+}
+
+func TestParseAmountRange(t *testing.T) {
+	tests := []struct {
+		text   string
+		lo, hi string
+		n      int
+	}{
+		{"2-3 tbsp", "2", "3", 3},
+		{"2–3 tbsp", "2", "3", len("2–3")},
+		{"2 - 3 tbsp", "2", "3", 5},
+		{"1 to 1 ½ cups", "1", "3/2", len("1 to 1 ½")},
+		{"2 or 3 eggs", "2", "3", 6},
+		{"½-¾ cup", "1/2", "3/4", len("½-¾")},
+		{"2 tomatoes", "2", "2", 1},
+		{"3-2 cups", "3", "3", 1}, // a reversed range is a dash, not a range
+	}
+	for _, tt := range tests {
+		lo, hi, n, ok := ParseAmountRange(tt.text)
+		if !ok || lo.String() != tt.lo || hi.String() != tt.hi || n != tt.n {
+			t.Errorf("ParseAmountRange(%q) = %s, %s, %d, %v; want %s, %s, %d", tt.text, lo, hi, n, ok, tt.lo, tt.hi, tt.n)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"os"
+	"sync"
+	"testing"
+)
+
+// testCatalogFile is the default catalog pkg/okra embeds, so tests and benchmarks run
+// against the uoms real callers get
+const testCatalogFile = "../../pkg/okra/catalog.json"
+
+var loadTestCatalog = sync.OnceValues(func() (*UomCatalog, error) {
+	f, err := os.Open(testCatalogFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	uoms, err := DecodeUoms(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewUomCatalog(uoms, 1), nil
+})
+
+// testCatalog returns the shared test catalog. Tests must not modify it.
+func testCatalog(tb testing.TB) *UomCatalog {
+	tb.Helper()
+	catalog, err := loadTestCatalog()
+	if err != nil {
+		tb.Fatalf("failed to load %s: %v", testCatalogFile, err)
+	}
+	return catalog
+}
+
+// testUom returns the uom of the test catalog with the label
+func testUom(tb testing.TB, label string) *Uom {
+	tb.Helper()
+	uom := testCatalog(tb).ByLabel(label)
+	if uom == nil {
+		tb.Fatalf("no uom %q in the test catalog", label)
+	}
+	return uom
+}
+
+// rat parses an amount, failing the test if it is invalid
+func rat(tb testing.TB, s string) Rational {
+	tb.Helper()
+	r, err := ParseRational(s)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return r
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// massNouns read the same for any count, or end in "s" without being plurals
+var massNouns = map[string]bool{
+	"asparagus": true, "broccoli": true, "couscous": true, "flour": true, "grits": true,
+	"hummus": true, "molasses": true, "oats": true, "octopus": true, "pastis": true,
+	"rice": true, "salt": true, "sugar": true, "swiss": true, "water": true, "citrus": true,
+	"cress": true, "watercress": true, "series": true, "species": true, "fish": true,
+	"sheep": true, "deer": true, "bass": true, "squash": true, "moose": true,
+}
+
+// irregularPlurals are the singular and plural nouns the suffix rules get wrong
+var irregularPlurals = map[string]string{
+	"leaf": "leaves", "loaf": "loaves", "half": "halves", "knife": "knives", "calf": "calves",
+	"tomato": "tomatoes", "potato": "potatoes", "mango": "mangoes", "echo": "echoes",
+	"cookie": "cookies", "brownie": "brownies", "smoothie": "smoothies", "veggie": "veggies",
+	"quiche": "quiches", "brioche": "brioches", "goose": "geese", "tooth": "teeth",
+	"die": "dice", "child": "children", "person": "people", "mouse": "mice",
+}
+
+// irregularSingulars inverts irregularPlurals
+var irregularSingulars = func() map[string]string {
+	m := make(map[string]string, len(irregularPlurals))
+	for singular, plural := range irregularPlurals {
+		m[plural] = singular
+	}
+	return m
+}()
+
+// SingularNoun returns the singular of an English noun: "eggs" -> "egg", "berries" ->
+// "berry". Mass nouns ("molasses", "asparagus") and singular nouns are returned unchanged.
+func SingularNoun(word string) string {
+	lower := strings.ToLower(word)
+	if massNouns[lower] {
+		return word
+	}
+	if singular, ok := irregularSingulars[lower]; ok {
+		return matchCase(singular, word)
+	}
+	if _, ok := irregularPlurals[lower]; ok {
+		return word
+	}
+	switch {
+	case len(lower) > 4 && strings.HasSuffix(lower, "ies"):
+		return word[:len(word)-3] + suffixCase("y", word)
+	case len(lower) > 4 && hasAnySuffix(lower, "ches", "shes", "xes", "sses", "zzes"):
+		return word[:len(word)-2]
+	case len(lower) > 3 && strings.HasSuffix(lower, "s") && !hasAnySuffix(lower, "ss", "us"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// PluralNoun returns the plural of an English noun: "egg" -> "eggs", "berry" ->
+// "berries". Mass nouns and plural nouns are returned unchanged.
+func PluralNoun(word string) string {
+	lower := strings.ToLower(word)
+	if massNouns[lower] {
+		return word
+	}
+	if plural, ok := irregularPlurals[lower]; ok {
+		return matchCase(plural, word)
+	}
+	if _, ok := irregularSingulars[lower]; ok || SingularNoun(word) != word {
+		return word
+	}
+	suffix := "s"
+	switch {
+	case len(lower) > 1 && strings.HasSuffix(lower, "y") && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		word, suffix = word[:len(word)-1], "ies"
+	case hasAnySuffix(lower, "s", "x", "z", "ch", "sh"):
+		suffix = "es"
+	}
+	return word + suffixCase(suffix, word)
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCase returns s in upper case when like is all upper case, and capitalized
+// when like is
+func matchCase(s, like string) string {
+	if isUpperWord(like) {
+		return strings.ToUpper(s)
+	}
+	if r, _ := utf8.DecodeRuneInString(like); unicode.IsUpper(r) {
+		first, size := utf8.DecodeRuneInString(s)
+		return string(unicode.ToUpper(first)) + s[size:]
+	}
+	return s
+}
+
+// suffixCase returns the suffix in upper case when it ends an upper case word
+func suffixCase(suffix, word string) string {
+	if isUpperWord(word) {
+		return strings.ToUpper(suffix)
+	}
+	return suffix
+}
+
+func isUpperWord(s string) bool {
+	return strings.ToUpper(s) == s && strings.ToLower(s) != s
+}
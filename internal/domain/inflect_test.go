@@ -0,0 +1,50 @@
+package domain
+
+import "testing"
+
+func TestInflectNouns(t *testing.T) {
+	tests := []struct {
+		singular, plural string
+	}{
+		{"egg", "eggs"},
+		{"onion", "onions"},
+		{"berry", "berries"},
+		{"anchovy", "anchovies"},
+		{"peach", "peaches"},
+		{"radish", "radishes"},
+		{"box", "boxes"},
+		{"glass", "glasses"},
+		{"tomato", "tomatoes"},
+		{"avocado", "avocados"},
+		{"leaf", "leaves"},
+		{"clove", "cloves"},
+		{"olive", "olives"},
+		{"cookie", "cookies"},
+		{"pie", "pies"},
+		{"pea", "peas"},
+		{"kiwi", "kiwis"},
+		{"key", "keys"},
+		{"Egg", "Eggs"},
+		{"EGG", "EGGS"},
+		{"Leaf", "Leaves"},
+		{"molasses", "molasses"},
+		{"asparagus", "asparagus"},
+		{"hummus", "hummus"},
+		{"couscous", "couscous"},
+		{"rice", "rice"},
+	}
+	for _, tt := range tests {
+		if got := PluralNoun(tt.singular); got != tt.plural {
+			t.Errorf("PluralNoun(%q) = %q, want %q", tt.singular, got, tt.plural)
+		}
+		if got := PluralNoun(tt.plural); got != tt.plural {
+			t.Errorf("PluralNoun(%q) = %q, want it unchanged", tt.plural, got)
+		}
+		if got := SingularNoun(tt.plural); got != tt.singular {
+			t.Errorf("SingularNoun(%q) = %q, want %q", tt.plural, got, tt.singular)
+		}
+		if got := SingularNoun(tt.singular); got != tt.singular {
+			t.Errorf("SingularNoun(%q) = %q, want it unchanged", tt.singular, got)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// IngredientLine is a recipe line split into its parts, e.g.
+// "2 (14.5 oz) cans diced tomatoes, drained" is quantity 2 cans, package size
+// 14.5 oz, ingredient "diced tomatoes" and preparation "drained".
+type IngredientLine struct {
+	Text        string
+	Quantity    *Quantity  // nil when the line has no quantity at all ("salt")
+	UomName     string     // the match name the uom was recognized by, e.g. "tbsp" for "Tbsp."
+	PackageSize *UomAmount // the size of one package, from a parenthetical right after the amount
+	Size        string     // "small", "large", ...
+	Ingredient  string
+	Preparation string // what follows the first comma: "finely chopped", "drained"
+	Notes       string // parentheticals after the ingredient and text after a semicolon
+	Confidence  IngredientConfidence
+}
+
+// IngredientConfidence scores how sure the parser is about each part, from 0 to 1
+type IngredientConfidence struct {
+	Overall    float64
+	Amount     float64
+	Uom        float64
+	Ingredient float64
+}
+
+// approximateWords mark an approximate quantity, longest first
+var approximateWords = []string{"approximately", "approx.", "approx", "roughly", "around", "about", "~"}
+
+// qualitativePrefixes are quantities without a number written before the ingredient,
+// longest first. The article is dropped from the stored quantity ("a pinch" -> "pinch").
+var qualitativePrefixes = []string{"a handful of", "a pinch of", "a splash of", "a dash of", "a few", "handful of", "pinch of", "splash of", "dash of", "some"}
+
+// qualitativeSuffixes are quantities without a number written after the ingredient
+var qualitativeSuffixes = []string{"to taste", "as needed", "as required", "to serve", "for garnish"}
+
+// uomConnectors join a uom to the ingredient ("1 cup of flour", "2 tazas de harina"),
+// by language. Elided forms end in an apostrophe and need no space ("d'huile").
+var uomConnectors = map[string][]string{
+	"en": {"of"},
+	"es": {"de", "del"},
+	"fr": {"de", "du", "d'", "d’"},
+	"it": {"di", "del", "della", "d'", "d’"},
+	"pt": {"de", "do", "da"},
+}
+
+// ingredientSizes are the size words recognized before or after the uom, longest first
+var ingredientSizes = []string{"extra large", "extra-large", "small", "medium", "large", "jumbo"}
+
+// Description is everything after the quantity: size, ingredient, preparation and notes
+func (l IngredientLine) Description() string {
+	var b strings.Builder
+	if l.Size != "" {
+		b.WriteString(l.Size + " ")
+	}
+	b.WriteString(l.Ingredient)
+	if l.Preparation != "" {
+		b.WriteString(", " + l.Preparation)
+	}
+	if l.Notes != "" {
+		b.WriteString(" (" + l.Notes + ")")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// AgreeWithCount inflects the last word of an ingredient counted without a uom so it
+// agrees with the count: "egg" for 1 or ½ and "eggs" for 4 or 1–2. Other quantities,
+// and locales other than English, leave the ingredient as it is.
+func AgreeWithCount(ingredient string, q Quantity, locales []language.Tag) string {
+	if q.Uom != nil || q.IsQualitative() || ingredient == "" {
+		return ingredient
+	}
+	if base, _ := LocaleChain(locales)[0].Base(); base != englishBase {
+		return ingredient
+	}
+	i := strings.LastIndexFunc(ingredient, func(r rune) bool { return !unicode.IsLetter(r) }) + 1
+	word := ingredient[i:]
+	if word == "" {
+		return ingredient
+	}
+	if isSingularCount(q.Max) {
+		return ingredient[:i] + SingularNoun(word)
+	}
+	return ingredient[:i] + PluralNoun(word)
+}
+
+var englishBase, _ = language.English.Base()
+
+// isSingularCount reports whether a count reads as one thing: 1, or a vulgar fraction
+// on its own ("½ onion"), like the uom names do
+func isSingularCount(amount Rational) bool {
+	f := amount.Float64()
+	if _, ok := vulgarFraction(f); ok {
+		return true
+	}
+	return PluralCategory(language.English, f) == PLURAL_ONE
+}
+
+// JoinQuantity writes a printed quantity and the ingredient it measures as one line.
+// Qualitative quantities read as they are written in recipes: "salt to taste", "a
+// pinch of salt"; the others go first: "1 cup flour".
+func JoinQuantity(text string, q *Quantity, ingredient string) string {
+	if q != nil && q.IsQualitative() {
+		if lead := qualitativeLead(q.Qualitative); lead != "" {
+			return strings.TrimSpace(lead + " " + ingredient)
+		}
+		return strings.TrimSpace(ingredient + " " + text)
+	}
+	return strings.TrimSpace(text + " " + ingredient)
+}
+
+// qualitativeLead returns the words a qualitative quantity parsed from before the
+// ingredient is written with ("a pinch of" for "pinch"), or "" for the other ones,
+// which follow the ingredient
+func qualitativeLead(qualitative string) string {
+	for _, prefix := range qualitativePrefixes {
+		if strings.TrimPrefix(strings.TrimSuffix(prefix, " of"), "a ") == qualitative {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// ParseIngredientLine splits a recipe line using the catalog's recipe matcher for the
+// preferred locales. It never fails: parts it can't find are left empty and lower
+// the confidence instead.
+func ParseIngredientLine(text string, catalog *UomCatalog, locales []language.Tag) IngredientLine {
+	line := IngredientLine{Text: text}
+	matcher := catalog.RecipeMatcher(locales)
+	rest := strings.TrimLeftFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '*' || r == '•'
+	})
+
+	// Amount or range, possibly approximate: "about 2-3"
+	approximate := false
+	if _, after, ok := cutWord(rest, approximateWords); ok {
+		approximate, rest = true, after
+	}
+	amountScore := 0.0
+	var quantity Quantity
+	if lo, hi, n, ok := ParseAmountRange(rest); ok {
+		quantity = NewQuantityRange(lo, hi, nil)
+		rest = rest[n:]
+		amountScore = 1
+	} else if article, after, ok := cutWord(rest, []string{"one", "an", "a"}); ok {
+		// "one onion", "a cup of sugar" and "a large onion", but not "a pinch of salt"
+		_, match := matcher.MatchPrefix(after)
+		size, _ := cutSize(after)
+		if article == "one" || match || size != "" {
+			quantity = NewQuantity(IntRational(1), nil)
+			rest = after
+			amountScore = 0.9
+		}
+	}
+	quantity.Approximate = approximate
+
+	// Package size: "2 (14.5 oz) cans"
+	if amountScore > 0 {
+		if inner, after, ok := leadingParenthetical(rest); ok {
+			if size, n, ok := ParseAmount(inner); ok {
+				if match, ok := matcher.MatchPrefix(inner[n:]); ok && strings.TrimSpace(inner[n+match.End:]) == "" {
+					line.PackageSize = &UomAmount{Amount: size, Uom: match.Uom}
+					rest = after
+				}
+			}
+		}
+	}
+
+	// Size and uom, in either order: "1 large can", "2 large eggs"
+	line.Size, rest = cutSize(rest)
+	uomScore := 0.0
+	if match, ok := matcher.MatchPrefix(rest); ok && amountScore > 0 {
+		quantity.Uom = match.Uom
+		line.UomName = match.Name
+		rest = rest[match.End:]
+		uomScore = 1
+		if line.Size == "" {
+			line.Size, rest = cutSize(rest)
+		}
+		rest = cutConnector(rest, locales)
+	} else if amountScore > 0 {
+		// A plain count ("2 eggs") is common, but so is an unknown uom
+		uomScore = 0.7
+	}
+	if amountScore > 0 {
+		line.Quantity = &quantity
+	} else {
+		// Quantities without a number: "a pinch of salt", "pepper to taste"
+		if phrase, after, ok := cutWord(rest, qualitativePrefixes); ok {
+			q := QualitativeQuantity(strings.TrimPrefix(strings.TrimSuffix(phrase, " of"), "a "))
+			line.Quantity, rest = &q, after
+		}
+	}
+
+	if line.Quantity == nil {
+		for _, phrase := range qualitativeSuffixes {
+			start, end := indexFold(rest, phrase)
+			if start <= 0 || !endsWord(rest, end) {
+				continue
+			}
+			if prev, _ := utf8.DecodeLastRuneInString(rest[:start]); isMatcherSeparator(prev) {
+				q := QualitativeQuantity(phrase)
+				line.Quantity = &q
+				rest = rest[:start] + rest[end:]
+				break
+			}
+		}
+	}
+
+	// Notes after a semicolon
+	if before, after, ok := strings.Cut(rest, ";"); ok {
+		line.Notes = strings.TrimSpace(after)
+		rest = before
+	}
+	// Preparation after the first comma
+	if before, after, ok := strings.Cut(rest, ","); ok {
+		line.Preparation = strings.TrimSpace(after)
+		rest = before
+	}
+	// Parentheticals in the ingredient are notes
+	rest, notes := cutParentheticals(rest)
+	if notes != "" {
+		if line.Notes != "" {
+			notes += "; " + line.Notes
+		}
+		line.Notes = notes
+	}
+	line.Ingredient = strings.Join(strings.Fields(rest), " ")
+
+	ingredientScore := 0.0
+	if line.Ingredient != "" {
+		ingredientScore = 1
+		if strings.ContainsFunc(line.Ingredient, unicode.IsDigit) {
+			// Digits left over usually mean an amount we didn't understand
+			ingredientScore = 0.5
+		}
+	}
+
+	line.Confidence = IngredientConfidence{
+		Amount:     amountScore,
+		Uom:        uomScore,
+		Ingredient: ingredientScore,
+	}
+	if amountScore == 0 {
+		// "salt to taste" has no amount to get wrong
+		line.Confidence.Overall = ingredientScore * 0.8
+	} else {
+		line.Confidence.Overall = (amountScore + uomScore + ingredientScore) / 3
+	}
+	return line
+}
+
+// leadingParenthetical returns the contents of a parenthetical at the start of s and what follows it
+func leadingParenthetical(s string) (inner, after string, ok bool) {
+	t := strings.TrimLeftFunc(s, unicode.IsSpace)
+	if !strings.HasPrefix(t, "(") {
+		return "", s, false
+	}
+	end := strings.IndexByte(t, ')')
+	if end < 0 {
+		return "", s, false
+	}
+	return t[1:end], t[end+1:], true
+}
+
+// cutParentheticals removes every "(...)" from s and returns them joined with "; "
+func cutParentheticals(s string) (string, string) {
+	var notes []string
+	for {
+		start := strings.IndexByte(s, '(')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], ')')
+		if end < 0 {
+			break
+		}
+		notes = append(notes, strings.TrimSpace(s[start+1:start+end]))
+		s = s[:start] + " " + s[start+end+1:]
+	}
+	return s, strings.Join(notes, "; ")
+}
+
+// cutConnector removes the word joining the uom to the ingredient, in the first
+// language of the locale chain that has one there
+func cutConnector(s string, locales []language.Tag) string {
+	t := strings.TrimLeftFunc(s, unicode.IsSpace)
+	for _, tag := range LocaleChain(locales) {
+		base, _ := tag.Base()
+		for _, connector := range uomConnectors[base.String()] {
+			n, ok := prefixFold(t, connector)
+			if !ok {
+				continue
+			}
+			if strings.HasSuffix(connector, "'") || strings.HasSuffix(connector, "’") {
+				return t[n:]
+			}
+			if n < len(t) && t[n] == ' ' {
+				return t[n+1:]
+			}
+		}
+	}
+	return t
+}
+
+// cutWord removes the first of words (case-insensitive) that starts s and is followed
+// by a space, so "large-flake oats" doesn't start with "large". Words ending in
+// punctuation ("approx.", "~") may be followed by anything.
+func cutWord(s string, words []string) (string, string, bool) {
+	t := strings.TrimLeftFunc(s, unicode.IsSpace)
+	for _, w := range words {
+		if n, ok := prefixFold(t, w); ok && (len(t) == n || t[n] == ' ' || !isWordByte(w[len(w)-1])) {
+			return w, t[n:], true
+		}
+	}
+	return "", s, false
+}
+
+// prefixFold reports whether s starts with prefix, ignoring case as strings.EqualFold
+// does, and returns the length of the match in s. It compares s itself rather than a
+// lowered copy, whose byte offsets can differ: "Ⱥ" is two bytes and "ⱥ" three.
+func prefixFold(s, prefix string) (int, bool) {
+	n := 0
+	for _, want := range prefix {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if size == 0 || r != want && !strings.EqualFold(string(r), string(want)) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
+
+// indexFold returns the byte offsets in s of the first case-insensitive match of
+// substr, or -1, -1
+func indexFold(s, substr string) (start, end int) {
+	for i := range s {
+		if n, ok := prefixFold(s[i:], substr); ok {
+			return i, i + n
+		}
+	}
+	return -1, -1
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9'
+}
+
+// cutSize removes a leading size word from s
+func cutSize(s string) (string, string) {
+	size, rest, _ := cutWord(s, ingredientSizes)
+	return size, rest
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestParseIngredientLine(t *testing.T) {
+	type want struct {
+		quantity    string // formatted, empty when the line has none
+		packageSize string
+		size        string
+		ingredient  string
+		preparation string
+		notes       string
+	}
+	tests := []struct {
+		text    string
+		locales []language.Tag
+		want    want
+	}{
+		{"2 (14.5 oz) cans diced tomatoes, drained", nil, want{quantity: "2 cans", packageSize: "14 ½ oz", ingredient: "diced tomatoes", preparation: "drained"}},
+		{"1 (28 oz) can crushed tomatoes", nil, want{quantity: "1 can", packageSize: "28 oz", ingredient: "crushed tomatoes"}},
+		{"2 Tbsp. butter, melted", nil, want{quantity: "2 tbsp", ingredient: "butter", preparation: "melted"}},
+		{"1 ½ cups all-purpose flour", nil, want{quantity: "1 ½ cups", ingredient: "all-purpose flour"}},
+		{"2-3 tbsp olive oil", nil, want{quantity: "2–3 tbsp", ingredient: "olive oil"}},
+		{"about 1 lb ground beef", nil, want{quantity: "about 1 lb", ingredient: "ground beef"}},
+		{"1 cup walnuts (optional), toasted; or pecans", nil, want{quantity: "1 cup", ingredient: "walnuts", preparation: "toasted", notes: "optional; or pecans"}},
+		{"2 large eggs", nil, want{quantity: "2", size: "large", ingredient: "eggs"}},
+		{"1 large onion, finely chopped", nil, want{quantity: "1", size: "large", ingredient: "onion", preparation: "finely chopped"}},
+		{"a cup of sugar", nil, want{quantity: "1 cup", ingredient: "sugar"}},
+		{"a pinch of salt", nil, want{quantity: "pinch", ingredient: "salt"}},
+		{"pepper to taste", nil, want{quantity: "to taste", ingredient: "pepper"}},
+		{"salt", nil, want{ingredient: "salt"}},
+		{"2 cucharadas de aceite", []language.Tag{language.Spanish}, want{quantity: "2 cdas", ingredient: "aceite"}},
+		{"2 cucharadas del aceite de oliva", []language.Tag{language.Spanish}, want{quantity: "2 cdas", ingredient: "aceite de oliva"}},
+		{"2 cucharadas dextrosa", []language.Tag{language.Spanish}, want{quantity: "2 cdas", ingredient: "dextrosa"}},
+		{"1 cup de-seeded grapes", nil, want{quantity: "1 cup", ingredient: "de-seeded grapes"}},
+	}
+	catalog := testCatalog(t)
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			line := ParseIngredientLine(tt.text, catalog, tt.locales)
+			got := want{
+				size:        line.Size,
+				ingredient:  line.Ingredient,
+				preparation: line.Preparation,
+				notes:       line.Notes,
+			}
+			if line.Quantity != nil {
+				got.quantity = FormatQuantity(*line.Quantity, FormatOptions{Locales: tt.locales})
+			}
+			if line.PackageSize != nil {
+				got.packageSize = Format(*line.PackageSize, FormatOptions{})
+			}
+			if got != tt.want {
+				t.Errorf("ParseIngredientLine(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIngredientLineConfidence(t *testing.T) {
+	catalog := testCatalog(t)
+	known := ParseIngredientLine("2 cups flour", catalog, nil).Confidence
+	count := ParseIngredientLine("2 eggs", catalog, nil).Confidence
+	none := ParseIngredientLine("salt", catalog, nil).Confidence
+
+	if known.Overall <= count.Overall || count.Overall <= none.Overall {
+		t.Errorf("confidence should fall from a known uom (%v) to a count (%v) to no quantity (%v)", known.Overall, count.Overall, none.Overall)
+	}
+	if known.Amount != 1 || known.Uom != 1 {
+		t.Errorf("2 cups flour: confidence %+v, want amount and uom 1", known)
+	}
+}
+
+// Lowercasing changes the byte length of some letters ("Ⱥ" is two bytes, "ⱥ" three), so
+// offsets found in a lowered copy don't fit the line
+func TestParseIngredientLineCaseFolding(t *testing.T) {
+	tests := []struct {
+		text       string
+		quantity   string
+		ingredient string
+	}{
+		{"salt ȺȺȺ to taste", "to taste", "salt ȺȺȺ"},
+		{"ȺȺȺȺȺȺȺȺȺȺȺȺ pepper To Taste", "to taste", "ȺȺȺȺȺȺȺȺȺȺȺȺ pepper"},
+		{"İİİ parsley for garnish", "for garnish", "İİİ parsley"},
+		{"ȺȺȺ", "", "ȺȺȺ"},
+		{"İİ ȺȺ", "", "İİ ȺȺ"},
+		{"ABOUT 2 CUPS Flour", "about 2 cups", "Flour"},
+		{"A Pinch Of salt", "pinch", "salt"},
+	}
+	catalog := testCatalog(t)
+	for _, tt := range tests {
+		line := ParseIngredientLine(tt.text, catalog, nil)
+		var quantity string
+		if line.Quantity != nil {
+			quantity = FormatQuantity(*line.Quantity, FormatOptions{})
+		}
+		if quantity != tt.quantity || line.Ingredient != tt.ingredient {
+			t.Errorf("ParseIngredientLine(%q) = %q, %q; want %q, %q", tt.text, quantity, line.Ingredient, tt.quantity, tt.ingredient)
+		}
+	}
+}
+
+func TestAgreeWithCount(t *testing.T) {
+	cup := testUom(t, "cup")
+	tests := []struct {
+		ingredient string
+		q          Quantity
+		locales    []language.Tag
+		want       string
+	}{
+		{"eggs", NewQuantity(IntRational(1), nil), nil, "egg"},
+		{"egg", NewQuantity(IntRational(4), nil), nil, "eggs"},
+		{"onion", NewQuantity(NewRational(1, 2), nil), nil, "onion"},
+		{"onions", NewQuantity(NewRational(3, 2), nil), nil, "onions"},
+		{"cherry", NewQuantityRange(IntRational(1), IntRational(2), nil), nil, "cherries"},
+		{"green onion", NewQuantity(IntRational(2), nil), nil, "green onions"},
+		{"blueberries", NewQuantity(IntRational(1), cup), nil, "blueberries"},
+		{"salt", QualitativeQuantity("to taste"), nil, "salt"},
+		{"huevo", NewQuantity(IntRational(2), nil), []language.Tag{language.Spanish}, "huevo"},
+		{"vitamin b12", NewQuantity(IntRational(2), nil), nil, "vitamin b12"},
+	}
+	for _, tt := range tests {
+		if got := AgreeWithCount(tt.ingredient, tt.q, tt.locales); got != tt.want {
+			t.Errorf("AgreeWithCount(%q, %s) = %q, want %q", tt.ingredient, FormatQuantity(tt.q, FormatOptions{}), got, tt.want)
+		}
+	}
+}
+
+func TestJoinQuantity(t *testing.T) {
+	cups := NewQuantity(IntRational(2), testUom(t, "cup"))
+	toTaste := QualitativeQuantity("to taste")
+	pinch := QualitativeQuantity("pinch")
+	few := QualitativeQuantity("few")
+	tests := []struct {
+		text       string
+		q          *Quantity
+		ingredient string
+		want       string
+	}{
+		{"2 cups", &cups, "flour", "2 cups flour"},
+		{"to taste", &toTaste, "salt", "salt to taste"},
+		{"pinch", &pinch, "salt", "a pinch of salt"},
+		{"few", &few, "sprigs thyme", "a few sprigs thyme"},
+		{"", nil, "pepper", "pepper"},
+		{"to taste", &toTaste, "", "to taste"},
+	}
+	for _, tt := range tests {
+		if got := JoinQuantity(tt.text, tt.q, tt.ingredient); got != tt.want {
+			t.Errorf("JoinQuantity(%q, %q) = %q, want %q", tt.text, tt.ingredient, got, tt.want)
+		}
+	}
+}
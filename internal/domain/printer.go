@@ -16,6 +16,9 @@ func print(f reflect.Value) string {
 		}
 		return "*" + print(f.Elem())
 	case reflect.Struct:
+		if r, ok := f.Interface().(Rational); ok {
+			return r.String()
+		}
 		return printStruct(f)
 	case reflect.String:
 		return "\"" + f.String() + "\""
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Quantity is an amount of a uom as a recipe writes it: exact ("2 cups"), a range
+// ("2–3 cups"), approximate ("about 1 lb") or qualitative ("a pinch", "to taste").
+// An exact quantity has Min == Max. A nil Uom is a plain count ("2–3 eggs").
+type Quantity struct {
+	Min         Rational
+	Max         Rational
+	Uom         *Uom
+	Approximate bool
+	Qualitative string // set for quantities without a number; Min and Max are zero
+}
+
+// NewQuantity returns an exact quantity
+func NewQuantity(amount Rational, uom *Uom) Quantity {
+	return Quantity{Min: amount, Max: amount, Uom: uom}
+}
+
+// NewQuantityRange returns a range quantity, swapping the bounds if they are reversed
+func NewQuantityRange(lo, hi Rational, uom *Uom) Quantity {
+	if hi.Cmp(lo) < 0 {
+		lo, hi = hi, lo
+	}
+	return Quantity{Min: lo, Max: hi, Uom: uom}
+}
+
+// QualitativeQuantity returns a quantity without a number, e.g. "to taste"
+func QualitativeQuantity(text string) Quantity {
+	return Quantity{Qualitative: text}
+}
+
+func (q Quantity) IsQualitative() bool {
+	return q.Qualitative != ""
+}
+
+func (q Quantity) IsRange() bool {
+	return !q.IsQualitative() && q.Min.Cmp(q.Max) != 0
+}
+
+// Bounds returns the lower and upper bound as amounts of the uom
+func (q Quantity) Bounds() (UomAmount, UomAmount) {
+	return UomAmount{Amount: q.Min, Uom: q.Uom}, UomAmount{Amount: q.Max, Uom: q.Uom}
+}
+
+// Scale multiplies both bounds by factor. Qualitative quantities don't scale.
+func (q Quantity) Scale(factor Rational) Quantity {
+	if q.IsQualitative() {
+		return q
+	}
+	q.Min = q.Min.Mul(factor)
+	q.Max = q.Max.Mul(factor)
+	return q
+}
+
+// ConvertQuantity expresses both bounds of q in the to uom
+func ConvertQuantity(q Quantity, to *Uom) (Quantity, error) {
+	if q.IsQualitative() {
+		return Quantity{}, fmt.Errorf("cannot convert %q: quantity has no amount", q.Qualitative)
+	}
+	if q.Uom == nil {
+		return Quantity{}, fmt.Errorf("cannot convert a count to %s", to.Label)
+	}
+	lo, err := Convert(q.Min, q.Uom, to)
+	if err != nil {
+		return Quantity{}, err
+	}
+	hi, err := Convert(q.Max, q.Uom, to)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Min: lo, Max: hi, Uom: to, Approximate: q.Approximate}, nil
+}
+
+// HumanizeQuantity is Humanize for quantities. Both bounds of a range are expressed in
+// the uom chosen for the lower bound, so "8–12 tbsp" becomes "½–¾ cup" rather than
+// mixing uoms. Qualitative quantities and plain counts are returned unchanged.
+func HumanizeQuantity(q Quantity, candidates []*Uom, system UomSystem) (Quantity, error) {
+	if q.IsQualitative() || q.Uom == nil {
+		return q, nil
+	}
+	lower, err := Humanize(q.Min, q.Uom, candidates, system)
+	if err != nil {
+		return Quantity{}, err
+	}
+	if !q.IsRange() {
+		return Quantity{Min: lower.Amount, Max: lower.Amount, Uom: lower.Uom, Approximate: q.Approximate}, nil
+	}
+	hi, err := Convert(q.Max, q.Uom, lower.Uom)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Min: lower.Amount, Max: Snap(hi, lower.Uom), Uom: lower.Uom, Approximate: q.Approximate}, nil
+}
+
+// QuantityJSON is the JSON form of a quantity. Exact quantities have an amount, ranges
+// have min and max, and qualitative quantities have neither. Amounts are plain numbers;
+// the exact fractions are only kept internally. The uom is given by its id and label.
+type QuantityJSON struct {
+	Amount      *float64 `json:"amount,omitempty"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Approximate bool     `json:"approximate,omitempty"`
+	Qualitative string   `json:"qualitative,omitempty"`
+	UomID       string   `json:"uom_id,omitempty"`
+	Uom         string   `json:"uom,omitempty"`
+}
+
+// JSON returns the JSON form of q, for responses that add fields of their own
+func (q Quantity) JSON() QuantityJSON {
+	j := QuantityJSON{Approximate: q.Approximate, Qualitative: q.Qualitative}
+	switch {
+	case q.IsQualitative():
+	case q.IsRange():
+		lo, hi := q.Min.Float64(), q.Max.Float64()
+		j.Min, j.Max = &lo, &hi
+	default:
+		amount := q.Min.Float64()
+		j.Amount = &amount
+	}
+	if q.Uom != nil {
+		j.UomID = q.Uom.Id
+		j.Uom = q.Uom.Label
+	}
+	return j
+}
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.JSON())
+}
+
+// UnmarshalJSON reads the form MarshalJSON writes. Amounts may also be strings in any
+// form ParseRational accepts, so "1/3" is read exactly. The uom only holds the id and
+// label from the JSON: look it up in a catalog before converting the quantity.
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	var j struct {
+		Amount      *Rational `json:"amount"`
+		Min         *Rational `json:"min"`
+		Max         *Rational `json:"max"`
+		Approximate bool      `json:"approximate"`
+		Qualitative string    `json:"qualitative"`
+		UomID       string    `json:"uom_id"`
+		Uom         string    `json:"uom"`
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	var uom *Uom
+	if j.UomID != "" || j.Uom != "" {
+		uom = &Uom{Id: j.UomID, BaseUom: BaseUom{Label: j.Uom}}
+	}
+	switch {
+	case j.Qualitative != "":
+		if j.Amount != nil || j.Min != nil || j.Max != nil {
+			return fmt.Errorf("qualitative quantity %q cannot have an amount", j.Qualitative)
+		}
+		*q = QualitativeQuantity(j.Qualitative)
+		q.Uom = uom
+	case j.Amount != nil:
+		if j.Min != nil || j.Max != nil {
+			return fmt.Errorf("quantity has both an amount and a range")
+		}
+		*q = NewQuantity(*j.Amount, uom)
+	case j.Min != nil && j.Max != nil:
+		*q = NewQuantityRange(*j.Min, *j.Max, uom)
+	default:
+		return fmt.Errorf("quantity needs an amount, min and max, or qualitative")
+	}
+	q.Approximate = j.Approximate
+	return nil
+}
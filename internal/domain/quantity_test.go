@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestHumanizeScaledQuantity(t *testing.T) {
+	catalog := testCatalog(t)
+	tests := []struct {
+		lo, hi string
+		uom    string
+		factor string
+		want   string
+	}{
+		{"2", "3", "tablespoon", "4", "½–¾ cup"},
+		{"16", "16", "tablespoon", "1", "1 cup"},
+		{"1", "1", "cup", "1/16", "1 tbsp"},
+		{"1", "1", "teaspoon", "3", "1 tbsp"},
+		{"1/2", "1/2", "cup", "3", "1 ½ cups"},
+		{"1", "2", "teaspoon", "1/2", "½–1 tsp"},
+	}
+	for _, tt := range tests {
+		q := NewQuantityRange(rat(t, tt.lo), rat(t, tt.hi), testUom(t, tt.uom))
+		got, err := HumanizeQuantity(q.Scale(rat(t, tt.factor)), catalog.All(), "")
+		if err != nil {
+			t.Errorf("HumanizeQuantity(%s–%s %s x%s) failed: %v", tt.lo, tt.hi, tt.uom, tt.factor, err)
+			continue
+		}
+		if text := FormatQuantity(got, FormatOptions{}); text != tt.want {
+			t.Errorf("%s–%s %s x%s = %q, want %q", tt.lo, tt.hi, tt.uom, tt.factor, text, tt.want)
+		}
+	}
+}
+
+func TestScaleKeepsQualitativeQuantities(t *testing.T) {
+	q := QualitativeQuantity("to taste")
+	if got := q.Scale(IntRational(4)); got != q {
+		t.Errorf("Scale changed a qualitative quantity: %+v", got)
+	}
+}
+
+func TestQuantityJSON(t *testing.T) {
+	cup, pound := testUom(t, "cup"), testUom(t, "pound")
+	approx := NewQuantity(IntRational(1), pound)
+	approx.Approximate = true
+	tests := []struct {
+		q    Quantity
+		json string
+	}{
+		{NewQuantity(NewRational(1, 2), cup), fmt.Sprintf(`{"amount":0.5,"uom_id":%q,"uom":"cup"}`, cup.Id)},
+		{NewQuantityRange(IntRational(2), IntRational(3), cup), fmt.Sprintf(`{"min":2,"max":3,"uom_id":%q,"uom":"cup"}`, cup.Id)},
+		{approx, fmt.Sprintf(`{"amount":1,"approximate":true,"uom_id":%q,"uom":"pound"}`, pound.Id)},
+		{NewQuantity(IntRational(2), nil), `{"amount":2}`},
+		{QualitativeQuantity("to taste"), `{"qualitative":"to taste"}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.q)
+		if err != nil || string(data) != tt.json {
+			t.Errorf("Marshal(%s) = %s, %v; want %s", FormatQuantity(tt.q, FormatOptions{}), data, err, tt.json)
+			continue
+		}
+
+		var got Quantity
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("Unmarshal(%s) failed: %v", data, err)
+			continue
+		}
+		if got.Min.Cmp(tt.q.Min) != 0 || got.Max.Cmp(tt.q.Max) != 0 || got.Approximate != tt.q.Approximate || got.Qualitative != tt.q.Qualitative {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", data, got, tt.q)
+		}
+		if (got.Uom == nil) != (tt.q.Uom == nil) || got.Uom != nil && (got.Uom.Id != tt.q.Uom.Id || got.Uom.Label != tt.q.Uom.Label) {
+			t.Errorf("Unmarshal(%s) has uom %v, want %v", data, got.Uom, tt.q.Uom)
+		}
+	}
+}
+
+func TestQuantityUnmarshalJSON(t *testing.T) {
+	var q Quantity
+	if err := json.Unmarshal([]byte(`{"min":"1/3","max":"1 ⅓","uom_id":"cup"}`), &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.Min.String() != "1/3" || q.Max.String() != "4/3" || q.Uom == nil || q.Uom.Id != "cup" {
+		t.Errorf("Unmarshal exact range = %s–%s %v, want 1/3–4/3 cup", q.Min, q.Max, q.Uom)
+	}
+
+	for _, data := range []string{
+		`{}`,
+		`{"uom_id":"cup"}`,
+		`{"min":1,"uom_id":"cup"}`,
+		`{"amount":1,"min":1,"max":2}`,
+		`{"amount":1,"qualitative":"to taste"}`,
+		`{"amount":"cup"}`,
+	} {
+		if err := json.Unmarshal([]byte(data), &q); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want an error", data)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Rational is an exact fraction used for amounts and uom ratios, so 1/3 cup converted
+// to tsp and back is still exactly 1/3 cup. The zero value is 0. Rationals are
+// immutable: every operation returns a new value.
+type Rational struct {
+	r *big.Rat // nil is zero
+}
+
+// NewRational returns num/den. It panics if den is zero.
+func NewRational(num, den int64) Rational {
+	if den == 0 {
+		panic("domain: rational with zero denominator")
+	}
+	return Rational{r: big.NewRat(num, den)}
+}
+
+// IntRational returns n as a rational
+func IntRational(n int64) Rational {
+	return Rational{r: new(big.Rat).SetInt64(n)}
+}
+
+// FloatRational returns the decimal the float prints as, so 0.1 is exactly 1/10
+// rather than its binary approximation. NaN and infinities are zero.
+func FloatRational(f float64) Rational {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Rational{}
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok {
+		return Rational{}
+	}
+	return Rational{r: r}
+}
+
+// ParseRational parses an amount as written in a recipe or stored in the repository:
+// "2", "0.333", "1/3", "4/3", "1 1/3", "⅓" or "1 ⅓". The whole string must be the amount.
+func ParseRational(s string) (Rational, error) {
+	// big.Rat handles plain numbers and simple fractions, including signs and exponents
+	if r, ok := new(big.Rat).SetString(strings.TrimSpace(s)); ok {
+		return Rational{r: r}, nil
+	}
+	v, n, ok := ParseAmount(s)
+	if !ok || strings.TrimSpace(s[n:]) != "" {
+		return Rational{}, fmt.Errorf("invalid amount %q", s)
+	}
+	return v, nil
+}
+
+func (a Rational) rat() *big.Rat {
+	if a.r == nil {
+		return new(big.Rat)
+	}
+	return a.r
+}
+
+// Rat returns a copy of the value as a big.Rat
+func (a Rational) Rat() *big.Rat {
+	return new(big.Rat).Set(a.rat())
+}
+
+func (a Rational) Add(b Rational) Rational {
+	return Rational{r: new(big.Rat).Add(a.rat(), b.rat())}
+}
+
+func (a Rational) Sub(b Rational) Rational {
+	return Rational{r: new(big.Rat).Sub(a.rat(), b.rat())}
+}
+
+func (a Rational) Mul(b Rational) Rational {
+	return Rational{r: new(big.Rat).Mul(a.rat(), b.rat())}
+}
+
+// Quo returns a/b. It panics if b is zero.
+func (a Rational) Quo(b Rational) Rational {
+	return Rational{r: new(big.Rat).Quo(a.rat(), b.rat())}
+}
+
+func (a Rational) Neg() Rational {
+	return Rational{r: new(big.Rat).Neg(a.rat())}
+}
+
+func (a Rational) Abs() Rational {
+	return Rational{r: new(big.Rat).Abs(a.rat())}
+}
+
+// Cmp returns -1, 0 or +1 depending on whether a is less than, equal to or greater than b
+func (a Rational) Cmp(b Rational) int {
+	return a.rat().Cmp(b.rat())
+}
+
+func (a Rational) Sign() int {
+	return a.rat().Sign()
+}
+
+func (a Rational) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// Float64 returns the nearest float64, for display and for callers that don't need exact values
+func (a Rational) Float64() float64 {
+	f, _ := a.rat().Float64()
+	return f
+}
+
+// String returns the fraction in lowest terms, e.g. "1/3", "4/3" or "2"
+func (a Rational) String() string {
+	return a.rat().RatString()
+}
+
+// Round returns the multiple of step nearest to a, rounding halves away from zero.
+// It panics if step is zero.
+func (a Rational) Round(step Rational) Rational {
+	q := new(big.Rat).Quo(a.rat(), step.rat())
+	num, den := new(big.Int).Abs(q.Num()), q.Denom()
+
+	// floor(|q| + 1/2) = (2*num + den) / (2*den)
+	n := new(big.Int).Add(new(big.Int).Lsh(num, 1), den)
+	n.Quo(n, new(big.Int).Lsh(den, 1))
+	if q.Sign() < 0 {
+		n.Neg(n)
+	}
+	return Rational{r: new(big.Rat).Mul(new(big.Rat).SetInt(n), step.rat())}
+}
+
+// MarshalJSON writes the same rounded decimal string as PreciseFloat32, so the JSON
+// form of a uom doesn't change. Exact values are kept by the repository, not the API.
+func (a Rational) MarshalJSON() ([]byte, error) {
+	s := a.rat().FloatString(6)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimRight(s, ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON reads a JSON number or a string in any form ParseRational accepts
+func (a *Rational) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n json.Number
+		if err2 := json.Unmarshal(data, &n); err2 != nil {
+			return fmt.Errorf("cannot unmarshal %s into Rational", string(data))
+		}
+		s = n.String()
+	}
+	v, err := ParseRational(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConvertRoundTripsExactly(t *testing.T) {
+	tests := []struct {
+		amount   string
+		from, to string
+		via      string // the exact amount in the to uom
+	}{
+		{"1/3", "cup", "teaspoon", "16"},
+		{"1/3", "cup", "tablespoon", "16/3"},
+		{"1/3", "cup", "milliliter", "157725491/2000000"},
+		{"2/3", "teaspoon", "cup", "1/72"},
+		{"1/7", "pound", "gram", "6479891/100000"},
+	}
+	for _, tt := range tests {
+		from, to := testUom(t, tt.from), testUom(t, tt.to)
+		via, err := Convert(rat(t, tt.amount), from, to)
+		if err != nil {
+			t.Errorf("Convert(%s %s, %s) failed: %v", tt.amount, tt.from, tt.to, err)
+			continue
+		}
+		back, err := Convert(via, to, from)
+		if err != nil {
+			t.Errorf("Convert(%s %s, %s) failed: %v", via, tt.to, tt.from, err)
+			continue
+		}
+		if via.String() != tt.via || back.Cmp(rat(t, tt.amount)) != 0 {
+			t.Errorf("%s %s -> %s %s -> %s %s, want %s %s and back exactly", tt.amount, tt.from, via, tt.to, back, tt.from, tt.via, tt.to)
+		}
+	}
+}
+
+func TestParseRational(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"2", "2"},
+		{"0.333", "333/1000"},
+		{"1/3", "1/3"},
+		{"4/3", "4/3"},
+		{"-1/2", "-1/2"},
+		{"1e-3", "1/1000"},
+		{"1 1/3", "4/3"},
+		{"⅓", "1/3"},
+		{"1 ⅓", "4/3"},
+		{" 1 ⅓ ", "4/3"},
+	}
+	for _, tt := range tests {
+		got, err := ParseRational(tt.text)
+		if err != nil || got.String() != tt.want {
+			t.Errorf("ParseRational(%q) = %s, %v; want %s", tt.text, got, err, tt.want)
+		}
+	}
+	for _, text := range []string{"", "cup", "1/0", "1 cup", "1 1/3 cups"} {
+		if got, err := ParseRational(text); err == nil {
+			t.Errorf("ParseRational(%q) = %s, want an error", text, got)
+		}
+	}
+}
+
+func TestRationalJSON(t *testing.T) {
+	tests := []struct {
+		value string
+		json  string
+	}{
+		{"1/3", `"0.333333"`},
+		{"2/3", `"0.666667"`},
+		{"1/2", `"0.5"`},
+		{"2", `"2"`},
+		{"0", `"0"`},
+		{"-1/3000000", `"0"`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(rat(t, tt.value))
+		if err != nil || string(data) != tt.json {
+			t.Errorf("Marshal(%s) = %s, %v; want %s", tt.value, data, err, tt.json)
+		}
+	}
+
+	for _, data := range []string{`0.5`, `"0.5"`, `"1/2"`, `"½"`, `5e-1`} {
+		var got Rational
+		if err := json.Unmarshal([]byte(data), &got); err != nil || got.String() != "1/2" {
+			t.Errorf("Unmarshal(%s) = %s, %v; want 1/2", data, got, err)
+		}
+	}
+	var got Rational
+	if err := json.Unmarshal([]byte(`true`), &got); err == nil {
+		t.Errorf("Unmarshal(true) succeeded, want an error")
+	}
+}
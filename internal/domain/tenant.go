@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxTenantLength bounds tenant ids, which are used verbatim as Firestore document ids
+const maxTenantLength = 64
+
+type tenantKey struct{}
+
+// WithTenant scopes ctx to a tenant (a household or account). Repositories keep each
+// tenant's uoms apart from the global catalog; the empty tenant is the global catalog.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant ctx is scoped to, or "" for the global catalog
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// ValidateTenant checks that a tenant id is 1 to 64 ASCII letters, digits, '-' or '_'
+func ValidateTenant(tenant string) error {
+	if tenant == "" {
+		return fmt.Errorf("tenant is required")
+	}
+	if len(tenant) > maxTenantLength {
+		return fmt.Errorf("tenant must be at most %d characters", maxTenantLength)
+	}
+	for _, r := range tenant {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return fmt.Errorf("tenant %q may only contain letters, digits, '-' and '_'", tenant)
+		}
+	}
+	return nil
+}
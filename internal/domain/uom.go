@@ -8,7 +8,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/google/uuid"
 	"github.com/gookit/validate"
 )
 
@@ -31,8 +30,8 @@ import (
 //	You might have overlapping group ranges and then do this negotiation.
 //
 // TODO: Just change the names in the csv to match this, remove differentiation field and pivot field
-// TODO: Match names must be greater than 0 length strings
-// TODO: Set validation to ensure no conflicting match names
+//
+// Deprecated: uom amounts and ratios are Rational, which holds fractions like 1/3 exactly.
 type PreciseFloat32 float32
 
 func (f PreciseFloat32) MarshalJSON() ([]byte, error) {
@@ -66,17 +65,21 @@ func (f PreciseFloat32) Float32() float32 {
 	return float32(f)
 }
 
-// TODO: Set validation to ensure unique Label
+// Label and match names are unique across the catalog; that is enforced by the repository
 type BaseUom struct {
 	Label string `json:"label" validate:"required"`
 
-	Enabled     bool             `json:"enabled" validate:"required"` // does not exist in csv
-	MeasureType UomMeasureType   `json:"measure_type" validate:"required"`
-	Group       *string          `json:"group,omitempty" validate:"-"` // Even when filling this in, it should be a reference because there will be one group instance per group
-	GroupMin    *PreciseFloat32  `json:"group_min,omitempty" validate:"-"`
-	GroupMax    *PreciseFloat32  `json:"group_max,omitempty" validate:"-"`
-	SnapAmount  []PreciseFloat32 `json:"snap_amount" validate:"required"`    // This is to ensure it doesn't do values in between these. (e.g. [0.25, 0.001], [1], etc)
-	SnapSelect  *PreciseFloat32  `json:"snap_select,omitempty" validate:"-"` // The snap to total ratio must be at least this much to use the snap, otherwise it checks the next highest snap. (e.g. 0.1, etc)
+	Enabled     bool           `json:"enabled" validate:"required"` // does not exist in csv
+	MeasureType UomMeasureType `json:"measure_type" validate:"required"`
+	Group       *string        `json:"group,omitempty" validate:"-"` // Even when filling this in, it should be a reference because there will be one group instance per group
+	GroupMin    *Rational      `json:"group_min,omitempty" validate:"-"`
+	GroupMax    *Rational      `json:"group_max,omitempty" validate:"-"`
+	SnapAmount  []Rational     `json:"snap_amount" validate:"required"`    // This is to ensure it doesn't do values in between these. (e.g. [0.25, 0.001], [1], etc)
+	SnapSelect  *Rational      `json:"snap_select,omitempty" validate:"-"` // The snap to total ratio must be at least this much to use the snap, otherwise it checks the next highest snap. (e.g. 0.1, etc)
+	PivotRatio  *Rational      `json:"pivot_ratio,omitempty" validate:"-"` // How many of the measure type's pivot unit (ml, g) are in one of this uom. Uoms without it can't be converted
+
+	PackageAmount *Rational `json:"package_amount,omitempty" validate:"-"` // For packages and items: how much of PackageUom one of this uom holds (e.g. a stick is 8 tbsp)
+	PackageUom    *string   `json:"package_uom,omitempty" validate:"-"`    // Id or label of the uom PackageAmount is expressed in
 
 	MatchNamesRecipe    []string `json:"match_names_recipe" validate:"-"`     // was "recipe_match_names"
 	MatchNamesFoodLabel []string `json:"match_names_food_label" validate:"-"` // was "food_label_match_names"
@@ -87,7 +90,12 @@ type BaseUom struct {
 	PrintedNameFullSingular  *string            `json:"full_name_singular,omitempty" validate:"-"`
 	PrintedNameFullPlural    *string            `json:"full_name_plural,omitempty" validate:"-"`
 
+	PrintedNameDisambiguatedSingular *string `json:"disambiguated_name_singular,omitempty" validate:"-"` // Used instead of the printed name when another uom of the NameGroup is printed alongside (e.g. "fl oz")
+	PrintedNameDisambiguatedPlural   *string `json:"disambiguated_name_plural,omitempty" validate:"-"`
+
 	AdditionalInfo *UomAdditionalInfo `json:"info,omitempty" validate:"-"`
+
+	Locales map[string]UomLocale `json:"locales,omitempty" validate:"-"` // Printed and match names in other languages, keyed by BCP 47 tag (e.g. "es", "fr-CA")
 }
 
 type Uom struct {
@@ -96,8 +104,8 @@ type Uom struct {
 }
 
 type UomAdditionalInfo struct {
-	Systems   []string `json:"systems" validate:"-"`
-	NameGroup *string  `json:"name_group,omitempty" validate:"-"`
+	Systems   []UomSystem `json:"systems" validate:"-"`
+	NameGroup *string     `json:"name_group,omitempty" validate:"-"`
 }
 
 type UomMeasureType = string
@@ -121,7 +129,7 @@ type UomOption func(*BaseUom)
 func NewUom(
 	label string,
 	measureType UomMeasureType,
-	snapAmount []PreciseFloat32,
+	snapAmount []Rational,
 	printedNameDefaultType UomPrintedNameType,
 	opts ...UomOption,
 ) (*BaseUom, error) {
@@ -139,24 +147,27 @@ func NewUom(
 	for _, opt := range opts {
 		opt(uom)
 	}
-	
+
 	if err := uom.Validate(); err != nil {
 		return nil, err
 	}
 	return uom, nil
 }
 
-func Create(base *BaseUom) (*Uom, error) {
+func Create(base *BaseUom, newID UomIDGenerator) (*Uom, error) {
 	if err := base.Validate(); err != nil {
 		return nil, err
 	}
-	id, err := uuid.NewUUID()
+	if newID == nil {
+		newID = UUIDv1IDGenerator
+	}
+	id, err := newID(base)
 	if err != nil {
 		return nil, err
 	}
 	uom := &Uom{
 		BaseUom: *base,
-		Id:      id.String(),
+		Id:      id,
 	}
 	if err := uom.Validate(); err != nil {
 		return nil, err
@@ -164,7 +175,7 @@ func Create(base *BaseUom) (*Uom, error) {
 	return uom, nil
 }
 
-func WithGroup(group string, groupMin, groupMax *PreciseFloat32) UomOption {
+func WithGroup(group string, groupMin, groupMax *Rational) UomOption {
 	return func(u *BaseUom) {
 		u.Group = &group
 		u.GroupMin = groupMin
@@ -172,7 +183,7 @@ func WithGroup(group string, groupMin, groupMax *PreciseFloat32) UomOption {
 	}
 }
 
-func WithSnapSelect(snapSelect PreciseFloat32) UomOption {
+func WithSnapSelect(snapSelect Rational) UomOption {
 	return func(u *BaseUom) {
 		u.SnapSelect = &snapSelect
 	}
@@ -203,12 +214,41 @@ func WithPrintedNames(shortSingular, shortPlural, fullSingular, fullPlural *stri
 	}
 }
 
+func WithDisambiguatedNames(singular, plural *string) UomOption {
+	return func(u *BaseUom) {
+		u.PrintedNameDisambiguatedSingular = singular
+		u.PrintedNameDisambiguatedPlural = plural
+	}
+}
+
 func WithAdditionalInfo(info *UomAdditionalInfo) UomOption {
 	return func(u *BaseUom) {
 		u.AdditionalInfo = info
 	}
 }
 
+func WithPivotRatio(ratio Rational) UomOption {
+	return func(u *BaseUom) {
+		u.PivotRatio = &ratio
+	}
+}
+
+func WithLocale(tag string, locale UomLocale) UomOption {
+	return func(u *BaseUom) {
+		if u.Locales == nil {
+			u.Locales = make(map[string]UomLocale)
+		}
+		u.Locales[tag] = locale
+	}
+}
+
+func WithPackageSize(amount Rational, uom string) UomOption {
+	return func(u *BaseUom) {
+		u.PackageAmount = &amount
+		u.PackageUom = &uom
+	}
+}
+
 func WithEnabled(enabled bool) UomOption {
 	return func(u *BaseUom) {
 		u.Enabled = enabled
@@ -220,7 +260,7 @@ func (u *BaseUom) Validate() error {
 	if !v.Validate() {
 		return v.Errors
 	}
-	return nil
+	return u.validateRules()
 }
 
 func (u *Uom) Validate() error {
@@ -228,6 +268,57 @@ func (u *Uom) Validate() error {
 	if !v.Validate() {
 		return v.Errors
 	}
+	return u.validateRules()
+}
+
+// validateRules runs the checks the struct tags can't express
+func (u *BaseUom) validateRules() error {
+	if err := u.validateNames(); err != nil {
+		return err
+	}
+	if err := u.validateSystems(); err != nil {
+		return err
+	}
+	if (u.PackageAmount == nil) != (u.PackageUom == nil || *u.PackageUom == "") {
+		return fmt.Errorf("package_amount and package_uom must be set together")
+	}
+	// Conversions divide by these
+	if u.PivotRatio != nil && u.PivotRatio.Sign() <= 0 {
+		return fmt.Errorf("pivot_ratio must be positive")
+	}
+	if u.PackageAmount != nil && u.PackageAmount.Sign() <= 0 {
+		return fmt.Errorf("package_amount must be positive")
+	}
+	return u.validateLocales()
+}
+
+// validateNames checks that the label can be slugged and that match names are
+// non-empty and not repeated within a list
+func (u *BaseUom) validateNames() error {
+	if Slug(u.Label) == "" {
+		return fmt.Errorf("label: must contain at least one letter or digit")
+	}
+	lists := []struct {
+		field string
+		names []string
+	}{
+		{"match_names_recipe", u.MatchNamesRecipe},
+		{"match_names_food_label", u.MatchNamesFoodLabel},
+	}
+	for _, list := range lists {
+		field := list.field
+		seen := make(map[string]bool, len(list.names))
+		for _, name := range list.names {
+			slug := Slug(name)
+			if slug == "" {
+				return fmt.Errorf("%s: match names must not be empty", field)
+			}
+			if seen[slug] {
+				return fmt.Errorf("%s: duplicate match name %q", field, name)
+			}
+			seen[slug] = true
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+)
+
+// UomCatalog is an immutable snapshot of all uoms, indexed for lookups.
+// Callers must not modify the uoms it returns.
+type UomCatalog struct {
+	version uint64
+	uoms    []*Uom
+	ranked  []*Uom // uoms in precedence order: when two share a name, the first one keeps it
+
+	byID                 map[string]*Uom
+	byLabel              map[string]*Uom   // keyed by Slug(label)
+	byGroup              map[string][]*Uom // keyed by group name
+	byRecipeMatchName    map[string]*Uom   // keyed by Slug(match name)
+	byFoodLabelMatchName map[string]*Uom   // keyed by Slug(match name)
+
+	matchLocales       map[string]bool // locales some uom has recipe match names in
+	recipeMatchers     sync.Map        // key of the locales with match names -> *UomMatcher
+	recipeMatcherCount atomic.Int32
+	foodLabelMatcher   func() *UomMatcher
+
+	merged atomic.Pointer[mergedUomCatalog] // the last merge of this tenant catalog over a global one
+}
+
+type mergedUomCatalog struct {
+	global  *UomCatalog
+	catalog *UomCatalog
+}
+
+// UomCatalogSource is implemented by repositories that can hand out a prebuilt catalog
+type UomCatalogSource interface {
+	Catalog(ctx context.Context) (*UomCatalog, error)
+}
+
+// LoadUomCatalog returns the catalog the tenant in ctx sees: the global catalog with the
+// tenant's own uoms merged over it, or just the global catalog without a tenant
+func LoadUomCatalog(ctx context.Context, repo UomRepository) (*UomCatalog, error) {
+	tenant := TenantFromContext(ctx)
+	global, err := loadUomCatalog(WithTenant(ctx, ""), repo)
+	if err != nil || tenant == "" {
+		return global, err
+	}
+	own, err := loadUomCatalog(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	return MergeUomCatalogs(global, own), nil
+}
+
+// loadUomCatalog returns the repository's own catalog if it keeps one, otherwise builds one from GetAll
+func loadUomCatalog(ctx context.Context, repo UomRepository) (*UomCatalog, error) {
+	if source, ok := repo.(UomCatalogSource); ok {
+		return source.Catalog(ctx)
+	}
+	uoms, err := repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewUomCatalog(uoms, 0), nil
+}
+
+// NewUomCatalog indexes the uoms. The version identifies the snapshot so derived
+// structures can be rebuilt only when the catalog changes.
+func NewUomCatalog(uoms []*Uom, version uint64) *UomCatalog {
+	return newUomCatalog(sortedByID(uoms), version)
+}
+
+// MergeUomCatalogs overlays a tenant's uoms on the global catalog. A tenant uom hides the
+// global uom with the same id or label and wins any match name the two share, so a
+// tenant can redefine "cup" or add "mug" without touching the global catalog. The
+// merge is remembered on the tenant catalog, so it is only rebuilt when either changes.
+func MergeUomCatalogs(global, tenant *UomCatalog) *UomCatalog {
+	if m := tenant.merged.Load(); m != nil && m.global == global {
+		return m.catalog
+	}
+
+	ranked := make([]*Uom, 0, len(tenant.uoms)+len(global.uoms))
+	ranked = append(ranked, tenant.uoms...)
+	for _, uom := range global.uoms {
+		if tenant.ByID(uom.Id) == nil && tenant.ByLabel(uom.Label) == nil {
+			ranked = append(ranked, uom)
+		}
+	}
+	// Both versions only grow, so their sum changes whenever either catalog does
+	c := newUomCatalog(ranked, global.version+tenant.version)
+	tenant.merged.Store(&mergedUomCatalog{global: global, catalog: c})
+	return c
+}
+
+func newUomCatalog(ranked []*Uom, version uint64) *UomCatalog {
+	c := &UomCatalog{
+		version:              version,
+		uoms:                 sortedByID(ranked),
+		ranked:               ranked,
+		byID:                 make(map[string]*Uom, len(ranked)),
+		byLabel:              make(map[string]*Uom, len(ranked)),
+		byGroup:              make(map[string][]*Uom),
+		byRecipeMatchName:    make(map[string]*Uom),
+		byFoodLabelMatchName: make(map[string]*Uom),
+		matchLocales:         make(map[string]bool),
+	}
+	claim := func(index map[string]*Uom, key string, uom *Uom) {
+		if _, taken := index[key]; !taken {
+			index[key] = uom
+		}
+	}
+	for _, uom := range ranked {
+		claim(c.byID, uom.Id, uom)
+		claim(c.byLabel, Slug(uom.Label), uom)
+		for _, name := range uom.MatchNamesRecipe {
+			claim(c.byRecipeMatchName, Slug(name), uom)
+		}
+		for _, name := range uom.MatchNamesFoodLabel {
+			claim(c.byFoodLabelMatchName, Slug(name), uom)
+		}
+		for tag, loc := range uom.Locales {
+			if len(loc.MatchNamesRecipe) > 0 {
+				c.matchLocales[tag] = true
+			}
+		}
+	}
+	for _, uom := range c.uoms {
+		if uom.Group != nil {
+			c.byGroup[*uom.Group] = append(c.byGroup[*uom.Group], uom)
+		}
+	}
+	c.foodLabelMatcher = sync.OnceValue(func() *UomMatcher {
+		return NewUomMatcher(c.ranked, func(u *Uom) []string { return u.MatchNamesFoodLabel })
+	})
+	return c
+}
+
+func sortedByID(uoms []*Uom) []*Uom {
+	sorted := make([]*Uom, len(uoms))
+	copy(sorted, uoms)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Id < sorted[j].Id })
+	return sorted
+}
+
+func (c *UomCatalog) Version() uint64 {
+	return c.version
+}
+
+func (c *UomCatalog) Len() int {
+	return len(c.uoms)
+}
+
+// All returns every uom ordered by id
+func (c *UomCatalog) All() []*Uom {
+	return c.uoms
+}
+
+// ByID returns the uom with the id, or nil
+func (c *UomCatalog) ByID(id string) *Uom {
+	return c.byID[id]
+}
+
+// ByLabel returns the uom whose label slugs the same as label, or nil
+func (c *UomCatalog) ByLabel(label string) *Uom {
+	return c.byLabel[Slug(label)]
+}
+
+// ByGroup returns the uoms in the group ordered by id
+func (c *UomCatalog) ByGroup(group string) []*Uom {
+	return c.byGroup[group]
+}
+
+// ByRecipeMatchName returns the uom that lists name in MatchNamesRecipe, or nil
+func (c *UomCatalog) ByRecipeMatchName(name string) *Uom {
+	return c.byRecipeMatchName[Slug(name)]
+}
+
+// ByFoodLabelMatchName returns the uom that lists name in MatchNamesFoodLabel, or nil
+func (c *UomCatalog) ByFoodLabelMatchName(name string) *Uom {
+	return c.byFoodLabelMatchName[Slug(name)]
+}
+
+// Resolve finds a uom by id, label or recipe match name, in that order, or returns nil
+func (c *UomCatalog) Resolve(ref string) *Uom {
+	if uom := c.ByID(ref); uom != nil {
+		return uom
+	}
+	if uom := c.ByLabel(ref); uom != nil {
+		return uom
+	}
+	return c.ByRecipeMatchName(ref)
+}
+
+// maxRecipeMatchers bounds how many matchers a catalog keeps for different locale sets
+const maxRecipeMatchers = 32
+
+// RecipeMatcher returns the matcher over the recipe match names of the preferred
+// locales and the default locale, building it on first use for that set of locales.
+//
+// Preferred locales come from request headers, so only the locales the catalog has
+// match names in are kept, and in a fixed order: uoms claim names in catalog order
+// whatever the locale order, so neither the others nor the order change the matcher.
+// Past maxRecipeMatchers distinct sets, matchers are built per call and not kept.
+func (c *UomCatalog) RecipeMatcher(locales []language.Tag) *UomMatcher {
+	var tags []language.Tag
+	var keys []string
+	for _, tag := range LocaleChain(locales) {
+		if key := tag.String(); c.matchLocales[key] {
+			tags = append(tags, tag)
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	key := strings.Join(keys, ",")
+
+	if m, ok := c.recipeMatchers.Load(key); ok {
+		return m.(*UomMatcher)
+	}
+	m := NewUomMatcher(c.ranked, func(u *Uom) []string { return u.LocalizedMatchNamesRecipe(tags) })
+	if c.recipeMatcherCount.Add(1) > maxRecipeMatchers {
+		return m
+	}
+	actual, _ := c.recipeMatchers.LoadOrStore(key, m)
+	return actual.(*UomMatcher)
+}
+
+// FoodLabelMatcher returns the matcher over food label match names, building it on first use
+func (c *UomCatalog) FoodLabelMatcher() *UomMatcher {
+	return c.foodLabelMatcher()
+}
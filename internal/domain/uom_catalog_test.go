@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestRecipeMatcherSharedAcrossLocaleSets(t *testing.T) {
+	catalog := NewUomCatalog(testCatalog(t).All(), 1)
+	es := catalog.RecipeMatcher([]language.Tag{language.Spanish})
+
+	// Locales without match names and the order of the rest don't change the matcher
+	for _, locales := range [][]language.Tag{
+		{language.MustParse("es-MX")},
+		{language.German, language.Spanish},
+		{language.Spanish, language.French, language.Japanese},
+	} {
+		if m := catalog.RecipeMatcher(locales); m != es {
+			t.Errorf("RecipeMatcher(%v) built a new matcher, want the shared Spanish one", locales)
+		}
+	}
+	if m, ok := es.MatchPrefix("cucharadas"); !ok || m.Uom.Label != "tablespoon" {
+		t.Errorf("Spanish matcher doesn't find cucharadas")
+	}
+	if m := catalog.RecipeMatcher(nil); m == es {
+		t.Errorf("RecipeMatcher(nil) returned the Spanish matcher")
+	} else if _, ok := m.MatchPrefix("cucharadas"); ok {
+		t.Errorf("default matcher finds Spanish names")
+	}
+}
+
+func TestRecipeMatcherCacheIsBounded(t *testing.T) {
+	uoms := make([]*Uom, 0, maxRecipeMatchers+10)
+	for i := range maxRecipeMatchers + 10 {
+		tag := fmt.Sprintf("x-l%d", i)
+		uoms = append(uoms, &Uom{Id: tag, BaseUom: BaseUom{Label: tag, Enabled: true, Locales: map[string]UomLocale{
+			tag: {MatchNamesRecipe: []string{tag + "name"}},
+		}}})
+	}
+	catalog := NewUomCatalog(uoms, 1)
+	for _, uom := range uoms {
+		for tag := range uom.Locales {
+			catalog.RecipeMatcher([]language.Tag{language.Make(tag)})
+		}
+	}
+
+	kept := 0
+	catalog.recipeMatchers.Range(func(_, _ any) bool {
+		kept++
+		return true
+	})
+	if kept != maxRecipeMatchers {
+		t.Errorf("catalog keeps %d matchers, want %d", kept, maxRecipeMatchers)
+	}
+}
@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UomAmount is an amount expressed in a uom. A nil Uom is a plain count ("2 eggs").
+type UomAmount struct {
+	Amount Rational
+	Uom    *Uom
+}
+
+// Convert expresses amount of from in to. Both uoms need the same measure type and a
+// pivot ratio. The result is exact, so converting there and back returns the amount.
+func Convert(amount Rational, from, to *Uom) (Rational, error) {
+	if from.Id == to.Id {
+		return amount, nil
+	}
+	if from.MeasureType != to.MeasureType {
+		return Rational{}, fmt.Errorf("cannot convert %s (%s) to %s (%s): measure types differ", from.Label, from.MeasureType, to.Label, to.MeasureType)
+	}
+	if from.PivotRatio == nil || to.PivotRatio == nil || to.PivotRatio.IsZero() {
+		return Rational{}, fmt.Errorf("cannot convert %s to %s: missing pivot ratio", from.Label, to.Label)
+	}
+	return amount.Mul(*from.PivotRatio).Quo(*to.PivotRatio), nil
+}
+
+// Humanize picks the uom a person would use for the amount among the candidates and
+// snaps the amount to that uom's snap rules. Candidates are restricted to enabled,
+// convertible uoms of the same measure type in the target system; with no system,
+// the candidates are the uoms in the same group as from, or from itself.
+//
+// The chosen uom is the largest one whose [GroupMin, GroupMax) range contains the
+// converted amount. If none does, it is the largest uom the amount is at least one
+// of, falling back to the smallest uom.
+func Humanize(amount Rational, from *Uom, candidates []*Uom, system UomSystem) (UomAmount, error) {
+	eligible := humanizeCandidates(from, candidates, system)
+	if len(eligible) == 0 {
+		if system != "" {
+			return UomAmount{}, fmt.Errorf("cannot convert %s: no %s uom with measure type %s", from.Label, system, from.MeasureType)
+		}
+		return UomAmount{Amount: Snap(amount, from), Uom: from}, nil
+	}
+
+	// Largest first
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return eligible[i].PivotRatio.Cmp(*eligible[j].PivotRatio) > 0
+	})
+
+	one := IntRational(1)
+	var best *Uom
+	var bestAmount Rational
+	for _, uom := range eligible {
+		converted, err := Convert(amount, from, uom)
+		if err != nil {
+			return UomAmount{}, err
+		}
+		if uom.inGroupRange(converted) {
+			best, bestAmount = uom, converted
+			break
+		}
+		if best == nil && converted.Cmp(one) >= 0 {
+			best, bestAmount = uom, converted
+		}
+	}
+	if best == nil {
+		best = eligible[len(eligible)-1]
+		converted, err := Convert(amount, from, best)
+		if err != nil {
+			return UomAmount{}, err
+		}
+		bestAmount = converted
+	}
+
+	return UomAmount{Amount: Snap(bestAmount, best), Uom: best}, nil
+}
+
+// Snap rounds the amount to one of the uom's snap increments. The finest increment
+// that is at least SnapSelect of the amount is used; without SnapSelect the finest
+// increment is always used. A non-zero amount never snaps to zero.
+func Snap(amount Rational, uom *Uom) Rational {
+	if len(uom.SnapAmount) == 0 || amount.IsZero() {
+		return amount
+	}
+
+	snaps := make([]Rational, 0, len(uom.SnapAmount))
+	for _, s := range uom.SnapAmount {
+		if s.Sign() > 0 {
+			snaps = append(snaps, s)
+		}
+	}
+	if len(snaps) == 0 {
+		return amount
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Cmp(snaps[j]) < 0 })
+
+	snap := snaps[len(snaps)-1]
+	if uom.SnapSelect == nil {
+		snap = snaps[0]
+	} else {
+		for _, s := range snaps {
+			if s.Quo(amount.Abs()).Cmp(*uom.SnapSelect) >= 0 {
+				snap = s
+				break
+			}
+		}
+	}
+
+	snapped := amount.Round(snap)
+	if snapped.IsZero() {
+		snapped = snap
+		if amount.Sign() < 0 {
+			snapped = snap.Neg()
+		}
+	}
+	return snapped
+}
+
+func humanizeCandidates(from *Uom, candidates []*Uom, system UomSystem) []*Uom {
+	var eligible []*Uom
+	for _, uom := range candidates {
+		if !uom.Enabled || uom.MeasureType != from.MeasureType || uom.PivotRatio == nil || uom.PivotRatio.Sign() <= 0 {
+			continue
+		}
+		if system != "" {
+			if !uom.InSystem(system) {
+				continue
+			}
+		} else if uom.Id != from.Id && (from.Group == nil || uom.Group == nil || *uom.Group != *from.Group) {
+			continue
+		}
+		eligible = append(eligible, uom)
+	}
+	return eligible
+}
+
+// inGroupRange reports whether amount falls within [GroupMin, GroupMax). A missing bound is open.
+func (u *BaseUom) inGroupRange(amount Rational) bool {
+	if u.GroupMin == nil && u.GroupMax == nil {
+		return false
+	}
+	if u.GroupMin != nil && amount.Cmp(*u.GroupMin) < 0 {
+		return false
+	}
+	if u.GroupMax != nil && amount.Cmp(*u.GroupMax) >= 0 {
+		return false
+	}
+	return true
+}
+
+// maxPackageDepth bounds how many package_uom references are followed, guarding against cycles
+const maxPackageDepth = 4
+
+// ExpandPackage re-expresses an amount of a package uom ("1 stick") in the uom its
+// package size is given in ("8 tbsp"), repeatedly, until it reaches a convertible uom.
+// Amounts that can't be expanded are returned unchanged.
+func (c *UomCatalog) ExpandPackage(a UomAmount) UomAmount {
+	for depth := 0; depth < maxPackageDepth && a.Uom != nil && a.Uom.PivotRatio == nil; depth++ {
+		if a.Uom.PackageAmount == nil || a.Uom.PackageUom == nil {
+			break
+		}
+		inner := c.Resolve(*a.Uom.PackageUom)
+		if inner == nil {
+			break
+		}
+		a = UomAmount{Amount: a.Amount.Mul(*a.Uom.PackageAmount), Uom: inner}
+	}
+	return a
+}
+
+// ToPivot returns the amount in the pivot unit of its measure type, or ok=false if the uom can't be converted
+func ToPivot(a UomAmount) (Rational, bool) {
+	if a.Uom == nil || a.Uom.PivotRatio == nil {
+		return Rational{}, false
+	}
+	return a.Amount.Mul(*a.Uom.PivotRatio), true
+}
+
+// HumanizePivot is Humanize for an amount already in the pivot unit of the measure
+// type. Without a system, the candidates are the uoms in the same group as like.
+func HumanizePivot(pivotAmount Rational, measureType UomMeasureType, like *Uom, candidates []*Uom, system UomSystem) (UomAmount, error) {
+	one := IntRational(1)
+	pivot := &Uom{
+		BaseUom: BaseUom{
+			Label:       "pivot " + measureType,
+			Enabled:     true,
+			MeasureType: measureType,
+			PivotRatio:  &one,
+		},
+	}
+	if like != nil {
+		pivot.Group = like.Group
+	}
+	result, err := Humanize(pivotAmount, pivot, candidates, system)
+	if err != nil {
+		return UomAmount{}, err
+	}
+	if result.Uom == pivot {
+		return UomAmount{}, fmt.Errorf("cannot convert %s: no uom to express it in", measureType)
+	}
+	return result, nil
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// vulgarFractions are the fractions printed with their unicode glyphs, in ascending order
+var vulgarFractions = []struct {
+	value float64
+	glyph string
+}{
+	{1.0 / 8, "⅛"},
+	{1.0 / 4, "¼"},
+	{1.0 / 3, "⅓"},
+	{3.0 / 8, "⅜"},
+	{1.0 / 2, "½"},
+	{5.0 / 8, "⅝"},
+	{2.0 / 3, "⅔"},
+	{3.0 / 4, "¾"},
+	{7.0 / 8, "⅞"},
+}
+
+// fractionTolerance is how close the fractional part must be to a vulgar fraction to print it as one
+const fractionTolerance = 0.01
+
+// UomDisambiguation controls when uoms that share a NameGroup are printed with their
+// unambiguous name variant ("fl oz" instead of "oz")
+type UomDisambiguation = string
+
+const (
+	// DISAMBIGUATE_AUTO uses the variant only when a list mixes uoms of the same NameGroup
+	DISAMBIGUATE_AUTO   UomDisambiguation = "auto"
+	DISAMBIGUATE_ALWAYS UomDisambiguation = "always"
+	DISAMBIGUATE_NEVER  UomDisambiguation = "never"
+)
+
+// ParseUomDisambiguation validates a disambiguation mode. The empty string means auto.
+func ParseUomDisambiguation(s string) (UomDisambiguation, error) {
+	switch s {
+	case "":
+		return DISAMBIGUATE_AUTO, nil
+	case DISAMBIGUATE_AUTO, DISAMBIGUATE_ALWAYS, DISAMBIGUATE_NEVER:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown disambiguate option %q (expected auto, always or never)", s)
+}
+
+type FormatOptions struct {
+	Locales      []language.Tag    // Preferred locales, most preferred first
+	Disambiguate UomDisambiguation // Defaults to auto
+}
+
+// approximatePrefix is printed before approximate quantities
+const approximatePrefix = "about "
+
+// rangeSeparator joins the bounds of a range, e.g. "2–3 cups"
+const rangeSeparator = "–"
+
+// Format prints the amount followed by the uom's printed name in the first preferred
+// locale it has names for, e.g. "1 ½ cups", "250 ml" or "2 cucharadas". On its own
+// an amount is never ambiguous, so only DISAMBIGUATE_ALWAYS picks the variant name.
+func Format(a UomAmount, opts FormatOptions) string {
+	return FormatQuantity(NewQuantity(a.Amount, a.Uom), opts)
+}
+
+// FormatList prints every amount like Format, but with auto disambiguation a uom is
+// printed with its variant name whenever another uom of its NameGroup is in the list,
+// e.g. "8 oz (weight)" next to "4 fl oz"
+func FormatList(amounts []UomAmount, opts FormatOptions) []string {
+	quantities := make([]Quantity, len(amounts))
+	for i, a := range amounts {
+		quantities[i] = NewQuantity(a.Amount, a.Uom)
+	}
+	return FormatQuantityList(quantities, opts)
+}
+
+// FormatQuantity prints a quantity like Format: "½–¾ cup", "about 1 lb" or "to taste".
+// The uom name agrees with the upper bound.
+func FormatQuantity(q Quantity, opts FormatOptions) string {
+	return FormatQuantityList([]Quantity{q}, opts)[0]
+}
+
+// FormatQuantityList prints every quantity like FormatQuantity, disambiguating uoms
+// that share a NameGroup like FormatList
+func FormatQuantityList(quantities []Quantity, opts FormatOptions) []string {
+	ambiguous := make(map[string]bool)
+	if opts.Disambiguate == "" || opts.Disambiguate == DISAMBIGUATE_AUTO {
+		members := make(map[string]string) // name group -> first uom id seen
+		for _, q := range quantities {
+			if q.Uom == nil {
+				continue
+			}
+			group := q.Uom.nameGroup()
+			if group == "" {
+				continue
+			}
+			if first, ok := members[group]; !ok {
+				members[group] = q.Uom.Id
+			} else if first != q.Uom.Id {
+				ambiguous[group] = true
+			}
+		}
+	}
+
+	formatted := make([]string, len(quantities))
+	for i, q := range quantities {
+		if q.IsQualitative() {
+			formatted[i] = q.Qualitative
+			continue
+		}
+		text := FormatAmount(q.Min, q.Uom)
+		if q.IsRange() {
+			text += rangeSeparator + FormatAmount(q.Max, q.Uom)
+		}
+		if q.Uom != nil {
+			group := q.Uom.nameGroup()
+			disambiguated := group != "" && (ambiguous[group] || opts.Disambiguate == DISAMBIGUATE_ALWAYS)
+			text += " " + q.Uom.localizedPrintedName(q.Max.Float64(), opts.Locales, disambiguated)
+		}
+		if q.Approximate {
+			text = approximatePrefix + text
+		}
+		formatted[i] = text
+	}
+	return formatted
+}
+
+// FormatAmount prints the amount the way the uom is usually written: metric-only uoms
+// use decimals, everything else (including unitless amounts, uom == nil) uses
+// fractions when the amount is close to one
+func FormatAmount(amount Rational, uom *Uom) string {
+	if uom != nil && uom.usesDecimals() {
+		return formatDecimal(amount.Float64())
+	}
+	return formatFraction(amount.Float64())
+}
+
+// printedName returns the singular or plural printed name of the default name type,
+// falling back to the other name type and finally to the label
+func (u *BaseUom) printedName(singular bool) string {
+	plural := !singular
+	short := pickName(plural, u.PrintedNameShortSingular, u.PrintedNameShortPlural)
+	full := pickName(plural, u.PrintedNameFullSingular, u.PrintedNameFullPlural)
+	if u.PrintedNameDefaultType == FULL {
+		short, full = full, short
+	}
+	if short != "" {
+		return short
+	}
+	if full != "" {
+		return full
+	}
+	return u.Label
+}
+
+func pickName(plural bool, singular, pluralName *string) string {
+	if plural && pluralName != nil && *pluralName != "" {
+		return *pluralName
+	}
+	if singular != nil && *singular != "" {
+		return *singular
+	}
+	if pluralName != nil {
+		return *pluralName
+	}
+	return ""
+}
+
+// disambiguatedName returns the variant name used when the uom could be confused with
+// another uom of its NameGroup. Without an explicit variant it qualifies the printed
+// name with the measure type, e.g. "oz (weight)".
+func (u *BaseUom) disambiguatedName(singular bool) string {
+	if name := pickName(!singular, u.PrintedNameDisambiguatedSingular, u.PrintedNameDisambiguatedPlural); name != "" {
+		return name
+	}
+	return u.printedName(singular) + " (" + u.MeasureType + ")"
+}
+
+func (u *BaseUom) nameGroup() string {
+	if u.AdditionalInfo == nil || u.AdditionalInfo.NameGroup == nil {
+		return ""
+	}
+	return *u.AdditionalInfo.NameGroup
+}
+
+func (u *BaseUom) usesDecimals() bool {
+	return u.AdditionalInfo != nil && len(u.AdditionalInfo.Systems) > 0 &&
+		!slices.ContainsFunc(u.AdditionalInfo.Systems, func(s UomSystem) bool { return s != METRIC })
+}
+
+func formatDecimal(amount float64) string {
+	s := strconv.FormatFloat(math.Round(amount*100)/100, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimRight(s, ".")
+}
+
+func formatFraction(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	whole, frac := math.Modf(amount)
+	if frac > 1-fractionTolerance {
+		whole, frac = whole+1, 0
+	}
+	if frac < fractionTolerance {
+		return sign + strconv.FormatFloat(whole, 'f', 0, 64)
+	}
+	if glyph, ok := vulgarFraction(frac); ok {
+		if whole == 0 {
+			return sign + glyph
+		}
+		return sign + strconv.FormatFloat(whole, 'f', 0, 64) + " " + glyph
+	}
+	return sign + formatDecimal(amount)
+}
+
+// vulgarFraction returns the glyph a fractional part is printed with, if it has one
+func vulgarFraction(frac float64) (string, bool) {
+	for _, f := range vulgarFractions {
+		if math.Abs(frac-f.value) < fractionTolerance {
+			return f.glyph, true
+		}
+	}
+	return "", false
+}
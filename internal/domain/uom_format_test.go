@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestFormatPluralizesFractions(t *testing.T) {
+	tests := []struct {
+		amount  string
+		uom     string
+		locales []language.Tag
+		want    string
+	}{
+		{"1/3", "cup", nil, "⅓ cup"},
+		{"1/2", "cup", nil, "½ cup"},
+		{"3/4", "cup", nil, "¾ cup"},
+		{"1", "cup", nil, "1 cup"},
+		{"1 1/2", "cup", nil, "1 ½ cups"},
+		{"2", "cup", nil, "2 cups"},
+		{"0.45", "cup", nil, "0.45 cups"},
+		{"1/2", "liter", []language.Tag{language.English}, "0.5 l"},
+		{"1/2", "milliliter", nil, "0.5 ml"},
+		{"1/2", "cup", []language.Tag{language.Spanish}, "½ taza"},
+		{"2", "cup", []language.Tag{language.Spanish}, "2 tazas"},
+		{"1/4", "teaspoon", []language.Tag{language.Spanish}, "¼ cdta"},
+	}
+	for _, tt := range tests {
+		a := UomAmount{Amount: rat(t, tt.amount), Uom: testUom(t, tt.uom)}
+		if got := Format(a, FormatOptions{Locales: tt.locales}); got != tt.want {
+			t.Errorf("Format(%s %s, %v) = %q, want %q", tt.amount, tt.uom, tt.locales, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRangePluralizesByUpperBound(t *testing.T) {
+	cup := testUom(t, "cup")
+	tests := []struct {
+		lo, hi string
+		want   string
+	}{
+		{"1/2", "3/4", "½–¾ cup"},
+		{"1/2", "1 1/2", "½–1 ½ cups"},
+		{"2", "3", "2–3 cups"},
+	}
+	for _, tt := range tests {
+		q := NewQuantityRange(rat(t, tt.lo), rat(t, tt.hi), cup)
+		if got := FormatQuantity(q, FormatOptions{}); got != tt.want {
+			t.Errorf("FormatQuantity(%s–%s cup) = %q, want %q", tt.lo, tt.hi, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// UomIDGenerator produces the id for a new Uom
+type UomIDGenerator func(base *BaseUom) (string, error)
+
+const (
+	UomIDStrategyUUIDv1 = "uuidv1"
+	UomIDStrategyUUIDv7 = "uuidv7"
+	UomIDStrategyLabel  = "label"
+)
+
+// uomLabelNamespace is the namespace used to derive deterministic ids from labels.
+// Changing it changes every label-derived id, so it must stay fixed.
+var uomLabelNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/jeffjlins/okra/uom"))
+
+// NewUomIDGenerator returns the generator for the given strategy name
+func NewUomIDGenerator(strategy string) (UomIDGenerator, error) {
+	switch strings.ToLower(strategy) {
+	case "", UomIDStrategyUUIDv1:
+		return UUIDv1IDGenerator, nil
+	case UomIDStrategyUUIDv7:
+		return UUIDv7IDGenerator, nil
+	case UomIDStrategyLabel:
+		return LabelIDGenerator, nil
+	}
+	return nil, fmt.Errorf("unknown uom id strategy %q", strategy)
+}
+
+// UUIDv1IDGenerator generates time-based (version 1) uuids
+func UUIDv1IDGenerator(_ *BaseUom) (string, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// UUIDv7IDGenerator generates time-ordered (version 7) uuids
+func UUIDv7IDGenerator(_ *BaseUom) (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// LabelIDGenerator derives a (version 5) uuid from the slug of the label so the
+// same catalog seeded in two environments ends up with identical ids
+func LabelIDGenerator(base *BaseUom) (string, error) {
+	slug := Slug(base.Label)
+	if slug == "" {
+		return "", fmt.Errorf("cannot derive id from empty label")
+	}
+	return uuid.NewSHA1(uomLabelNamespace, []byte(slug)).String(), nil
+}
+
+// DecodeUoms reads a JSON array of uoms, in the form GET /uom returns them. Uoms
+// without an id get one derived from their label.
+func DecodeUoms(r io.Reader) ([]*Uom, error) {
+	var uoms []*Uom
+	if err := json.NewDecoder(r).Decode(&uoms); err != nil {
+		return nil, fmt.Errorf("failed to decode uoms: %w", err)
+	}
+	for _, uom := range uoms {
+		if uom.Id != "" {
+			continue
+		}
+		id, err := LabelIDGenerator(&uom.BaseUom)
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		uom.Id = id
+	}
+	return uoms, nil
+}
+
+// Slug normalizes a label for lookups: lowercased, with every run of
+// non-alphanumeric characters collapsed to a single dash (e.g. "Fl. Oz" -> "fl-oz")
+func Slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the language of the printed names and match names stored directly on a uom
+var DefaultLocale = language.English
+
+// UomPluralCategory is a CLDR plural category: zero, one, two, few, many or other
+type UomPluralCategory = string
+
+const (
+	PLURAL_ZERO  UomPluralCategory = "zero"
+	PLURAL_ONE   UomPluralCategory = "one"
+	PLURAL_TWO   UomPluralCategory = "two"
+	PLURAL_FEW   UomPluralCategory = "few"
+	PLURAL_MANY  UomPluralCategory = "many"
+	PLURAL_OTHER UomPluralCategory = "other"
+)
+
+var pluralCategories = map[plural.Form]UomPluralCategory{
+	plural.Zero:  PLURAL_ZERO,
+	plural.One:   PLURAL_ONE,
+	plural.Two:   PLURAL_TWO,
+	plural.Few:   PLURAL_FEW,
+	plural.Many:  PLURAL_MANY,
+	plural.Other: PLURAL_OTHER,
+}
+
+// UomLocale holds the names of a uom in one locale. Printed names are keyed by CLDR
+// plural category so languages with more than two forms can be spelled correctly.
+type UomLocale struct {
+	ShortNames         map[UomPluralCategory]string `json:"short_names,omitempty"`
+	FullNames          map[UomPluralCategory]string `json:"full_names,omitempty"`
+	DisambiguatedNames map[UomPluralCategory]string `json:"disambiguated_names,omitempty"` // Used instead of the printed name when the NameGroup is ambiguous
+	MatchNamesRecipe   []string                     `json:"match_names_recipe,omitempty"`
+}
+
+// PluralCategory returns the CLDR plural category of the amount in the language,
+// using the amount as it is printed (at most two decimals)
+func PluralCategory(tag language.Tag, amount float64) UomPluralCategory {
+	s := strconv.FormatFloat(math.Abs(math.Round(amount*100)/100), 'f', -1, 64)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	i, _ := strconv.Atoi(intPart)
+	f, _ := strconv.Atoi(fracPart)
+	t, _ := strconv.Atoi(strings.TrimRight(fracPart, "0"))
+	v := len(fracPart)
+	w := len(strings.TrimRight(fracPart, "0"))
+	return pluralCategories[plural.Cardinal.MatchPlural(tag, i, v, w, f, t)]
+}
+
+// pluralCategory is the plural category the uom's name takes after the amount as
+// FormatAmount prints it. Decimals follow CLDR ("0.5 liters", "0.45 cups"), but a
+// vulgar fraction on its own is read as part of a single uom ("½ cup", "¾ taza"), so
+// it takes the category of 1. Mixed numbers stay plural ("1 ½ cups").
+func (u *BaseUom) pluralCategory(tag language.Tag, amount float64) UomPluralCategory {
+	if _, ok := vulgarFraction(amount); ok && !u.usesDecimals() {
+		return PluralCategory(tag, 1)
+	}
+	return PluralCategory(tag, amount)
+}
+
+// LocaleChain expands the preferred locales into the lookup order: each tag followed
+// by its parents ("fr-CA" -> "fr"), ending with the default locale
+func LocaleChain(preferred []language.Tag) []language.Tag {
+	var chain []language.Tag
+	seen := make(map[string]bool)
+	add := func(tag language.Tag) {
+		if key := tag.String(); !seen[key] {
+			seen[key] = true
+			chain = append(chain, tag)
+		}
+	}
+	for _, tag := range preferred {
+		for t := tag; !t.IsRoot(); t = t.Parent() {
+			add(t)
+		}
+	}
+	add(DefaultLocale)
+	return chain
+}
+
+// localeFor returns the first locale in the chain the uom has names for and its tag.
+// The default locale is served from the uom's own fields, so it returns nil for it.
+func (u *BaseUom) localeFor(chain []language.Tag) (*UomLocale, language.Tag) {
+	for _, tag := range chain {
+		if tag == DefaultLocale {
+			return nil, DefaultLocale
+		}
+		if loc, ok := u.Locales[tag.String()]; ok && (len(loc.ShortNames) > 0 || len(loc.FullNames) > 0) {
+			return &loc, tag
+		}
+	}
+	return nil, DefaultLocale
+}
+
+// LocalizedPrintedName returns the printed name for the amount in the first of the
+// preferred locales the uom has names for, falling back to the default locale
+func (u *BaseUom) LocalizedPrintedName(amount float64, preferred []language.Tag) string {
+	return u.localizedPrintedName(amount, preferred, false)
+}
+
+func (u *BaseUom) localizedPrintedName(amount float64, preferred []language.Tag, disambiguated bool) string {
+	loc, tag := u.localeFor(LocaleChain(preferred))
+	category := u.pluralCategory(tag, amount)
+	if loc == nil {
+		if disambiguated {
+			return u.disambiguatedName(category == PLURAL_ONE)
+		}
+		return u.printedName(category == PLURAL_ONE)
+	}
+
+	if disambiguated {
+		if name := pickPluralName(loc.DisambiguatedNames, category); name != "" {
+			return name
+		}
+	}
+	short := pickPluralName(loc.ShortNames, category)
+	full := pickPluralName(loc.FullNames, category)
+	if u.PrintedNameDefaultType == FULL {
+		short, full = full, short
+	}
+	name := short
+	if name == "" {
+		name = full
+	}
+	if disambiguated {
+		return name + " (" + u.MeasureType + ")"
+	}
+	return name
+}
+
+// LocalizedMatchNamesRecipe returns the recipe match names of every locale in the chain, including the default ones
+func (u *BaseUom) LocalizedMatchNamesRecipe(preferred []language.Tag) []string {
+	names := append([]string{}, u.MatchNamesRecipe...)
+	for _, tag := range LocaleChain(preferred) {
+		if loc, ok := u.Locales[tag.String()]; ok {
+			names = append(names, loc.MatchNamesRecipe...)
+		}
+	}
+	return names
+}
+
+// pickPluralName returns the name for the category, falling back to "other" and then "one"
+func pickPluralName(names map[UomPluralCategory]string, category UomPluralCategory) string {
+	for _, c := range []UomPluralCategory{category, PLURAL_OTHER, PLURAL_ONE} {
+		if name := names[c]; name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// validateLocales checks that locale keys are BCP 47 tags and names are keyed by plural category
+func (u *BaseUom) validateLocales() error {
+	for key, loc := range u.Locales {
+		tag, err := language.Parse(key)
+		if err != nil {
+			return fmt.Errorf("locales: %q is not a BCP 47 language tag: %w", key, err)
+		}
+		if tag.String() != key {
+			return fmt.Errorf("locales: %q must be written in canonical form %q", key, tag.String())
+		}
+		for _, names := range []map[UomPluralCategory]string{loc.ShortNames, loc.FullNames, loc.DisambiguatedNames} {
+			for category := range names {
+				if !isPluralCategory(category) {
+					return fmt.Errorf("locales.%s: unknown plural category %q", key, category)
+				}
+			}
+		}
+		for _, name := range loc.MatchNamesRecipe {
+			if Slug(name) == "" {
+				return fmt.Errorf("locales.%s: match names must not be empty", key)
+			}
+		}
+	}
+	return nil
+}
+
+func isPluralCategory(category UomPluralCategory) bool {
+	for _, c := range pluralCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// UomMatcher finds uom match names in free text. It is an immutable trie over the
+// normalized match names of the enabled uoms in a catalog, so it is safe for
+// concurrent use and only needs to be built once per catalog version.
+//
+// Matching is case-insensitive and, like Slug, treats every run of characters other
+// than letters and digits as a single separator, so "Fl. Oz.", "fl-oz" and "fl oz" all
+// match each other and "tbsp." matches "tbsp". Two names the catalog's uniqueness
+// checks consider the same are therefore also the same to the matcher. When several
+// names match at the same position the longest one wins ("fl oz" over "fl").
+type UomMatcher struct {
+	root *matcherNode
+}
+
+// UomMatch is one match name found in the text. Start and End are byte offsets into
+// the text; End includes a trailing period such as the one in "tbsp.".
+type UomMatch struct {
+	Uom   *Uom   `json:"uom"`
+	Name  string `json:"name"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+type matcherNode struct {
+	children map[rune]*matcherNode
+	uom      *Uom
+	name     string
+}
+
+// matcherSeparator is the edge used for any run of separator characters
+const matcherSeparator = ' '
+
+// NewUomMatcher builds a matcher over names(uom) for every enabled uom. When two uoms
+// share a normalized name, the first one in the slice keeps it.
+func NewUomMatcher(uoms []*Uom, names func(*Uom) []string) *UomMatcher {
+	m := &UomMatcher{root: &matcherNode{}}
+	for _, uom := range uoms {
+		if !uom.Enabled {
+			continue
+		}
+		for _, name := range names(uom) {
+			m.insert(uom, name)
+		}
+	}
+	return m
+}
+
+func (m *UomMatcher) insert(uom *Uom, name string) {
+	key := normalizeMatchName(name)
+	if key == "" {
+		return
+	}
+	node := m.root
+	for _, r := range key {
+		if node.children == nil {
+			node.children = make(map[rune]*matcherNode)
+		}
+		child, ok := node.children[r]
+		if !ok {
+			child = &matcherNode{}
+			node.children[r] = child
+		}
+		node = child
+	}
+	if node.uom == nil {
+		node.uom = uom
+		node.name = name
+	}
+}
+
+// MatchPrefix returns the longest match starting at the beginning of text, ignoring leading whitespace
+func (m *UomMatcher) MatchPrefix(text string) (UomMatch, bool) {
+	start := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+	return m.matchAt(text, start)
+}
+
+// FindAll scans text left to right and returns the non-overlapping longest matches.
+// A match must not start or end in the middle of a word, but may directly follow a
+// number ("2tbsp").
+func (m *UomMatcher) FindAll(text string) []UomMatch {
+	var matches []UomMatch
+	prev := rune(-1)
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsLetter(prev) && !isMatcherSeparator(r) {
+			if match, ok := m.matchAt(text, i); ok {
+				matches = append(matches, match)
+				prev, _ = utf8.DecodeLastRuneInString(text[:match.End])
+				i = match.End
+				continue
+			}
+		}
+		prev = r
+		i += size
+	}
+	return matches
+}
+
+// matchAt walks the trie from position start and returns the longest match that ends on a word boundary
+func (m *UomMatcher) matchAt(text string, start int) (UomMatch, bool) {
+	var best *matcherNode
+	bestEnd := 0
+
+	node := m.root
+	pendingSeparator := false
+	for i := start; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if isMatcherSeparator(r) {
+			pendingSeparator = true
+			i += size
+			continue
+		}
+		if pendingSeparator {
+			node = node.children[matcherSeparator]
+			if node == nil {
+				break
+			}
+			pendingSeparator = false
+		}
+		node = node.children[unicode.ToLower(r)]
+		if node == nil {
+			break
+		}
+		i += size
+		if node.uom != nil && endsWord(text, i) {
+			best = node
+			bestEnd = i
+		}
+	}
+
+	if best == nil {
+		return UomMatch{}, false
+	}
+	// Swallow abbreviation periods ("tbsp.")
+	for bestEnd < len(text) && text[bestEnd] == '.' {
+		bestEnd++
+	}
+	return UomMatch{
+		Uom:   best.uom,
+		Name:  best.name,
+		Text:  text[start:bestEnd],
+		Start: start,
+		End:   bestEnd,
+	}, true
+}
+
+// normalizeMatchName lowercases the name and collapses separator runs into a single space
+func normalizeMatchName(name string) string {
+	var b strings.Builder
+	pendingSeparator := false
+	for _, r := range name {
+		if isMatcherSeparator(r) {
+			pendingSeparator = b.Len() > 0
+			continue
+		}
+		if pendingSeparator {
+			b.WriteRune(matcherSeparator)
+			pendingSeparator = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// isMatcherSeparator reports whether r separates words, matching what Slug turns into a dash
+func isMatcherSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// endsWord reports whether position i in text is not followed by a letter
+func endsWord(text string, i int) bool {
+	if i >= len(text) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return !unicode.IsLetter(r)
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUomMatcherFindAll(t *testing.T) {
+	m := testCatalog(t).RecipeMatcher(nil)
+
+	type match struct{ text, label string }
+	tests := []struct {
+		name string
+		text string
+		want []match
+	}{
+		{"lowercase", "2 tbsp butter", []match{{"tbsp", "tablespoon"}}},
+		{"uppercase", "1 TABLESPOON oil", []match{{"TABLESPOON", "tablespoon"}}},
+		{"mixed case", "1 Tsp salt", []match{{"Tsp", "teaspoon"}}},
+		{"trailing dot", "2 Tbsp. butter", []match{{"Tbsp.", "tablespoon"}}},
+		{"dot at end of text", "salt, 1 tsp.", []match{{"tsp.", "teaspoon"}}},
+		{"longest match", "8 fl oz milk", []match{{"fl oz", "fluid ounce"}}},
+		{"longest match with dots", "8 Fl. Oz. milk", []match{{"Fl. Oz.", "fluid ounce"}}},
+		{"longest match with dash", "8 fl-oz milk", []match{{"fl-oz", "fluid ounce"}}},
+		{"shorter name alone", "8 oz cheese", []match{{"oz", "ounce"}}},
+		{"after a number", "2tbsp sugar", []match{{"tbsp", "tablespoon"}}},
+		{"several", "2 cups flour and 1 tsp salt", []match{{"cups", "cup"}, {"tsp", "teaspoon"}}},
+		{"in parentheses", "2 (14.5 oz) cans tomatoes", []match{{"oz", "ounce"}, {"cans", "can"}}},
+		{"inside a word", "tbspoon scups", nil},
+		{"no uom", "salt and pepper", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []match
+			for _, found := range m.FindAll(tt.text) {
+				if tt.text[found.Start:found.End] != found.Text {
+					t.Errorf("match %q has offsets %d..%d covering %q", found.Text, found.Start, found.End, tt.text[found.Start:found.End])
+				}
+				got = append(got, match{found.Text, found.Uom.Label})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FindAll(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUomMatcherSeparatorsMatchSlug(t *testing.T) {
+	// A name the uniqueness checks slug the same way as text must be found in that text
+	uom := &Uom{BaseUom: BaseUom{Label: "fluid ounce", Enabled: true, MatchNamesRecipe: []string{"fl-oz"}}, Id: "fl-oz"}
+	m := NewUomMatcher([]*Uom{uom}, func(u *Uom) []string { return u.MatchNamesRecipe })
+
+	for _, text := range []string{"fl-oz", "fl oz", "Fl. Oz.", "fl_oz", "fl/oz"} {
+		if Slug(text) != Slug("fl-oz") {
+			t.Fatalf("Slug(%q) = %q, want %q", text, Slug(text), Slug("fl-oz"))
+		}
+		match, ok := m.MatchPrefix(text)
+		if !ok || match.Uom != uom {
+			t.Errorf("MatchPrefix(%q) = %v, %v; want the fl-oz uom", text, match.Text, ok)
+		}
+	}
+}
+
+func TestUomMatcherFindsEveryCatalogName(t *testing.T) {
+	catalog := testCatalog(t)
+	m := catalog.RecipeMatcher(nil)
+	for _, uom := range catalog.All() {
+		for _, name := range uom.MatchNamesRecipe {
+			match, ok := m.MatchPrefix(name)
+			if !ok || match.End != len(name) {
+				t.Errorf("match name %q of %s is not found in its own text", name, uom.Label)
+				continue
+			}
+			if want := catalog.ByRecipeMatchName(name); match.Uom != want {
+				t.Errorf("match name %q matches %s, but the catalog resolves it to %s", name, match.Uom.Label, want.Label)
+			}
+		}
+	}
+}
+
+// benchmarkRecipe is a typical ingredient list
+const benchmarkRecipe = `2 1/4 cups all-purpose flour
+1 tsp baking soda
+1 tsp. salt
+1 cup (2 sticks) butter, softened
+3/4 cup granulated sugar
+3/4 cup packed brown sugar
+1 Tbsp vanilla extract
+2 large eggs
+2 cups chocolate chips
+1 (14.5 oz) can diced tomatoes, drained
+8 fl oz whole milk
+500 g strong white flour
+7 g dried yeast
+300 ml lukewarm water
+2 tbsp olive oil, plus extra for greasing
+salt and pepper to taste
+`
+
+func BenchmarkUomMatcher(b *testing.B) {
+	catalog := testCatalog(b)
+	text := strings.Repeat(benchmarkRecipe, 8)
+
+	b.Run("FindAll", func(b *testing.B) {
+		m := catalog.RecipeMatcher(nil)
+		b.SetBytes(int64(len(text)))
+		b.ReportAllocs()
+		for b.Loop() {
+			m.FindAll(text)
+		}
+	})
+	b.Run("Build", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			NewUomMatcher(catalog.All(), func(u *Uom) []string { return u.MatchNamesRecipe })
+		}
+	})
+}
@@ -1,10 +1,23 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is wrapped by the errors about uoms that don't exist, e.g. "uom with id
+// x not found"
+var ErrNotFound = errors.New("not found")
 
 type UomRepository interface {
-	Save(ctx context.Context, uom *Uom) error
+	// Create stores a new uom. It fails with an "already exists" error if the id,
+	// the label or any match name is already taken.
+	Create(ctx context.Context, uom *Uom) error
+	// Update replaces an existing uom. It fails with an error wrapping ErrNotFound if the uom
+	// doesn't exist and an "already exists" error if the label or a match name is taken.
+	Update(ctx context.Context, uom *Uom) error
 	GetByID(ctx context.Context, id string) (*Uom, error)
+	GetByLabel(ctx context.Context, label string) (*Uom, error)
 	GetAll(ctx context.Context) ([]*Uom, error)
 	Delete(ctx context.Context, id string) error
 }
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+type UomSystem = string
+
+const (
+	US_CUSTOMARY UomSystem = "us_customary"
+	IMPERIAL     UomSystem = "imperial"
+	METRIC       UomSystem = "metric"
+)
+
+// UomSystems lists the valid measurement systems
+var UomSystems = []UomSystem{US_CUSTOMARY, IMPERIAL, METRIC}
+
+// ParseUomSystem validates a system name. The empty string is allowed and means "any system".
+func ParseUomSystem(s string) (UomSystem, error) {
+	if s == "" || slices.Contains(UomSystems, s) {
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown measurement system %q (expected one of %v)", s, UomSystems)
+}
+
+// InSystem reports whether the uom belongs to the system. Every uom is in the empty system.
+func (u *BaseUom) InSystem(system UomSystem) bool {
+	if system == "" {
+		return true
+	}
+	return u.AdditionalInfo != nil && slices.Contains(u.AdditionalInfo.Systems, system)
+}
+
+// validateSystems checks that every listed system is a known one
+func (u *BaseUom) validateSystems() error {
+	if u.AdditionalInfo == nil {
+		return nil
+	}
+	for _, system := range u.AdditionalInfo.Systems {
+		if system == "" {
+			return fmt.Errorf("info.systems: system must not be empty")
+		}
+		if _, err := ParseUomSystem(system); err != nil {
+			return fmt.Errorf("info.systems: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+// Package telemetry installs the OpenTelemetry tracer and meter providers. The rest of
+// the application instruments itself against the global providers (otel.Tracer and
+// otel.Meter), which stay no-op until Setup installs real ones.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// Exporters accepted by Setup
+const (
+	EXPORTER_NONE   = "none"
+	EXPORTER_OTLP   = "otlp"
+	EXPORTER_STDOUT = "stdout"
+	EXPORTER_FILE   = "file"
+)
+
+// OTLP protocols
+const (
+	PROTOCOL_GRPC = "grpc"
+	PROTOCOL_HTTP = "http"
+)
+
+type Options struct {
+	ServiceName    string
+	Exporter       string        // none (default), otlp, stdout or file
+	Protocol       string        // otlp only: grpc (default) or http
+	Endpoint       string        // otlp only: Optional, "host:port" or a URL. Defaults to the OTEL_EXPORTER_OTLP_* env vars, then localhost
+	Insecure       bool          // otlp only: connect without TLS
+	File           string        // file only: spans and metrics are appended to this file as JSON lines
+	SampleRatio    float64       // share of new traces recorded, 0 to 1. Traces started upstream follow the caller's decision
+	MetricInterval time.Duration // how often metrics are exported
+}
+
+// Setup installs global tracer and meter providers that export as configured, plus
+// the W3C trace context propagator. It returns a function that flushes and stops them.
+// With the none exporter nothing is installed and shutdown does nothing.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	noop := func(context.Context) error { return nil }
+	if opts.Exporter == "" || opts.Exporter == EXPORTER_NONE {
+		return noop, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(opts.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build telemetry resource: %w", err)
+	}
+
+	var closers []func(context.Context) error
+	shutdown = func(ctx context.Context) error {
+		var errs []error
+		for i := len(closers) - 1; i >= 0; i-- {
+			errs = append(errs, closers[i](ctx))
+		}
+		return errors.Join(errs...)
+	}
+	defer func() {
+		if err != nil {
+			shutdown(ctx)
+		}
+	}()
+
+	spans, metrics, closeFile, err := newExporters(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if closeFile != nil {
+		closers = append(closers, func(context.Context) error { return closeFile() })
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(spans),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	closers = append(closers, tracerProvider.Shutdown)
+
+	interval := opts.MetricInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metrics, sdkmetric.WithInterval(interval))),
+	)
+	closers = append(closers, meterProvider.Shutdown)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return shutdown, nil
+}
+
+// newExporters builds the span and metric exporters. closeFile is set when they write to a file.
+func newExporters(ctx context.Context, opts Options) (sdktrace.SpanExporter, sdkmetric.Exporter, func() error, error) {
+	switch opts.Exporter {
+	case EXPORTER_OTLP:
+		spans, metrics, err := newOTLPExporters(ctx, opts)
+		return spans, metrics, nil, err
+
+	case EXPORTER_STDOUT:
+		spans, metrics, err := newWriterExporters(os.Stdout)
+		return spans, metrics, nil, err
+
+	case EXPORTER_FILE:
+		if opts.File == "" {
+			return nil, nil, nil, errors.New("the file exporter needs a file")
+		}
+		f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to open telemetry file: %w", err)
+		}
+		spans, metrics, err := newWriterExporters(f)
+		if err != nil {
+			f.Close()
+			return nil, nil, nil, err
+		}
+		return spans, metrics, f.Close, nil
+	}
+	return nil, nil, nil, fmt.Errorf("unknown exporter %q (expected none, otlp, stdout or file)", opts.Exporter)
+}
+
+func newWriterExporters(w io.Writer) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {
+	spans, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create span exporter: %w", err)
+	}
+	metrics, err := stdoutmetric.New(stdoutmetric.WithWriter(w))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+	return spans, metrics, nil
+}
+
+func newOTLPExporters(ctx context.Context, opts Options) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {
+	isURL := strings.Contains(opts.Endpoint, "://")
+
+	var spans sdktrace.SpanExporter
+	var metrics sdkmetric.Exporter
+	var err error
+	switch opts.Protocol {
+	case "", PROTOCOL_GRPC:
+		var traceOpts []otlptracegrpc.Option
+		var metricOpts []otlpmetricgrpc.Option
+		if opts.Endpoint != "" && isURL {
+			traceOpts = append(traceOpts, otlptracegrpc.WithEndpointURL(opts.Endpoint))
+			metricOpts = append(metricOpts, otlpmetricgrpc.WithEndpointURL(opts.Endpoint))
+		} else if opts.Endpoint != "" {
+			traceOpts = append(traceOpts, otlptracegrpc.WithEndpoint(opts.Endpoint))
+			metricOpts = append(metricOpts, otlpmetricgrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			traceOpts = append(traceOpts, otlptracegrpc.WithInsecure())
+			metricOpts = append(metricOpts, otlpmetricgrpc.WithInsecure())
+		}
+		if spans, err = otlptracegrpc.New(ctx, traceOpts...); err == nil {
+			metrics, err = otlpmetricgrpc.New(ctx, metricOpts...)
+		}
+
+	case PROTOCOL_HTTP:
+		var traceOpts []otlptracehttp.Option
+		var metricOpts []otlpmetrichttp.Option
+		if opts.Endpoint != "" && isURL {
+			traceOpts = append(traceOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+			metricOpts = append(metricOpts, otlpmetrichttp.WithEndpointURL(opts.Endpoint))
+		} else if opts.Endpoint != "" {
+			traceOpts = append(traceOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+			metricOpts = append(metricOpts, otlpmetrichttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+			metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+		}
+		if spans, err = otlptracehttp.New(ctx, traceOpts...); err == nil {
+			metrics, err = otlpmetrichttp.New(ctx, metricOpts...)
+		}
+
+	default:
+		return nil, nil, fmt.Errorf("unknown otlp protocol %q (expected grpc or http)", opts.Protocol)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+	}
+	return spans, metrics, nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+type ConversionService struct {
+	repo    domain.UomRepository
+	metrics Metrics
+}
+
+func NewConversionService(repo domain.UomRepository, opts ...Option) *ConversionService {
+	o := newOptions(opts)
+	return &ConversionService{
+		repo:    repo,
+		metrics: o.metrics,
+	}
+}
+
+// Convert expresses the quantity in the to uom. Uoms are referenced by id, label or match name.
+func (s *ConversionService) Convert(ctx context.Context, q QuantityLine, to string) (domain.Quantity, error) {
+	catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+	if err != nil {
+		return domain.Quantity{}, fmt.Errorf("failed to load uom catalog: %w", err)
+	}
+	from, err := resolveQuantity(catalog, q)
+	if err != nil {
+		return domain.Quantity{}, err
+	}
+	toUom, err := resolveUom(catalog, to)
+	if err != nil {
+		return domain.Quantity{}, err
+	}
+
+	converted, err := domain.ConvertQuantity(from, toUom)
+	if err != nil {
+		return domain.Quantity{}, err
+	}
+	s.metrics.Converted(from.Uom.MeasureType, toUom.MeasureType)
+	return converted, nil
+}
+
+// Humanize re-expresses the quantity in the most natural uom of the target system, or
+// of its uom's group when system is empty
+func (s *ConversionService) Humanize(ctx context.Context, q QuantityLine, system domain.UomSystem) (domain.Quantity, error) {
+	catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+	if err != nil {
+		return domain.Quantity{}, fmt.Errorf("failed to load uom catalog: %w", err)
+	}
+	from, err := resolveQuantity(catalog, q)
+	if err != nil {
+		return domain.Quantity{}, err
+	}
+
+	humanized, err := domain.HumanizeQuantity(from, catalog.All(), system)
+	if err != nil {
+		return domain.Quantity{}, err
+	}
+	s.metrics.HumanizeSelected(humanized.Uom)
+	return humanized, nil
+}
+
+// Format prints the quantities as one list, so uoms sharing a NameGroup are told apart
+// according to opts.Disambiguate
+func (s *ConversionService) Format(ctx context.Context, items []QuantityLine, opts domain.FormatOptions) ([]domain.Quantity, []string, error) {
+	catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load uom catalog: %w", err)
+	}
+
+	quantities := make([]domain.Quantity, len(items))
+	for i, item := range items {
+		q, err := resolveQuantity(catalog, item)
+		if err != nil {
+			return nil, nil, err
+		}
+		quantities[i] = q
+	}
+	return quantities, domain.FormatQuantityList(quantities, opts), nil
+}
+
+// resolveQuantity resolves a structured quantity of a uom; conversions need both
+func resolveQuantity(catalog *domain.UomCatalog, q QuantityLine) (domain.Quantity, error) {
+	if q.Amount == nil {
+		return domain.Quantity{}, fmt.Errorf("validation failed: amount is required")
+	}
+	if q.Uom == "" {
+		return domain.Quantity{}, fmt.Errorf("validation failed: uom is required")
+	}
+	line, err := resolveQuantityLine(catalog, QuantityLine{Amount: q.Amount, Max: q.Max, Approximate: q.Approximate, Uom: q.Uom}, nil)
+	if err != nil {
+		return domain.Quantity{}, err
+	}
+	return *line.Quantity, nil
+}
+
+func resolveUom(catalog *domain.UomCatalog, ref string) (*domain.Uom, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("validation failed: uom is required")
+	}
+	uom := catalog.Resolve(ref)
+	if uom == nil {
+		return nil, fmt.Errorf("uom %s %w", ref, domain.ErrNotFound)
+	}
+	return uom, nil
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+// Health statuses of a component and of the whole service
+const (
+	HEALTH_UP   = "up"
+	HEALTH_DOWN = "down"
+)
+
+// healthProbeID is read from the backend to check it answers. The uom needn't exist.
+const healthProbeID = "okra-health-probe"
+
+// ComponentHealth is the outcome of probing one dependency
+type ComponentHealth struct {
+	Name    string
+	Status  string
+	Latency time.Duration
+	Error   string         // why the component is down
+	Details map[string]any // Optional: component specific state, e.g. the catalog size
+}
+
+// Readiness is up only when every component is
+type Readiness struct {
+	Status     string
+	Components []ComponentHealth
+}
+
+// HealthProbe checks one more dependency, such as a background listener. It returns
+// optional details and why the component is down.
+type HealthProbe struct {
+	Name  string
+	Probe func(ctx context.Context) (map[string]any, error)
+}
+
+// HealthService probes the dependencies a request needs, to tell whether the instance
+// can serve traffic
+type HealthService struct {
+	backend domain.UomRepository // the store itself, bypassing any cache
+	repo    domain.UomRepository // what the other services read uoms through
+	timeout time.Duration
+	extra   []HealthProbe
+}
+
+// NewHealthService probes backend with a cheap read, loads the catalog through repo and
+// runs the extra probes. Each probe gives up after timeout, 2s when it isn't positive.
+func NewHealthService(backend, repo domain.UomRepository, timeout time.Duration, extra ...HealthProbe) *HealthService {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &HealthService{
+		backend: backend,
+		repo:    repo,
+		timeout: timeout,
+		extra:   extra,
+	}
+}
+
+// Readiness runs the probes concurrently against the global catalog
+func (s *HealthService) Readiness(ctx context.Context) Readiness {
+	ctx = domain.WithTenant(ctx, "")
+	probes := append([]HealthProbe{
+		{"repository", s.probeRepository},
+		{"catalog", s.probeCatalog},
+	}, s.extra...)
+
+	components := make([]ComponentHealth, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Go(func() {
+			components[i] = s.check(ctx, p.Name, p.Probe)
+		})
+	}
+	wg.Wait()
+
+	readiness := Readiness{Status: HEALTH_UP, Components: components}
+	for _, c := range components {
+		if c.Status != HEALTH_UP {
+			readiness.Status = HEALTH_DOWN
+		}
+	}
+	return readiness
+}
+
+func (s *HealthService) check(ctx context.Context, name string, probe func(ctx context.Context) (map[string]any, error)) ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := probe(ctx)
+	c := ComponentHealth{Name: name, Status: HEALTH_UP, Latency: time.Since(start), Details: details}
+	if err != nil {
+		c.Status = HEALTH_DOWN
+		c.Error = err.Error()
+	}
+	return c
+}
+
+func (s *HealthService) probeRepository(ctx context.Context) (map[string]any, error) {
+	_, err := s.backend.GetByID(ctx, healthProbeID)
+	return nil, err
+}
+
+// probeCatalog reports whether the catalog loads and how many uoms it has. An empty
+// catalog is still up: it's a valid, if unusual, state.
+func (s *HealthService) probeCatalog(ctx context.Context) (map[string]any, error) {
+	catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"uoms":    catalog.Len(),
+		"version": catalog.Version(),
+	}, nil
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeffjlins/okra/internal/domain"
+	"golang.org/x/text/language"
+)
+
+type IngredientService struct {
+	repo    domain.UomRepository
+	metrics Metrics
+}
+
+func NewIngredientService(repo domain.UomRepository, opts ...Option) *IngredientService {
+	o := newOptions(opts)
+	return &IngredientService{
+		repo:    repo,
+		metrics: o.metrics,
+	}
+}
+
+// Parse splits each recipe line into quantity, uom, size, ingredient, preparation and notes
+func (s *IngredientService) Parse(ctx context.Context, lines []string, locales []language.Tag) ([]domain.IngredientLine, error) {
+	catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load uom catalog: %w", err)
+	}
+
+	parsed := make([]domain.IngredientLine, len(lines))
+	for i, line := range lines {
+		parsed[i] = domain.ParseIngredientLine(line, catalog, locales)
+		if q := parsed[i].Quantity; q != nil && !q.IsQualitative() {
+			s.metrics.UomParsed(parsed[i].UomName, q.Uom != nil)
+		}
+	}
+	return parsed, nil
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import "github.com/jeffjlins/okra/internal/domain"
+
+// Metrics receives the domain events okra counts. Implementations must be safe for
+// concurrent use and must not block.
+type Metrics interface {
+	// UomParsed records a uom lookup in free text: a hit by the match name that was
+	// found, or a miss (matchName is empty) when text with an amount had no known uom
+	UomParsed(matchName string, hit bool)
+	// Converted records a conversion between measure types, e.g. volume to weight
+	Converted(from, to domain.UomMeasureType)
+	// HumanizeSelected records the uom a humanized amount was expressed in
+	HumanizeSelected(uom *domain.Uom)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) UomParsed(string, bool)                                 {}
+func (noopMetrics) Converted(domain.UomMeasureType, domain.UomMeasureType) {}
+func (noopMetrics) HumanizeSelected(*domain.Uom)                           {}
+
+// Option configures a service
+type Option func(*options)
+
+type options struct {
+	metrics Metrics
+}
+
+// WithMetrics reports the service's domain events to m
+func WithMetrics(m Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{metrics: noopMetrics{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/jeffjlins/okra/internal/domain"
+	"golang.org/x/text/language"
+)
+
+// QuantityLine is one ingredient quantity, either as free text ("2-3 tbsp butter") or
+// structured. Text is only parsed when neither Amount nor Qualitative is set.
+type QuantityLine struct {
+	Text        string
+	Amount      *domain.Rational // the amount, or the lower bound of a range
+	Max         *domain.Rational // Optional: the upper bound of a range
+	Approximate bool
+	Qualitative string // a quantity without a number, e.g. "to taste"
+	Uom         string // uom id, label or match name; empty for plain counts
+	Ingredient  string
+}
+
+// resolveQuantityLine turns a line into an ingredient line. Free text is parsed with
+// the ingredient parser; the quantity is nil for lines that have none ("salt").
+// Negative amounts are rejected: no recipe measures less than nothing.
+func resolveQuantityLine(catalog *domain.UomCatalog, line QuantityLine, locales []language.Tag) (domain.IngredientLine, error) {
+	if line.Qualitative != "" {
+		q := domain.QualitativeQuantity(line.Qualitative)
+		return domain.IngredientLine{Quantity: &q, Ingredient: line.Ingredient}, nil
+	}
+	if line.Amount == nil {
+		return domain.ParseIngredientLine(line.Text, catalog, locales), nil
+	}
+
+	if line.Amount.Sign() < 0 || line.Max != nil && line.Max.Sign() < 0 {
+		return domain.IngredientLine{}, fmt.Errorf("validation failed: amounts must not be negative")
+	}
+	q := domain.NewQuantity(*line.Amount, nil)
+	if line.Max != nil {
+		q = domain.NewQuantityRange(*line.Amount, *line.Max, nil)
+	}
+	q.Approximate = line.Approximate
+	if line.Uom != "" {
+		uom, err := resolveUom(catalog, line.Uom)
+		if err != nil {
+			return domain.IngredientLine{}, err
+		}
+		q.Uom = uom
+	}
+	return domain.IngredientLine{Quantity: &q, Ingredient: line.Ingredient}, nil
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+// unitlessSnap is the increment plain counts are rounded to when scaled ("1 ½ eggs")
+var unitlessSnap = domain.NewRational(1, 4)
+
+type ScaleService struct {
+	repo    domain.UomRepository
+	metrics Metrics
+}
+
+func NewScaleService(repo domain.UomRepository, opts ...Option) *ScaleService {
+	o := newOptions(opts)
+	return &ScaleService{
+		repo:    repo,
+		metrics: o.metrics,
+	}
+}
+
+type ScaleOptions struct {
+	Factor domain.Rational
+	System domain.UomSystem // Optional: express scaled amounts in this system
+	Format domain.FormatOptions
+}
+
+// ScaledQuantity is a quantity before or after scaling along with its printed form
+type ScaledQuantity struct {
+	Quantity domain.Quantity
+	Text     string
+}
+
+type ScaledLine struct {
+	Ingredient string
+	Original   *ScaledQuantity // nil when the line had no quantity ("salt")
+	Scaled     *ScaledQuantity
+	Text       string // the full scaled line, e.g. "1 cup butter"
+}
+
+// Scale multiplies every line by the factor, then re-humanizes each amount with the
+// snap rules so it is promoted or demoted to the natural uom (16 tbsp -> 1 cup).
+// Both bounds of a range are scaled and kept in one uom ("2–3 tbsp" x4 -> "½–¾ cup").
+// Lines without a quantity and qualitative quantities ("to taste") are passed through
+// unchanged, and plain counts keep the ingredient in agreement ("1 egg" x4 -> "4 eggs").
+func (s *ScaleService) Scale(ctx context.Context, lines []QuantityLine, opts ScaleOptions) ([]ScaledLine, error) {
+	if opts.Factor.Sign() <= 0 {
+		return nil, fmt.Errorf("validation failed: factor must be a positive number")
+	}
+
+	catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load uom catalog: %w", err)
+	}
+
+	originals := make([]*domain.Quantity, len(lines))
+	scaled := make([]*domain.Quantity, len(lines))
+	ingredients := make([]string, len(lines))
+	for i, line := range lines {
+		parsed, err := resolveQuantityLine(catalog, line, opts.Format.Locales)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if original := parsed.Quantity; original != nil {
+			result, err := scaleQuantity(catalog, *original, opts.Factor, opts.System)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if result.Uom != nil && !result.IsQualitative() {
+				s.metrics.HumanizeSelected(result.Uom)
+			}
+			originals[i], scaled[i] = original, &result
+			// "1 egg" x4 is "4 eggs"
+			parsed.Ingredient = domain.AgreeWithCount(parsed.Ingredient, result, opts.Format.Locales)
+		}
+
+		ingredients[i] = parsed.Description()
+		if parsed.PackageSize != nil {
+			// The package size doesn't scale: "4 (14.5 oz) cans"
+			ingredients[i] = "(" + domain.Format(*parsed.PackageSize, opts.Format) + ") " + ingredients[i]
+		}
+	}
+
+	originalTexts := formatPresent(originals, opts.Format)
+	scaledTexts := formatPresent(scaled, opts.Format)
+
+	result := make([]ScaledLine, len(lines))
+	for i := range lines {
+		result[i] = ScaledLine{Ingredient: ingredients[i], Text: ingredients[i]}
+		if originals[i] == nil {
+			continue
+		}
+		result[i].Original = &ScaledQuantity{Quantity: *originals[i], Text: originalTexts[i]}
+		result[i].Scaled = &ScaledQuantity{Quantity: *scaled[i], Text: scaledTexts[i]}
+		result[i].Text = domain.JoinQuantity(scaledTexts[i], scaled[i], ingredients[i])
+	}
+	return result, nil
+}
+
+func scaleQuantity(catalog *domain.UomCatalog, q domain.Quantity, factor domain.Rational, system domain.UomSystem) (domain.Quantity, error) {
+	q = q.Scale(factor)
+	if q.IsQualitative() {
+		return q, nil
+	}
+	if q.Uom == nil {
+		q.Min, q.Max = snapUnitless(q.Min), snapUnitless(q.Max)
+		return q, nil
+	}
+	return domain.HumanizeQuantity(q, catalog.All(), system)
+}
+
+func snapUnitless(amount domain.Rational) domain.Rational {
+	snapped := amount.Round(unitlessSnap)
+	if snapped.IsZero() {
+		snapped = unitlessSnap
+	}
+	return snapped
+}
+
+// formatPresent formats the non-nil quantities together so NameGroup disambiguation sees the whole list
+func formatPresent(quantities []*domain.Quantity, opts domain.FormatOptions) []string {
+	present := make([]domain.Quantity, 0, len(quantities))
+	for _, q := range quantities {
+		if q != nil {
+			present = append(present, *q)
+		}
+	}
+	formatted := domain.FormatQuantityList(present, opts)
+
+	texts := make([]string, len(quantities))
+	j := 0
+	for i, q := range quantities {
+		if q != nil {
+			texts[i] = formatted[j]
+			j++
+		}
+	}
+	return texts
+}
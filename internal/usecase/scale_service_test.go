@@ -0,0 +1,99 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeffjlins/okra/internal/adapters/outbound/memory"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/usecase"
+	"github.com/jeffjlins/okra/pkg/okra"
+)
+
+// newTestRepo serves the default catalog pkg/okra embeds
+func newTestRepo(tb testing.TB) *memory.UomRepository {
+	tb.Helper()
+	uoms, err := okra.DefaultUoms()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	repo, err := memory.NewUomRepository(uoms)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return repo
+}
+
+func TestScaleService(t *testing.T) {
+	svc := usecase.NewScaleService(newTestRepo(t))
+	lines := []usecase.QuantityLine{
+		{Text: "2-3 tbsp butter"},
+		{Text: "16 tbsp sugar"},
+		{Text: "2 (14.5 oz) cans diced tomatoes, drained"},
+		{Text: "1 egg"},
+		{Text: "salt to taste"},
+		{Text: "pepper"},
+	}
+	want := []struct {
+		original, scaled, text string
+	}{
+		{"2–3 tbsp", "½–¾ cup", "½–¾ cup butter"},
+		{"16 tbsp", "1 quart", "1 quart sugar"},
+		{"2 cans", "8 cans", "8 cans (14 ½ oz) diced tomatoes, drained"},
+		{"1", "4", "4 eggs"},
+		{"to taste", "to taste", "salt to taste"},
+		{"", "", "pepper"},
+	}
+
+	got, err := svc.Scale(context.Background(), lines, usecase.ScaleOptions{Factor: domain.IntRational(4)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, line := range got {
+		var original, scaled string
+		if line.Original != nil {
+			original, scaled = line.Original.Text, line.Scaled.Text
+		}
+		if original != want[i].original || scaled != want[i].scaled || line.Text != want[i].text {
+			t.Errorf("Scale(%q) = %q -> %q (%q), want %q -> %q (%q)", lines[i].Text,
+				original, scaled, line.Text, want[i].original, want[i].scaled, want[i].text)
+		}
+	}
+}
+
+func TestScaleServiceAgreesWithCounts(t *testing.T) {
+	svc := usecase.NewScaleService(newTestRepo(t))
+	tests := []struct {
+		line   string
+		factor domain.Rational
+		want   string
+	}{
+		{"2 large eggs, beaten", domain.NewRational(1, 2), "1 large egg, beaten"},
+		{"1 onion", domain.NewRational(1, 2), "½ onion"},
+		{"1 cherry", domain.IntRational(3), "3 cherries"},
+		{"1-2 peaches", domain.IntRational(2), "2–4 peaches"},
+		{"4 tomatoes", domain.NewRational(1, 4), "1 tomato"},
+		{"1 cup molasses", domain.IntRational(2), "2 cups molasses"},
+		{"a pinch of salt", domain.IntRational(2), "a pinch of salt"},
+		{"pepper as needed", domain.IntRational(2), "pepper as needed"},
+	}
+	for _, tt := range tests {
+		got, err := svc.Scale(context.Background(), []usecase.QuantityLine{{Text: tt.line}}, usecase.ScaleOptions{Factor: tt.factor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[0].Text != tt.want {
+			t.Errorf("Scale(%q x%s) = %q, want %q", tt.line, tt.factor, got[0].Text, tt.want)
+		}
+	}
+}
+
+func TestScaleServiceRejectsFactor(t *testing.T) {
+	svc := usecase.NewScaleService(newTestRepo(t))
+	for _, factor := range []domain.Rational{{}, domain.IntRational(-2)} {
+		_, err := svc.Scale(context.Background(), []usecase.QuantityLine{{Text: "1 cup flour"}}, usecase.ScaleOptions{Factor: factor})
+		if err == nil {
+			t.Errorf("Scale with factor %s succeeded, want a validation error", factor)
+		}
+	}
+}
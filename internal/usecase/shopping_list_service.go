@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeffjlins/okra/internal/domain"
+)
+
+type ShoppingListService struct {
+	repo    domain.UomRepository
+	metrics Metrics
+}
+
+func NewShoppingListService(repo domain.UomRepository, opts ...Option) *ShoppingListService {
+	o := newOptions(opts)
+	return &ShoppingListService{
+		repo:    repo,
+		metrics: o.metrics,
+	}
+}
+
+type ShoppingListOptions struct {
+	System    domain.UomSystem   // Optional: express totals in this system
+	Densities map[string]float64 // Optional: grams per millilitre, keyed by ingredient, used to fold volumes into weights
+	Format    domain.FormatOptions
+}
+
+// ShoppingLineRef points at the recipe line a shopping item was built from
+type ShoppingLineRef struct {
+	Recipe int
+	Line   int
+}
+
+// ShoppingItem is one line of the shopping list. An ingredient whose quantities
+// can't be reconciled (e.g. "2 eggs" and "100 g eggs" without a density) gets one
+// item per incompatible quantity. Ranges are summed bound by bound.
+type ShoppingItem struct {
+	Ingredient   string
+	Quantity     *domain.Quantity // qualitative or nil when none of the lines had an amount ("salt to taste")
+	QuantityText string           // the printed quantity, e.g. "1 ½ cups"
+	Text         string           // the full line, e.g. "1 ½ cups butter"
+	Sources      []ShoppingLineRef
+}
+
+// shoppingBucket accumulates the lines of one ingredient that can be summed together
+type shoppingBucket struct {
+	measureType domain.UomMeasureType // set for pivot buckets (convertible uoms)
+	like        *domain.Uom           // first uom seen, used to pick the output group
+	min, max    domain.Rational       // in the pivot unit for pivot buckets, otherwise in the uom itself
+	approximate bool
+	hasAmount   bool
+	qualitative string // the first qualitative quantity seen, for buckets without an amount
+	sources     []ShoppingLineRef
+}
+
+type shoppingIngredient struct {
+	name    string
+	buckets map[string]*shoppingBucket
+	order   []string
+}
+
+// Aggregate combines the lines of all recipes into a shopping list. Lines are grouped
+// by ingredient; within an ingredient, package uoms are expanded to their package
+// size, convertible amounts are summed in the pivot unit of their measure type,
+// volumes are folded into weights when a density is known, and each total is
+// humanized into the most natural uom.
+func (s *ShoppingListService) Aggregate(ctx context.Context, recipes [][]QuantityLine, opts ShoppingListOptions) ([]ShoppingItem, error) {
+	catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load uom catalog: %w", err)
+	}
+
+	var ingredients []*shoppingIngredient
+	byKey := make(map[string]*shoppingIngredient)
+	for r, lines := range recipes {
+		for l, line := range lines {
+			parsed, err := resolveQuantityLine(catalog, line, opts.Format.Locales)
+			if err != nil {
+				return nil, fmt.Errorf("recipe %d line %d: %w", r+1, l+1, err)
+			}
+			name, q := parsed.Ingredient, parsed.Quantity
+			if q != nil && !q.IsQualitative() && parsed.PackageSize != nil {
+				// "2 (14.5 oz) cans" is bought as 29 oz
+				expanded := q.Scale(parsed.PackageSize.Amount)
+				expanded.Uom = parsed.PackageSize.Uom
+				q = &expanded
+			}
+
+			key := ingredientKey(name)
+			ing, ok := byKey[key]
+			if !ok {
+				ing = &shoppingIngredient{name: name, buckets: make(map[string]*shoppingBucket)}
+				byKey[key] = ing
+				ingredients = append(ingredients, ing)
+			}
+			ing.add(catalog, q, ShoppingLineRef{Recipe: r, Line: l})
+		}
+	}
+
+	densities := make(map[string]float64, len(opts.Densities))
+	for ingredient, density := range opts.Densities {
+		densities[ingredientKey(ingredient)] = density
+	}
+
+	var items []ShoppingItem
+	for _, ing := range ingredients {
+		if density, ok := densities[ingredientKey(ing.name)]; ok && density > 0 {
+			if ing.foldVolumeIntoWeight(domain.FloatRational(density)) {
+				s.metrics.Converted(domain.VOL, domain.WEIGHT)
+			}
+		}
+		ing.foldUnquantified()
+		for _, key := range ing.order {
+			bucket := ing.buckets[key]
+			if bucket == nil {
+				continue
+			}
+			item := ShoppingItem{Ingredient: ing.name, Sources: bucket.sources}
+			if bucket.hasAmount {
+				q := bucket.humanize(catalog, opts.System)
+				if bucket.measureType != "" {
+					s.metrics.HumanizeSelected(q.Uom)
+				}
+				item.Quantity = &q
+				// "1 egg" and "2 eggs" make "3 eggs"
+				item.Ingredient = domain.AgreeWithCount(ing.name, q, opts.Format.Locales)
+			} else if bucket.qualitative != "" {
+				q := domain.QualitativeQuantity(bucket.qualitative)
+				item.Quantity = &q
+			}
+			items = append(items, item)
+		}
+	}
+
+	quantities := make([]*domain.Quantity, len(items))
+	for i := range items {
+		quantities[i] = items[i].Quantity
+	}
+	texts := formatPresent(quantities, opts.Format)
+	for i := range items {
+		items[i].QuantityText = texts[i]
+		items[i].Text = domain.JoinQuantity(texts[i], items[i].Quantity, items[i].Ingredient)
+	}
+	return items, nil
+}
+
+func (ing *shoppingIngredient) add(catalog *domain.UomCatalog, q *domain.Quantity, ref ShoppingLineRef) {
+	key := "none"
+	var lo, hi domain.UomAmount
+	if q != nil && !q.IsQualitative() {
+		lower, upper := q.Bounds()
+		lo, hi = catalog.ExpandPackage(lower), catalog.ExpandPackage(upper)
+		switch {
+		case lo.Uom == nil:
+			key = "count"
+		case lo.Uom.PivotRatio != nil:
+			key = "pivot:" + lo.Uom.MeasureType
+		default:
+			key = "uom:" + lo.Uom.Id
+		}
+	}
+
+	bucket, ok := ing.buckets[key]
+	if !ok {
+		bucket = &shoppingBucket{like: lo.Uom}
+		if lo.Uom != nil && lo.Uom.PivotRatio != nil {
+			bucket.measureType = lo.Uom.MeasureType
+		}
+		ing.buckets[key] = bucket
+		ing.order = append(ing.order, key)
+	}
+	bucket.sources = append(bucket.sources, ref)
+	if q == nil {
+		return
+	}
+	if q.IsQualitative() {
+		if bucket.qualitative == "" {
+			bucket.qualitative = q.Qualitative
+		}
+		return
+	}
+	bucket.hasAmount = true
+	bucket.approximate = bucket.approximate || q.Approximate
+	if pivotLo, ok := domain.ToPivot(lo); ok {
+		pivotHi, _ := domain.ToPivot(hi)
+		bucket.min = bucket.min.Add(pivotLo)
+		bucket.max = bucket.max.Add(pivotHi)
+	} else {
+		bucket.min = bucket.min.Add(lo.Amount)
+		bucket.max = bucket.max.Add(hi.Amount)
+	}
+}
+
+// foldVolumeIntoWeight converts the volume total to grams and adds it to the weight
+// total, reporting whether there was anything to fold. Pivot units are millilitres for
+// volume and grams for weight.
+func (ing *shoppingIngredient) foldVolumeIntoWeight(density domain.Rational) bool {
+	volumeKey, weightKey := "pivot:"+domain.VOL, "pivot:"+domain.WEIGHT
+	volume, weight := ing.buckets[volumeKey], ing.buckets[weightKey]
+	if volume == nil || weight == nil {
+		return false
+	}
+	weight.min = weight.min.Add(volume.min.Mul(density))
+	weight.max = weight.max.Add(volume.max.Mul(density))
+	weight.approximate = weight.approximate || volume.approximate
+	weight.sources = append(weight.sources, volume.sources...)
+	ing.buckets[volumeKey] = nil
+	return true
+}
+
+// foldUnquantified attaches lines without an amount ("butter, for greasing") to the
+// first quantified item of the ingredient, so they don't show up as a separate item
+func (ing *shoppingIngredient) foldUnquantified() {
+	none := ing.buckets["none"]
+	if none == nil {
+		return
+	}
+	for _, key := range ing.order {
+		if bucket := ing.buckets[key]; bucket != nil && key != "none" {
+			bucket.sources = append(bucket.sources, none.sources...)
+			ing.buckets["none"] = nil
+			return
+		}
+	}
+}
+
+func (b *shoppingBucket) humanize(catalog *domain.UomCatalog, system domain.UomSystem) domain.Quantity {
+	if b.measureType == "" {
+		// Plain counts and uoms that can't be converted are listed as summed
+		return domain.Quantity{Min: b.min, Max: b.max, Uom: b.like, Approximate: b.approximate}
+	}
+	uom := b.like
+	if lower, err := domain.HumanizePivot(b.min, b.measureType, b.like, catalog.All(), system); err == nil {
+		uom = lower.Uom
+	}
+	// Nothing to express it in for the system falls back to the uom the recipes used
+	ratio := *uom.PivotRatio
+	return domain.Quantity{
+		Min:         domain.Snap(b.min.Quo(ratio), uom),
+		Max:         domain.Snap(b.max.Quo(ratio), uom),
+		Uom:         uom,
+		Approximate: b.approximate,
+	}
+}
+
+// ingredientKey normalizes an ingredient name for grouping, singularizing each word so
+// "2 eggs" and "1 egg" land on the same line
+func ingredientKey(name string) string {
+	words := strings.Split(domain.Slug(name), "-")
+	for i, w := range words {
+		words[i] = domain.SingularNoun(w)
+	}
+	return strings.Join(words, "-")
+}
@@ -0,0 +1,82 @@
+package usecase_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/jeffjlins/okra/internal/usecase"
+)
+
+func TestShoppingListService(t *testing.T) {
+	svc := usecase.NewShoppingListService(newTestRepo(t))
+	tests := []struct {
+		name      string
+		recipes   [][]string
+		densities map[string]float64
+		want      []string
+	}{
+		{
+			name:    "volumes across units",
+			recipes: [][]string{{"1 cup milk"}, {"8 tbsp milk"}},
+			want:    []string{"1 ½ cups milk"},
+		},
+		{
+			name:    "weights across units",
+			recipes: [][]string{{"1 lb butter"}, {"8 oz butter"}},
+			want:    []string{"1 ½ lb butter"},
+		},
+		{
+			name:      "volume folded into weight with a density",
+			recipes:   [][]string{{"100 g flour"}, {"1 cup flour"}},
+			densities: map[string]float64{"flour": 0.5},
+			want:      []string{"220 g flour"},
+		},
+		{
+			name:    "volume and weight without a density",
+			recipes: [][]string{{"100 g flour"}, {"1 cup flour"}},
+			want:    []string{"100 g flour", "1 cup flour"},
+		},
+		{
+			name:    "counts and weights can't be added",
+			recipes: [][]string{{"2 eggs"}, {"100 g eggs"}},
+			want:    []string{"2 eggs", "100 g eggs"},
+		},
+		{
+			name:    "singular and plural",
+			recipes: [][]string{{"1 egg"}, {"2 eggs"}},
+			want:    []string{"3 eggs"},
+		},
+		{
+			name:    "mass nouns ending in s",
+			recipes: [][]string{{"1 cup molasses", "1 lb asparagus"}, {"2 tbsp molasses", "8 oz asparagus"}},
+			want:    []string{"1 ⅛ cups molasses", "1 ½ lb asparagus"},
+		},
+		{
+			name:    "qualitative quantities",
+			recipes: [][]string{{"salt to taste", "a pinch of pepper"}, {"salt to taste"}},
+			want:    []string{"salt to taste", "a pinch of pepper"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipes := make([][]usecase.QuantityLine, len(tt.recipes))
+			for i, lines := range tt.recipes {
+				for _, line := range lines {
+					recipes[i] = append(recipes[i], usecase.QuantityLine{Text: line})
+				}
+			}
+			items, err := svc.Aggregate(context.Background(), recipes, usecase.ShoppingListOptions{Densities: tt.densities})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, item := range items {
+				got = append(got, item.Text)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Aggregate = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
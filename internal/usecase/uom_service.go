@@ -5,88 +5,153 @@ import (
 	"fmt"
 
 	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/logging"
+	"golang.org/x/text/language"
 )
 
+// UomService manages the uoms of the tenant in the request context. Without a tenant it
+// manages the global catalog. A tenant sees the global catalog merged with its own uoms
+// but can only change its own; the global uoms are read-only for it.
 type UomService struct {
-	repo domain.UomRepository
+	repo    domain.UomRepository
+	newID   domain.UomIDGenerator
+	metrics Metrics
 }
 
-func NewUomService(repo domain.UomRepository) *UomService {
+func NewUomService(repo domain.UomRepository, newID domain.UomIDGenerator, opts ...Option) *UomService {
+	o := newOptions(opts)
 	return &UomService{
-		repo: repo,
+		repo:    repo,
+		newID:   newID,
+		metrics: o.metrics,
 	}
 }
 
-func (s *UomService) CreateUom(ctx context.Context, base *domain.BaseUom) (*domain.Uom, error) {
+func (s *UomService) CreateUom(ctx context.Context, base *domain.BaseUom) (_ *domain.Uom, err error) {
+	ctx, span := tracer.Start(ctx, "UomService.CreateUom")
+	defer endSpan(span, &err)
+
 	if err := base.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
-	uom, err := domain.Create(base)
+	uom, err := domain.Create(base, s.newID)
 	if err != nil {
 		return nil, fmt.Errorf("uom creation failed: %w", err)
 	}
-	existing, err := s.repo.GetByID(ctx, uom.Id)
-	if err != nil {
-		return nil, fmt.Errorf("error checking for existence of uom with id %s: %w", uom.Id, err)
-	}
-	if existing != nil {
-		return nil, fmt.Errorf("uom with id %s already exists", uom.Id)
-	}
 
-	if err := s.repo.Save(ctx, uom); err != nil {
-		return nil, fmt.Errorf("failed to save uom: %w", err)
+	if err := s.repo.Create(ctx, uom); err != nil {
+		return nil, fmt.Errorf("failed to create uom: %w", err)
 	}
+	logging.FromContext(ctx).Info("uom created", "uom_id", uom.Id, "label", uom.Label)
 
 	return uom, nil
 }
 
-func (s *UomService) GetUomByID(ctx context.Context, id string) (*domain.Uom, error) {
+func (s *UomService) GetUomByID(ctx context.Context, id string) (_ *domain.Uom, err error) {
+	ctx, span := tracer.Start(ctx, "UomService.GetUomByID")
+	defer endSpan(span, &err)
+
+	if domain.TenantFromContext(ctx) != "" {
+		catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get uom: %w", err)
+		}
+		if uom := catalog.ByID(id); uom != nil {
+			return uom, nil
+		}
+		return nil, fmt.Errorf("uom with id %s %w", id, domain.ErrNotFound)
+	}
+
 	uom, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get uom: %w", err)
 	}
 	if uom == nil {
-		return nil, fmt.Errorf("uom with id %s not found", id)
+		return nil, fmt.Errorf("uom with id %s %w", id, domain.ErrNotFound)
 	}
 	return uom, nil
 }
 
-func (s *UomService) GetAllUoms(ctx context.Context) ([]*domain.Uom, error) {
-	uoms, err := s.repo.GetAll(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get all uoms: %w", err)
+func (s *UomService) GetUomByLabel(ctx context.Context, label string) (_ *domain.Uom, err error) {
+	ctx, span := tracer.Start(ctx, "UomService.GetUomByLabel")
+	defer endSpan(span, &err)
+
+	if domain.TenantFromContext(ctx) != "" {
+		catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get uom: %w", err)
+		}
+		if uom := catalog.ByLabel(label); uom != nil {
+			return uom, nil
+		}
+		return nil, fmt.Errorf("uom with label %s %w", label, domain.ErrNotFound)
 	}
-	return uoms, nil
-}
 
-func (s *UomService) DeleteUom(ctx context.Context, id string) error {
-	existing, err := s.repo.GetByID(ctx, id)
+	uom, err := s.repo.GetByLabel(ctx, label)
 	if err != nil {
-		return fmt.Errorf("error checking for existence of uom: %w", err)
+		return nil, fmt.Errorf("failed to get uom: %w", err)
+	}
+	if uom == nil {
+		return nil, fmt.Errorf("uom with label %s %w", label, domain.ErrNotFound)
 	}
-	if existing == nil {
-		return fmt.Errorf("uom with id %s not found", id)
+	return uom, nil
+}
+
+// GetAllUoms returns every uom, or only the ones in the system when it isn't empty
+func (s *UomService) GetAllUoms(ctx context.Context, system domain.UomSystem) (_ []*domain.Uom, err error) {
+	ctx, span := tracer.Start(ctx, "UomService.GetAllUoms")
+	defer endSpan(span, &err)
+
+	var uoms []*domain.Uom
+	if domain.TenantFromContext(ctx) != "" {
+		catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get all uoms: %w", err)
+		}
+		uoms = catalog.All()
+	} else {
+		var err error
+		uoms, err = s.repo.GetAll(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get all uoms: %w", err)
+		}
+	}
+	if system == "" {
+		return uoms, nil
+	}
+
+	filtered := make([]*domain.Uom, 0, len(uoms))
+	for _, uom := range uoms {
+		if uom.InSystem(system) {
+			filtered = append(filtered, uom)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *UomService) DeleteUom(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "UomService.DeleteUom")
+	defer endSpan(span, &err)
+
+	if err := s.checkOwned(ctx, id); err != nil {
+		return err
 	}
 
 	if err := s.repo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete uom: %w", err)
 	}
+	logging.FromContext(ctx).Info("uom deleted", "uom_id", id)
 	return nil
 }
 
-func (s *UomService) UpdateUom(ctx context.Context, id string, base *domain.BaseUom) (*domain.Uom, error) {
+func (s *UomService) UpdateUom(ctx context.Context, id string, base *domain.BaseUom) (_ *domain.Uom, err error) {
+	ctx, span := tracer.Start(ctx, "UomService.UpdateUom")
+	defer endSpan(span, &err)
+
 	if err := base.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	existing, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("error checking for existence of uom: %w", err)
-	}
-	if existing == nil {
-		return nil, fmt.Errorf("uom with id %s not found", id)
-	}
-
 	uom := &domain.Uom{
 		BaseUom: *base,
 		Id:      id,
@@ -95,10 +160,69 @@ func (s *UomService) UpdateUom(ctx context.Context, id string, base *domain.Base
 	if err := uom.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	if err := s.checkOwned(ctx, id); err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.Save(ctx, uom); err != nil {
+	if err := s.repo.Update(ctx, uom); err != nil {
 		return nil, fmt.Errorf("failed to update uom: %w", err)
 	}
+	logging.FromContext(ctx).Info("uom updated", "uom_id", uom.Id, "label", uom.Label)
 
 	return uom, nil
 }
+
+// checkOwned fails unless the uom exists in the partition of the tenant in ctx. A
+// tenant asking for a global uom gets a "read-only" error rather than "not found".
+func (s *UomService) checkOwned(ctx context.Context, id string) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error checking for existence of uom: %w", err)
+	}
+	if existing != nil {
+		return nil
+	}
+	if domain.TenantFromContext(ctx) != "" {
+		global, err := s.repo.GetByID(domain.WithTenant(ctx, ""), id)
+		if err != nil {
+			return fmt.Errorf("error checking for existence of uom: %w", err)
+		}
+		if global != nil {
+			logging.FromContext(ctx).Warn("tenant tried to change a global uom", "uom_id", id)
+			return fmt.Errorf("uom with id %s is read-only: it belongs to the global catalog", id)
+		}
+	}
+	return fmt.Errorf("uom with id %s %w", id, domain.ErrNotFound)
+}
+
+// Catalog returns the catalog the tenant in ctx sees, for adapters that resolve many
+// uoms per request and would otherwise look each one up on its own
+func (s *UomService) Catalog(ctx context.Context) (_ *domain.UomCatalog, err error) {
+	ctx, span := tracer.Start(ctx, "UomService.Catalog")
+	defer endSpan(span, &err)
+
+	catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load uom catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// MatchUoms finds the recipe match names of the preferred locales in text using the catalog's prebuilt matcher
+func (s *UomService) MatchUoms(ctx context.Context, text string, locales []language.Tag) (_ []domain.UomMatch, err error) {
+	ctx, span := tracer.Start(ctx, "UomService.MatchUoms")
+	defer endSpan(span, &err)
+
+	catalog, err := domain.LoadUomCatalog(ctx, s.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load uom catalog: %w", err)
+	}
+	matches := catalog.RecipeMatcher(locales).FindAll(text)
+	for _, m := range matches {
+		s.metrics.UomParsed(m.Name, true)
+	}
+	if len(matches) == 0 {
+		s.metrics.UomParsed("", false)
+	}
+	return matches, nil
+}
@@ -0,0 +1,261 @@
+// Package client is a Go client for the okra REST API.
+//
+//	c, err := client.New("https://okra.example.com", client.WithAPIKey(key))
+//	q, err := c.Convert(ctx, client.ConvertRequest{Amount: "1/2", From: "cup", To: "ml"})
+//
+// Every method takes a context that bounds the whole call, retries included. Failed
+// calls return an *Error, which can be matched against ErrNotFound and the other
+// sentinels with errors.Is. A call canceled while waiting to retry returns the last
+// *Error wrapped together with the context's error.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyHeader    = "X-API-Key"
+	tenantHeader    = "X-Tenant-ID"
+	requestIDHeader = "X-Request-ID"
+	userAgent       = "okra-go-client"
+)
+
+// Client calls the okra API. It is safe for concurrent use.
+type Client struct {
+	baseURL        *url.URL
+	httpClient     *http.Client
+	apiKey         string
+	bearerToken    string
+	tenant         string
+	acceptLanguage string
+	userAgent      string
+	retry          RetryPolicy
+}
+
+// RetryPolicy controls how failed calls are retried. Only calls that are safe to
+// repeat are retried, that is all but CreateUom, and only on network errors, 429 and
+// 502, 503 and 504 answers. The delay doubles from MinBackoff up to MaxBackoff, with
+// jitter, unless the server asks for longer with Retry-After.
+type RetryPolicy struct {
+	MaxRetries int // 0 turns retries off
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries three times, waiting up to 2s between attempts
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 3, MinBackoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of http.DefaultClient, e.g. to set
+// a timeout per attempt or a custom transport
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithAPIKey authenticates with a static api key
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithBearerToken authenticates with a JWT
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.bearerToken = token }
+}
+
+// WithTenant acts for the tenant; without it calls act on the global catalog
+func WithTenant(tenant string) Option {
+	return func(c *Client) { c.tenant = tenant }
+}
+
+// WithAcceptLanguage asks for uom names in these locales, e.g. "es, en;q=0.5"
+func WithAcceptLanguage(languages string) Option {
+	return func(c *Client) { c.acceptLanguage = languages }
+}
+
+// WithUserAgent identifies the calling application in the server's logs
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua + " " + userAgent }
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// New returns a client of the API at baseURL, e.g. "http://localhost:8080"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("okra: invalid base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("okra: base url %q must be http or https", baseURL)
+	}
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		userAgent:  userAgent,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// ForTenant returns a copy of the client that acts for the tenant
+func (c *Client) ForTenant(tenant string) *Client {
+	cp := *c
+	cp.tenant = tenant
+	return &cp
+}
+
+// request is one API call
+type request struct {
+	method     string
+	path       string // relative to the base url, may carry a query
+	body       any
+	idempotent bool  // safe to retry
+	accept     []int // statuses decoded into the result rather than turned into an *Error
+}
+
+// do sends the request, retrying it if allowed, and decodes a successful JSON answer
+// into out unless out is nil. It returns the final response's headers.
+func (c *Client) do(ctx context.Context, req request, out any) (http.Header, error) {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("okra: failed to encode request: %w", err)
+		}
+	}
+	u, err := c.baseURL.Parse(c.baseURL.Path + req.path)
+	if err != nil {
+		return nil, fmt.Errorf("okra: invalid path %q: %w", req.path, err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req.method, u.String(), body)
+		if err != nil {
+			if ctx.Err() != nil || !req.idempotent || attempt >= c.retry.MaxRetries {
+				return nil, fmt.Errorf("okra: %s %s: %w", req.method, req.path, err)
+			}
+			if err := c.wait(ctx, attempt, 0); err != nil {
+				return nil, fmt.Errorf("okra: %s %s: %w", req.method, req.path, err)
+			}
+			continue
+		}
+
+		if resp.StatusCode < 300 || slices.Contains(req.accept, resp.StatusCode) {
+			defer resp.Body.Close()
+			if out != nil && resp.StatusCode != http.StatusNoContent {
+				if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+					return nil, fmt.Errorf("okra: failed to decode %s %s response: %w", req.method, req.path, err)
+				}
+			}
+			return resp.Header, nil
+		}
+
+		apiErr := newError(resp)
+		if !req.idempotent || !retryable(resp.StatusCode) || attempt >= c.retry.MaxRetries {
+			return nil, apiErr
+		}
+		if err := c.wait(ctx, attempt, apiErr.RetryAfter); err != nil {
+			// Both match with errors.Is: the context's error and the answer it cut short
+			return nil, fmt.Errorf("%w while waiting to retry: %w", apiErr, err)
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.apiKey != "" {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	if c.tenant != "" {
+		req.Header.Set(tenantHeader, c.tenant)
+	}
+	if c.acceptLanguage != "" {
+		req.Header.Set("Accept-Language", c.acceptLanguage)
+	}
+	return c.httpClient.Do(req)
+}
+
+// wait sleeps before the retry of the given attempt, for retryAfter when the server
+// asked for it and for the backoff otherwise
+func (c *Client) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = c.backoff(attempt)
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// backoff is the exponential delay before the retry of attempt, jittered over its upper half
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.retry.MaxBackoff
+	if shift := uint(attempt); shift < 32 && c.retry.MinBackoff<<shift < c.retry.MaxBackoff {
+		d = c.retry.MinBackoff << shift
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(h); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
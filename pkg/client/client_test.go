@@ -0,0 +1,294 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeffjlins/okra/pkg/client"
+)
+
+// fastRetries keeps the tests quick where the server doesn't ask for a delay
+var fastRetries = client.RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+// newTestClient serves the client's calls with handler, counting the requests
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*client.Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	c, err := client.New(srv.URL, client.WithRetryPolicy(fastRetries))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, &calls
+}
+
+// failTimes answers the first n requests with status and the rest with a uom
+func failTimes(n int32, status int, retryAfter string) http.HandlerFunc {
+	var seen atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		if seen.Add(1) <= n {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"error":"try again"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"cup-id","label":"cup"}`)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		failures   int32
+		wantCalls  int32
+		wantStatus int // 0 when the call should succeed
+	}{
+		{"429 then success", http.StatusTooManyRequests, 2, 3, 0},
+		{"503 then success", http.StatusServiceUnavailable, 1, 2, 0},
+		{"503 past the retries", http.StatusServiceUnavailable, 10, 4, http.StatusServiceUnavailable},
+		{"404 isn't retried", http.StatusNotFound, 10, 1, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, calls := newTestClient(t, failTimes(tt.failures, tt.status, ""))
+			uom, err := c.GetUom(context.Background(), "cup-id")
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("server got %d requests, want %d", got, tt.wantCalls)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil || uom.Label != "cup" {
+					t.Errorf("GetUom = %+v, %v; want cup", uom, err)
+				}
+				return
+			}
+			var apiErr *client.Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+				t.Errorf("GetUom error = %v, want a %d *Error", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	c, calls := newTestClient(t, failTimes(1, http.StatusServiceUnavailable, "1"))
+	start := time.Now()
+	if _, err := c.GetUom(context.Background(), "cup-id"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want the 1s the server asked for", elapsed)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2", got)
+	}
+}
+
+func TestCreateUomIsNotRetried(t *testing.T) {
+	c, calls := newTestClient(t, failTimes(1, http.StatusServiceUnavailable, ""))
+	_, err := c.CreateUom(context.Background(), client.UomFields{Label: "mug"})
+	if !errors.Is(err, client.ErrUnavailable) {
+		t.Errorf("CreateUom error = %v, want ErrUnavailable", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+func TestCancelDuringBackoff(t *testing.T) {
+	c, calls := newTestClient(t, failTimes(10, http.StatusServiceUnavailable, "60"))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetUom(ctx, "cup-id")
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetUom returned after %v, want it to stop when the context ends", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, client.ErrUnavailable) {
+		t.Errorf("GetUom error = %v, want both the context's error and ErrUnavailable", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		sentinel    error
+		want        client.Error
+	}{
+		{
+			name:        "error body",
+			status:      http.StatusNotFound,
+			contentType: "application/json",
+			body:        `{"error":"uom with id x not found"}`,
+			sentinel:    client.ErrNotFound,
+			want:        client.Error{StatusCode: http.StatusNotFound, Message: "uom with id x not found", RequestID: "req-1"},
+		},
+		{
+			name:        "unexpected error",
+			status:      http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        `{"error":"the server hit an unexpected error","request_id":"req-2"}`,
+			sentinel:    client.ErrInternal,
+			want:        client.Error{StatusCode: http.StatusInternalServerError, Message: "the server hit an unexpected error", RequestID: "req-2"},
+		},
+		{
+			name:        "plain text",
+			status:      http.StatusForbidden,
+			contentType: "text/plain",
+			body:        "Forbidden\n",
+			sentinel:    client.ErrForbidden,
+			want:        client.Error{StatusCode: http.StatusForbidden, Message: "Forbidden", RequestID: "req-1"},
+		},
+		{
+			name:        "conflict",
+			status:      http.StatusConflict,
+			contentType: "application/json",
+			body:        `{"error":"uom with label \"mug\" already exists"}`,
+			sentinel:    client.ErrConflict,
+			want:        client.Error{StatusCode: http.StatusConflict, Message: `uom with label "mug" already exists`, RequestID: "req-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Header().Set("X-Request-ID", "req-1")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			})
+			_, err := c.GetUom(context.Background(), "x")
+
+			var apiErr *client.Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetUom error = %v, want an *Error", err)
+			}
+			if *apiErr != tt.want {
+				t.Errorf("GetUom error = %+v, want %+v", *apiErr, tt.want)
+			}
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.sentinel)
+			}
+			if errors.Is(err, client.ErrBadRequest) {
+				t.Errorf("errors.Is(%v, ErrBadRequest) = true", err)
+			}
+		})
+	}
+}
+
+func TestErrorRetryAfter(t *testing.T) {
+	c, _ := newTestClient(t, failTimes(10, http.StatusTooManyRequests, "7"))
+	_, err := c.CreateUom(context.Background(), client.UomFields{Label: "mug"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 7*time.Second || !errors.Is(err, client.ErrRateLimited) {
+		t.Errorf("CreateUom error = %#v, want ErrRateLimited with RetryAfter 7s", err)
+	}
+}
+
+// pagedUoms serves uoms 0 to total-1 a page at a time, linking to the next page the
+// way the server does
+func pagedUoms(total int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		start := 0
+		if after := r.URL.Query().Get("after"); after != "" {
+			n, _ := strconv.Atoi(after)
+			start = n + 1
+		}
+		end := min(start+limit, total)
+		if end < total {
+			q := r.URL.Query()
+			q.Set("after", strconv.Itoa(end-1))
+			w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, q.Encode()))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "[")
+		for i := start; i < end; i++ {
+			if i > start {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"id":"%d","label":"uom %d"}`, i, i)
+		}
+		fmt.Fprint(w, "]")
+	}
+}
+
+func TestUomsFollowsNextLinks(t *testing.T) {
+	c, calls := newTestClient(t, pagedUoms(7))
+	uoms, err := c.ListUoms(context.Background(), client.ListOptions{PageSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uoms) != 7 {
+		t.Fatalf("ListUoms returned %d uoms, want 7", len(uoms))
+	}
+	for i, uom := range uoms {
+		if uom.ID != strconv.Itoa(i) {
+			t.Errorf("uom %d has id %s", i, uom.ID)
+		}
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server got %d requests, want 3", got)
+	}
+}
+
+func TestUomsStopsWhenTheLoopBreaks(t *testing.T) {
+	c, calls := newTestClient(t, pagedUoms(100))
+	n := 0
+	for _, err := range c.Uoms(context.Background(), client.ListOptions{PageSize: 3}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n++; n == 4 {
+			break
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server got %d requests, want the 2 pages the loop read", got)
+	}
+}
+
+func TestUomsEndsWithTheError(t *testing.T) {
+	var seen atomic.Int32
+	paged := pagedUoms(10)
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if seen.Add(1) > 1 {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		paged(w, r)
+	})
+	var ids []string
+	var last error
+	for uom, err := range c.Uoms(context.Background(), client.ListOptions{PageSize: 3}) {
+		if err != nil {
+			last = err
+			continue
+		}
+		ids = append(ids, uom.ID)
+	}
+	if len(ids) != 3 || !errors.Is(last, client.ErrForbidden) {
+		t.Errorf("Uoms yielded %v then %v, want 3 uoms then ErrForbidden", ids, last)
+	}
+}
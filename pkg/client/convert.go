@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Convert expresses the amount in another uom
+func (c *Client) Convert(ctx context.Context, req ConvertRequest) (*Quantity, error) {
+	var q Quantity
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/uom/convert", body: req, idempotent: true}, &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// Humanize re-expresses the amount in the most natural uom, e.g. 48 tsp as 1 cup
+func (c *Client) Humanize(ctx context.Context, req HumanizeRequest) (*Quantity, error) {
+	var q Quantity
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/uom/humanize", body: req, idempotent: true}, &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// Format prints one amount, e.g. "1 ½ cups"
+func (c *Client) Format(ctx context.Context, item FormatItem) (*Quantity, error) {
+	var q Quantity
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/uom/format", body: item, idempotent: true}, &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// FormatList prints the amounts as one list, so uoms sharing a name, like US and
+// imperial cups, are told apart according to disambiguate: auto (the default when
+// empty), always or never
+func (c *Client) FormatList(ctx context.Context, items []FormatItem, disambiguate string) ([]Quantity, error) {
+	body := struct {
+		Items        []FormatItem `json:"items"`
+		Disambiguate string       `json:"disambiguate,omitempty"`
+	}{items, disambiguate}
+	var resp struct {
+		Items []Quantity `json:"items"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/uom/format", body: body, idempotent: true}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinels matched by an *Error of the corresponding status, e.g.
+// errors.Is(err, client.ErrNotFound)
+var (
+	ErrBadRequest   = &Error{StatusCode: http.StatusBadRequest}            // invalid input, e.g. a failed validation or uoms that can't be converted
+	ErrUnauthorized = &Error{StatusCode: http.StatusUnauthorized}          // missing or invalid credentials
+	ErrForbidden    = &Error{StatusCode: http.StatusForbidden}             // the role doesn't allow the call, or the uom is read-only for the tenant
+	ErrNotFound     = &Error{StatusCode: http.StatusNotFound}              // the uom doesn't exist
+	ErrConflict     = &Error{StatusCode: http.StatusConflict}              // the label or a match name is taken
+	ErrTooLarge     = &Error{StatusCode: http.StatusRequestEntityTooLarge} // the request body is over the server's limit
+	ErrRateLimited  = &Error{StatusCode: http.StatusTooManyRequests}       // see Error.RetryAfter
+	ErrInternal     = &Error{StatusCode: http.StatusInternalServerError}   // the server failed; Error.RequestID finds it in the logs
+	ErrUnavailable  = &Error{StatusCode: http.StatusServiceUnavailable}
+)
+
+// Error is an error answer of the API, whose body is {"error": "..."}. Answers from
+// something in front of the server, e.g. a proxy's plain text 502, are read too.
+type Error struct {
+	StatusCode int
+	Message    string        // what went wrong, as the server put it
+	RequestID  string        // the X-Request-ID of the call, to find it in the server's logs
+	RetryAfter time.Duration // how long the server asked to wait, for 429 and 503
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		return fmt.Sprintf("okra: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("okra: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), msg)
+}
+
+// Is matches the sentinel of the same status
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Message == "" && t.StatusCode == e.StatusCode
+}
+
+// errorBody is the body of the server's error answers
+type errorBody struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id"` // set on unexpected errors
+}
+
+// maxErrorBody bounds how much of an error answer is read
+const maxErrorBody = 64 << 10
+
+func newError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(requestIDHeader),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		var b errorBody
+		if json.Unmarshal(body, &b) == nil && b.Error != "" {
+			e.Message = b.Error
+			if b.RequestID != "" {
+				e.RequestID = b.RequestID
+			}
+			return e
+		}
+	}
+	e.Message = strings.TrimSpace(string(body))
+	return e
+}
@@ -0,0 +1,17 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Ready runs the server's readiness check. A server that is up but has a dependency
+// down answers with a Health whose Status is "down" rather than an error.
+func (c *Client) Ready(ctx context.Context) (*Health, error) {
+	var h Health
+	req := request{method: http.MethodGet, path: "/readyz", accept: []int{http.StatusServiceUnavailable}}
+	if _, err := c.do(ctx, req, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) ScaleRecipe(ctx context.Context, req ScaleRequest) (*ScaledRecipe, error) {
+	var scaled ScaledRecipe
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/recipes/scale", body: req, idempotent: true}, &scaled); err != nil {
+		return nil, err
+	}
+	return &scaled, nil
+}
+
+// ShoppingList adds up the ingredients of the recipes
+func (c *Client) ShoppingList(ctx context.Context, req ShoppingListRequest) (*ShoppingList, error) {
+	var list ShoppingList
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/shopping-list", body: req, idempotent: true}, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ParseIngredients splits ingredient lines like "2 cups flour, sifted" into their parts
+func (c *Client) ParseIngredients(ctx context.Context, lines ...string) ([]IngredientLine, error) {
+	var resp struct {
+		Lines []IngredientLine `json:"lines"`
+	}
+	body := map[string][]string{"lines": lines}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/ingredients/parse", body: body, idempotent: true}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Lines, nil
+}
@@ -0,0 +1,201 @@
+package client
+
+// Amount is an exact amount: a decimal like "0.25" or a fraction like "1/3" or "1 ⅓".
+// The server keeps amounts exact, so they're strings rather than floats.
+type Amount string
+
+// UomFields are the fields of a uom that are set when creating or replacing one
+type UomFields struct {
+	Label       string   `json:"label"`
+	Enabled     bool     `json:"enabled"`
+	MeasureType string   `json:"measure_type"` // volume, weight, item or package
+	Group       *string  `json:"group,omitempty"`
+	GroupMin    *Amount  `json:"group_min,omitempty"`
+	GroupMax    *Amount  `json:"group_max,omitempty"`
+	SnapAmount  []Amount `json:"snap_amount"`
+	SnapSelect  *Amount  `json:"snap_select,omitempty"`
+	PivotRatio  *Amount  `json:"pivot_ratio,omitempty"` // how many of the measure type's pivot uom are in one of this uom
+
+	PackageAmount *Amount `json:"package_amount,omitempty"`
+	PackageUom    *string `json:"package_uom,omitempty"`
+
+	MatchNamesRecipe    []string `json:"match_names_recipe"`
+	MatchNamesFoodLabel []string `json:"match_names_food_label"`
+
+	DefaultNameType           string  `json:"default_name_type"` // short or full
+	ShortNameSingular         *string `json:"short_name_singular,omitempty"`
+	ShortNamePlural           *string `json:"short_name_plural,omitempty"`
+	FullNameSingular          *string `json:"full_name_singular,omitempty"`
+	FullNamePlural            *string `json:"full_name_plural,omitempty"`
+	DisambiguatedNameSingular *string `json:"disambiguated_name_singular,omitempty"`
+	DisambiguatedNamePlural   *string `json:"disambiguated_name_plural,omitempty"`
+
+	Info    *UomInfo             `json:"info,omitempty"`
+	Locales map[string]UomLocale `json:"locales,omitempty"` // keyed by BCP 47 tag, e.g. "es"
+}
+
+// Uom is a unit of measure of the catalog
+type Uom struct {
+	UomFields
+	ID string `json:"id"`
+}
+
+type UomInfo struct {
+	Systems   []string `json:"systems"` // us_customary, imperial or metric
+	NameGroup *string  `json:"name_group,omitempty"`
+}
+
+// UomLocale holds a uom's names in one language. Names are keyed by CLDR plural
+// category: zero, one, two, few, many or other.
+type UomLocale struct {
+	ShortNames         map[string]string `json:"short_names,omitempty"`
+	FullNames          map[string]string `json:"full_names,omitempty"`
+	DisambiguatedNames map[string]string `json:"disambiguated_names,omitempty"`
+	MatchNamesRecipe   []string          `json:"match_names_recipe,omitempty"`
+}
+
+// UomMatch is a uom named in a text, at the byte offsets [Start, End)
+type UomMatch struct {
+	Uom   *Uom   `json:"uom"`
+	Name  string `json:"name"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Quantity is an amount of a uom as the server prints it. Exact quantities have an
+// Amount, ranges have Min and Max, and qualitative quantities ("to taste") have none.
+type Quantity struct {
+	Amount      *float64 `json:"amount,omitempty"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Approximate bool     `json:"approximate,omitempty"`
+	Qualitative string   `json:"qualitative,omitempty"`
+	UomID       string   `json:"uom_id,omitempty"`
+	Uom         string   `json:"uom,omitempty"`
+	Text        string   `json:"text"`
+}
+
+// QuantityLine is a recipe line, as free text or as a structured quantity
+type QuantityLine struct {
+	Text        string  `json:"text,omitempty"`
+	Amount      *Amount `json:"amount,omitempty"` // the lower bound of a range
+	Max         *Amount `json:"max,omitempty"`
+	Approximate bool    `json:"approximate,omitempty"`
+	Qualitative string  `json:"qualitative,omitempty"`
+	Uom         string  `json:"uom,omitempty"`
+	Ingredient  string  `json:"ingredient,omitempty"`
+}
+
+// ConvertRequest converts an amount, or a range when Max is set. Uoms are given by
+// id, label or match name. Without To, the most natural uom of System is picked.
+type ConvertRequest struct {
+	Amount      Amount  `json:"amount"`
+	Max         *Amount `json:"max,omitempty"`
+	Approximate bool    `json:"approximate,omitempty"`
+	From        string  `json:"from"`
+	To          string  `json:"to,omitempty"`
+	System      string  `json:"system,omitempty"`
+}
+
+// HumanizeRequest re-expresses an amount in the most natural uom of System, or of its
+// uom's group when System is empty
+type HumanizeRequest struct {
+	Amount      Amount  `json:"amount"`
+	Max         *Amount `json:"max,omitempty"`
+	Approximate bool    `json:"approximate,omitempty"`
+	Uom         string  `json:"uom"`
+	System      string  `json:"system,omitempty"`
+}
+
+// FormatItem is an amount to print
+type FormatItem struct {
+	Amount      Amount  `json:"amount"`
+	Max         *Amount `json:"max,omitempty"`
+	Approximate bool    `json:"approximate,omitempty"`
+	Uom         string  `json:"uom"`
+}
+
+// ScaleRequest scales a recipe by Factor, or from ServingsFrom to ServingsTo servings
+type ScaleRequest struct {
+	Factor       Amount         `json:"factor,omitempty"`
+	ServingsFrom Amount         `json:"servings_from,omitempty"`
+	ServingsTo   Amount         `json:"servings_to,omitempty"`
+	System       string         `json:"system,omitempty"`
+	Disambiguate string         `json:"disambiguate,omitempty"` // auto (default), always or never
+	Lines        []QuantityLine `json:"lines"`
+}
+
+type ScaledRecipe struct {
+	Factor float64      `json:"factor"`
+	Lines  []ScaledLine `json:"lines"`
+}
+
+type ScaledLine struct {
+	Ingredient string    `json:"ingredient"`
+	Original   *Quantity `json:"original"`
+	Scaled     *Quantity `json:"scaled"`
+	Text       string    `json:"text"`
+}
+
+type ShoppingListRequest struct {
+	Recipes      []ShoppingListRecipe `json:"recipes"`
+	System       string               `json:"system,omitempty"`
+	Densities    map[string]float64   `json:"densities,omitempty"` // grams per millilitre, keyed by ingredient
+	Disambiguate string               `json:"disambiguate,omitempty"`
+}
+
+type ShoppingListRecipe struct {
+	Name  string         `json:"name,omitempty"`
+	Lines []QuantityLine `json:"lines"`
+}
+
+type ShoppingList struct {
+	Items []ShoppingItem `json:"items"`
+}
+
+type ShoppingItem struct {
+	Ingredient string            `json:"ingredient"`
+	Quantity   *Quantity         `json:"quantity"`
+	Text       string            `json:"text"`
+	Sources    []ShoppingLineRef `json:"sources"` // the recipe lines the item adds up
+}
+
+// ShoppingLineRef points at a line of a recipe of the request, by index
+type ShoppingLineRef struct {
+	Recipe int `json:"recipe"`
+	Line   int `json:"line"`
+}
+
+type IngredientLine struct {
+	Text        string               `json:"text"`
+	Quantity    *Quantity            `json:"quantity"`
+	PackageSize *Quantity            `json:"package_size,omitempty"`
+	Size        string               `json:"size,omitempty"`
+	Ingredient  string               `json:"ingredient"`
+	Preparation string               `json:"preparation,omitempty"`
+	Notes       string               `json:"notes,omitempty"`
+	Confidence  IngredientConfidence `json:"confidence"`
+}
+
+// IngredientConfidence rates each part of a parsed line from 0 to 1
+type IngredientConfidence struct {
+	Overall    float64 `json:"overall"`
+	Amount     float64 `json:"amount"`
+	Uom        float64 `json:"uom"`
+	Ingredient float64 `json:"ingredient"`
+}
+
+// Health is the answer of a health check
+type Health struct {
+	Status     string            `json:"status"` // up or down
+	Components []ComponentHealth `json:"components,omitempty"`
+}
+
+type ComponentHealth struct {
+	Name      string         `json:"name"`
+	Status    string         `json:"status"`
+	LatencyMs float64        `json:"latency_ms"`
+	Error     string         `json:"error,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
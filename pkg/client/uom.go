@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CreateUom adds a uom to the catalog. It isn't retried, as a lost answer could leave
+// the uom created.
+func (c *Client) CreateUom(ctx context.Context, uom UomFields) (*Uom, error) {
+	var created Uom
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/uom", body: uom}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) GetUom(ctx context.Context, id string) (*Uom, error) {
+	var uom Uom
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/uom/" + url.PathEscape(id), idempotent: true}, &uom); err != nil {
+		return nil, err
+	}
+	return &uom, nil
+}
+
+func (c *Client) GetUomByLabel(ctx context.Context, label string) (*Uom, error) {
+	var uom Uom
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/uom/by-label/" + url.PathEscape(label), idempotent: true}, &uom); err != nil {
+		return nil, err
+	}
+	return &uom, nil
+}
+
+// UpdateUom replaces every field of the uom
+func (c *Client) UpdateUom(ctx context.Context, id string, uom UomFields) (*Uom, error) {
+	var updated Uom
+	if _, err := c.do(ctx, request{method: http.MethodPut, path: "/uom/" + url.PathEscape(id), body: uom, idempotent: true}, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) DeleteUom(ctx context.Context, id string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/uom/" + url.PathEscape(id), idempotent: true}, nil)
+	return err
+}
+
+// ListOptions selects the uoms to list
+type ListOptions struct {
+	System   string // Optional: only the uoms of this system, e.g. "metric"
+	PageSize int    // Optional: uoms per request, at most 1000. 0 fetches the whole list at once
+}
+
+// ListUoms returns every uom, fetching all the pages
+func (c *Client) ListUoms(ctx context.Context, opts ListOptions) ([]*Uom, error) {
+	var uoms []*Uom
+	for uom, err := range c.Uoms(ctx, opts) {
+		if err != nil {
+			return nil, err
+		}
+		uoms = append(uoms, uom)
+	}
+	return uoms, nil
+}
+
+// Uoms iterates over the uoms, fetching a page at a time as the loop needs it. A
+// failed request ends the iteration with its error.
+//
+//	for uom, err := range c.Uoms(ctx, client.ListOptions{PageSize: 100}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) Uoms(ctx context.Context, opts ListOptions) iter.Seq2[*Uom, error] {
+	return func(yield func(*Uom, error) bool) {
+		q := url.Values{}
+		if opts.System != "" {
+			q.Set("system", opts.System)
+		}
+		if opts.PageSize > 0 {
+			q.Set("limit", strconv.Itoa(opts.PageSize))
+		}
+		path := "/uom"
+		if len(q) > 0 {
+			path += "?" + q.Encode()
+		}
+
+		for path != "" {
+			var page []*Uom
+			header, err := c.do(ctx, request{method: http.MethodGet, path: path, idempotent: true}, &page)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, uom := range page {
+				if !yield(uom, nil) {
+					return
+				}
+			}
+			if path, err = c.nextPage(header); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+// nextPage returns the path of the page the Link header points to with rel="next",
+// relative to the base url, or "" on the last page
+func (c *Client) nextPage(header http.Header) (string, error) {
+	for _, link := range header.Values("Link") {
+		for part := range strings.SplitSeq(link, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+			if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+				continue
+			}
+			u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+			if err != nil {
+				return "", fmt.Errorf("okra: invalid next page link %q: %w", target, err)
+			}
+			// The server links by absolute path; drop the base url's path, which do adds back
+			path := strings.TrimPrefix(u.Path, c.baseURL.Path)
+			if u.RawQuery != "" {
+				path += "?" + u.RawQuery
+			}
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// ParseUoms finds the uoms named in the text, in order
+func (c *Client) ParseUoms(ctx context.Context, text string) ([]UomMatch, error) {
+	var resp struct {
+		Matches []UomMatch `json:"matches"`
+	}
+	req := request{method: http.MethodPost, path: "/uom/parse", body: map[string]string{"text": text}, idempotent: true}
+	if _, err := c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Matches, nil
+}
@@ -0,0 +1,68 @@
+package okra_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jeffjlins/okra/pkg/okra"
+)
+
+func Example() {
+	cat, err := okra.Default()
+	if err != nil {
+		log.Fatal(err)
+	}
+	q, err := cat.Convert("1/2", "cup", "ml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(cat.FormatQuantity(q))
+	// Output: 118.29 ml
+}
+
+func ExampleCatalog_Humanize() {
+	cat, err := okra.Default()
+	if err != nil {
+		log.Fatal(err)
+	}
+	q, err := cat.Humanize("48", "tsp", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(cat.FormatQuantity(q))
+	// Output: 1 cup
+}
+
+func ExampleCatalog_ParseIngredients() {
+	cat, err := okra.Default()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, line := range cat.ParseIngredients("2 (14.5 oz) cans diced tomatoes, drained", "salt to taste") {
+		fmt.Printf("%q %q %q\n", cat.FormatQuantity(*line.Quantity), line.Ingredient, line.Preparation)
+	}
+	// Output:
+	// "2 cans" "diced tomatoes" "drained"
+	// "to taste" "salt" ""
+}
+
+func ExampleCatalog_FormatQuantities() {
+	cat, err := okra.Default()
+	if err != nil {
+		log.Fatal(err)
+	}
+	milk, err := cat.Convert("1", "cup", "fl oz")
+	if err != nil {
+		log.Fatal(err)
+	}
+	cheese, err := cat.Convert("1/2", "lb", "oz")
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Both are ounces, so they are told apart when listed together
+	fmt.Println(cat.FormatQuantities([]okra.Quantity{milk, cheese}, okra.DISAMBIGUATE_AUTO))
+	fmt.Println(cat.FormatQuantities([]okra.Quantity{milk, cheese}, okra.DISAMBIGUATE_NEVER))
+	// Output:
+	// [8 US fl oz 8 oz (weight)]
+	// [8 fl oz 8 oz]
+}
@@ -0,0 +1,244 @@
+// Package okra runs the uom catalog in-process, for programs that want to parse,
+// convert and format quantities without calling the server:
+//
+//	cat, err := okra.Default()
+//	q, err := cat.Convert("1/2", "cup", "ml")
+//	fmt.Println(cat.FormatQuantity(q)) // 118.29 ml
+//
+// A Catalog is read-only and safe for concurrent use. It is loaded from the catalog
+// compiled into the package, or from a JSON file of uoms in the form GET /uom returns.
+// Amounts are kept exact and must not be negative.
+package okra
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jeffjlins/okra/internal/adapters/outbound/memory"
+	"github.com/jeffjlins/okra/internal/domain"
+	"github.com/jeffjlins/okra/internal/usecase"
+	"golang.org/x/text/language"
+)
+
+// defaultCatalog holds the common US customary, imperial and metric uoms. Entries
+// leave out the id, so it is derived from the label.
+//
+//go:embed catalog.json
+var defaultCatalog []byte
+
+type (
+	Uom            = domain.Uom
+	Rational       = domain.Rational
+	Quantity       = domain.Quantity
+	UomMatch       = domain.UomMatch
+	IngredientLine = domain.IngredientLine
+	System         = domain.UomSystem
+	Disambiguation = domain.UomDisambiguation
+)
+
+const (
+	US_CUSTOMARY = domain.US_CUSTOMARY
+	IMPERIAL     = domain.IMPERIAL
+	METRIC       = domain.METRIC
+
+	DISAMBIGUATE_AUTO   = domain.DISAMBIGUATE_AUTO
+	DISAMBIGUATE_ALWAYS = domain.DISAMBIGUATE_ALWAYS
+	DISAMBIGUATE_NEVER  = domain.DISAMBIGUATE_NEVER
+)
+
+// Catalog parses, converts and formats quantities with a fixed set of uoms. Uoms are
+// referenced by id, label or recipe match name throughout.
+type Catalog struct {
+	uoms        *usecase.UomService
+	conversions *usecase.ConversionService
+	ingredients *usecase.IngredientService
+	locales     []language.Tag
+}
+
+// Option configures a Catalog
+type Option func(*Catalog)
+
+// WithLocales sets the preferred locales, most preferred first, for recognizing uom
+// names and printing them. English is always the last fallback.
+func WithLocales(locales ...language.Tag) Option {
+	return func(c *Catalog) {
+		c.locales = locales
+	}
+}
+
+// Default returns the catalog compiled into the package
+func Default(opts ...Option) (*Catalog, error) {
+	return Load(bytes.NewReader(defaultCatalog), opts...)
+}
+
+// DefaultUoms returns a fresh copy of the uoms compiled into the package, e.g. to add
+// uoms of your own before calling New
+func DefaultUoms() ([]*Uom, error) {
+	return domain.DecodeUoms(bytes.NewReader(defaultCatalog))
+}
+
+// LoadFile reads a catalog from a JSON file, see Load
+func LoadFile(path string, opts ...Option) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uom catalog: %w", err)
+	}
+	defer f.Close()
+
+	c, err := Load(f, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Load reads a catalog from a JSON array of uoms, in the form GET /uom returns them, so
+// a catalog exported from a server can be used as is. Uoms without an id get one
+// derived from their label.
+func Load(r io.Reader, opts ...Option) (*Catalog, error) {
+	uoms, err := domain.DecodeUoms(r)
+	if err != nil {
+		return nil, err
+	}
+	return New(uoms, opts...)
+}
+
+// New returns a catalog of the uoms. It fails if a uom is invalid or two of them share
+// an id, a label or a match name.
+func New(uoms []*Uom, opts ...Option) (*Catalog, error) {
+	repo, err := memory.NewUomRepository(uoms)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uom catalog: %w", err)
+	}
+	c := &Catalog{
+		uoms:        usecase.NewUomService(repo, domain.LabelIDGenerator),
+		conversions: usecase.NewConversionService(repo),
+		ingredients: usecase.NewIngredientService(repo),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// ParseAmount reads an amount as a recipe writes it: "2", "0.5", "1/3", "1 1/3" or "1 ⅓"
+func ParseAmount(s string) (Rational, error) {
+	return domain.ParseRational(s)
+}
+
+// Uoms returns every uom ordered by id. Callers must not modify them.
+func (c *Catalog) Uoms() []*Uom {
+	return c.catalog().All()
+}
+
+// Uom finds a uom by id, label or recipe match name
+func (c *Catalog) Uom(ref string) (*Uom, error) {
+	if uom := c.catalog().Resolve(ref); uom != nil {
+		return uom, nil
+	}
+	return nil, fmt.Errorf("uom %s not found", ref)
+}
+
+// ParseUoms finds the uoms named in text, e.g. "tbsp" and "cups" in "2 tbsp butter
+// and 3 cups flour"
+func (c *Catalog) ParseUoms(text string) []UomMatch {
+	matches, _ := c.uoms.MatchUoms(context.Background(), text, c.locales)
+	return matches
+}
+
+// ParseIngredients splits each recipe line into quantity, uom, size, ingredient,
+// preparation and notes
+func (c *Catalog) ParseIngredients(lines ...string) []IngredientLine {
+	parsed, _ := c.ingredients.Parse(context.Background(), lines, c.locales)
+	return parsed
+}
+
+// Convert expresses the amount of the from uom in the to uom, e.g. "1/2" cup in ml
+func (c *Catalog) Convert(amount, from, to string) (Quantity, error) {
+	q, err := quantityLine(amount, from)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return c.conversions.Convert(context.Background(), q, to)
+}
+
+// ConvertQuantity expresses a quantity, such as one parsed from an ingredient line, in the to uom
+func (c *Catalog) ConvertQuantity(q Quantity, to string) (Quantity, error) {
+	return c.conversions.Convert(context.Background(), fromQuantity(q), to)
+}
+
+// Humanize re-expresses the amount in the uom a person would use, picked from the
+// system or, when system is empty, from the uom's own group: 48 tsp becomes 1 cup
+func (c *Catalog) Humanize(amount, uom string, system System) (Quantity, error) {
+	q, err := quantityLine(amount, uom)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return c.humanize(q, system)
+}
+
+// HumanizeQuantity is Humanize for a quantity, such as one parsed from an ingredient line
+func (c *Catalog) HumanizeQuantity(q Quantity, system System) (Quantity, error) {
+	return c.humanize(fromQuantity(q), system)
+}
+
+func (c *Catalog) humanize(q usecase.QuantityLine, system System) (Quantity, error) {
+	if _, err := domain.ParseUomSystem(system); err != nil {
+		return Quantity{}, fmt.Errorf("validation failed: %w", err)
+	}
+	return c.conversions.Humanize(context.Background(), q, system)
+}
+
+// Format prints the amount of the uom, e.g. "1 ½ cups"
+func (c *Catalog) Format(amount, uom string) (string, error) {
+	q, err := quantityLine(amount, uom)
+	if err != nil {
+		return "", err
+	}
+	_, texts, err := c.conversions.Format(context.Background(), []usecase.QuantityLine{q}, domain.FormatOptions{Locales: c.locales})
+	if err != nil {
+		return "", err
+	}
+	return texts[0], nil
+}
+
+// FormatQuantity prints the quantity, e.g. "about 2–3 tbsp"
+func (c *Catalog) FormatQuantity(q Quantity) string {
+	return domain.FormatQuantity(q, domain.FormatOptions{Locales: c.locales})
+}
+
+// FormatQuantities prints the quantities as one list, so uoms that share a name group
+// are told apart according to disambiguate
+func (c *Catalog) FormatQuantities(quantities []Quantity, disambiguate Disambiguation) []string {
+	return domain.FormatQuantityList(quantities, domain.FormatOptions{Locales: c.locales, Disambiguate: disambiguate})
+}
+
+// catalog returns the loaded catalog, which the memory repository never fails to hand out
+func (c *Catalog) catalog() *domain.UomCatalog {
+	catalog, _ := c.uoms.Catalog(context.Background())
+	return catalog
+}
+
+func quantityLine(amount, uom string) (usecase.QuantityLine, error) {
+	a, err := domain.ParseRational(amount)
+	if err != nil {
+		return usecase.QuantityLine{}, fmt.Errorf("validation failed: %w", err)
+	}
+	return usecase.QuantityLine{Amount: &a, Uom: uom}, nil
+}
+
+// fromQuantity turns a quantity back into the structured line the use cases take
+func fromQuantity(q Quantity) usecase.QuantityLine {
+	line := usecase.QuantityLine{Approximate: q.Approximate, Qualitative: q.Qualitative}
+	if !q.IsQualitative() {
+		line.Amount, line.Max = &q.Min, &q.Max
+	}
+	if q.Uom != nil {
+		line.Uom = q.Uom.Id
+	}
+	return line
+}
@@ -0,0 +1,146 @@
+package okra_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeffjlins/okra/pkg/okra"
+	"golang.org/x/text/language"
+)
+
+func defaultCatalog(t *testing.T, opts ...okra.Option) *okra.Catalog {
+	t.Helper()
+	cat, err := okra.Default(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cat
+}
+
+func TestConvert(t *testing.T) {
+	cat := defaultCatalog(t)
+	tests := []struct {
+		amount, from, to string
+		want             string // formatted, empty when the conversion must fail
+	}{
+		{"1/2", "cup", "ml", "118.29 ml"},
+		{"1", "cup", "tbsp", "16 tbsp"},
+		{"1 ½", "lb", "oz", "24 oz"},
+		{"1", "kg", "pound", "2.2 lb"},
+		{"0", "cup", "ml", "0 ml"},
+		{"1", "cup", "gram", ""},
+		{"1", "cup", "cubit", ""},
+		{"lots", "cup", "ml", ""},
+		{"-1", "cup", "ml", ""},
+	}
+	for _, tt := range tests {
+		q, err := cat.Convert(tt.amount, tt.from, tt.to)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Convert(%s %s to %s) = %s, want an error", tt.amount, tt.from, tt.to, cat.FormatQuantity(q))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Convert(%s %s to %s) failed: %v", tt.amount, tt.from, tt.to, err)
+			continue
+		}
+		if got := cat.FormatQuantity(q); got != tt.want {
+			t.Errorf("Convert(%s %s to %s) = %s, want %s", tt.amount, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	cat := defaultCatalog(t)
+	tests := []struct {
+		amount, uom string
+		system      okra.System
+		want        string // formatted, empty when humanizing must fail
+	}{
+		{"48", "tsp", "", "1 cup"},
+		{"3", "tsp", "", "1 tbsp"},
+		{"16", "oz", "", "1 lb"},
+		{"1000", "ml", "", "1 l"},
+		{"1", "cup", okra.METRIC, "235 ml"},
+		{"500", "g", okra.US_CUSTOMARY, "1 lb"},
+		{"1", "cup", "martian", ""},
+		{"-1", "cup", "", ""},
+	}
+	for _, tt := range tests {
+		q, err := cat.Humanize(tt.amount, tt.uom, tt.system)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Humanize(%s %s, %q) = %s, want an error", tt.amount, tt.uom, tt.system, cat.FormatQuantity(q))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Humanize(%s %s, %q) failed: %v", tt.amount, tt.uom, tt.system, err)
+			continue
+		}
+		if got := cat.FormatQuantity(q); got != tt.want {
+			t.Errorf("Humanize(%s %s, %q) = %s, want %s", tt.amount, tt.uom, tt.system, got, tt.want)
+		}
+	}
+}
+
+func TestParseIngredients(t *testing.T) {
+	tests := []struct {
+		line                          string
+		locales                       []language.Tag
+		quantity, ingredient, prepped string
+	}{
+		{"2 (14.5 oz) cans diced tomatoes, drained", nil, "2 cans", "diced tomatoes", "drained"},
+		{"1 ½ cups all-purpose flour", nil, "1 ½ cups", "all-purpose flour", ""},
+		{"about 2-3 Tbsp. butter, melted", nil, "about 2–3 tbsp", "butter", "melted"},
+		{"pepper to taste", nil, "to taste", "pepper", ""},
+		{"salt ȺȺȺ to taste", nil, "to taste", "salt ȺȺȺ", ""},
+		{"salt", nil, "", "salt", ""},
+		{"2 cucharadas de aceite", []language.Tag{language.Spanish}, "2 cdas", "aceite", ""},
+	}
+	for _, tt := range tests {
+		cat := defaultCatalog(t, okra.WithLocales(tt.locales...))
+		lines := cat.ParseIngredients(tt.line)
+		if len(lines) != 1 {
+			t.Fatalf("ParseIngredients(%q) returned %d lines", tt.line, len(lines))
+		}
+		line := lines[0]
+		var quantity string
+		if line.Quantity != nil {
+			quantity = cat.FormatQuantity(*line.Quantity)
+		}
+		if quantity != tt.quantity || line.Ingredient != tt.ingredient || line.Preparation != tt.prepped {
+			t.Errorf("ParseIngredients(%q) = %q, %q, %q; want %q, %q, %q", tt.line,
+				quantity, line.Ingredient, line.Preparation, tt.quantity, tt.ingredient, tt.prepped)
+		}
+	}
+}
+
+func TestLoadRejectsInvalidCatalogs(t *testing.T) {
+	tests := map[string]string{
+		"not json":        `{`,
+		"no label":        `[{"enabled": true}]`,
+		"duplicate label": `[{"label": "mug", "measure_type": "item", "snap_amount": ["1"], "default_name_type": "full"}, {"label": "Mug", "measure_type": "item", "snap_amount": ["1"], "default_name_type": "full"}]`,
+	}
+	for name, data := range tests {
+		if _, err := okra.Load(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: Load succeeded, want an error", name)
+		}
+	}
+}
+
+func TestDefaultUomsAreCopies(t *testing.T) {
+	uoms, err := okra.DefaultUoms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uoms[0].Label = "changed"
+	again, err := okra.DefaultUoms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again[0].Label == "changed" {
+		t.Error("DefaultUoms handed out uoms shared between calls")
+	}
+}